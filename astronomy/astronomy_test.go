@@ -0,0 +1,210 @@
+package astronomy
+
+import (
+	"fishfishes_backend/common"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+var berlin = common.Coordinates{Latitude: 52.52, Longitude: 13.405}
+
+func TestSunTimes(t *testing.T) {
+
+	t.Parallel()
+
+	type test struct {
+		coordinates common.Coordinates
+		date        time.Time
+		sunrise     *time.Time
+		sunset      *time.Time
+	}
+
+	cases := map[string]test{
+		"berlin midsummer": {
+			coordinates: berlin,
+			date:        time.Date(2023, 6, 21, 0, 0, 0, 0, time.UTC),
+			sunrise:     timePtr(time.Date(2023, 6, 21, 2, 43, 0, 0, time.UTC)),
+			sunset:      timePtr(time.Date(2023, 6, 21, 19, 33, 0, 0, time.UTC)),
+		},
+		"berlin midwinter": {
+			coordinates: berlin,
+			date:        time.Date(2023, 12, 21, 0, 0, 0, 0, time.UTC),
+			sunrise:     timePtr(time.Date(2023, 12, 21, 7, 15, 0, 0, time.UTC)),
+			sunset:      timePtr(time.Date(2023, 12, 21, 14, 54, 0, 0, time.UTC)),
+		},
+		"polar day": {
+			coordinates: common.Coordinates{Latitude: 78.22, Longitude: 15.65},
+			date:        time.Date(2023, 6, 21, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for name, tc := range cases {
+
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			sunrise, sunset := SunTimes(tc.coordinates, tc.date)
+			assertNear(t, tc.sunrise, sunrise)
+			assertNear(t, tc.sunset, sunset)
+		})
+	}
+}
+
+func TestPhase(t *testing.T) {
+
+	t.Parallel()
+
+	type test struct {
+		at           time.Time
+		name         string
+		illumination float64
+	}
+
+	cases := map[string]test{
+		"new moon": {
+			at:           time.Date(2023, 8, 16, 9, 38, 0, 0, time.UTC),
+			name:         "New Moon",
+			illumination: 0,
+		},
+		"first quarter": {
+			at:           time.Date(2023, 8, 24, 9, 57, 0, 0, time.UTC),
+			name:         "First Quarter",
+			illumination: 0.5,
+		},
+		"full moon": {
+			at:           time.Date(2023, 8, 31, 1, 35, 0, 0, time.UTC),
+			name:         "Full Moon",
+			illumination: 1,
+		},
+		"waning crescent": {
+			at:           time.Date(2023, 9, 12, 12, 0, 0, 0, time.UTC),
+			name:         "Waning Crescent",
+			illumination: 0.05,
+		},
+	}
+
+	for name, tc := range cases {
+
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			phase := Phase(tc.at)
+			assert.Equal(t, tc.name, phase.Name)
+			assert.InDelta(t, tc.illumination, phase.Illumination, 0.05)
+		})
+	}
+}
+
+func TestNewForecast(t *testing.T) {
+
+	forecast := NewForecast(berlin, time.Date(2023, 8, 31, 0, 0, 0, 0, time.UTC))
+
+	assert.Equal(t, "2023-08-31", forecast.Date)
+	assert.Equal(t, "Full Moon", forecast.MoonPhase.Name)
+
+	// a full moon rises around sunset
+	if assert.NotNil(t, forecast.Moonrise) && assert.NotNil(t, forecast.Sunset) {
+		assert.InDelta(t, 0, forecast.Moonrise.Sub(*forecast.Sunset).Hours(), 1)
+	}
+
+	// two major periods around the transits, the minor ones around moonrise and moonset
+	majors := 0
+	for i, period := range forecast.Periods {
+		if period.Type == common.SolunarMajor {
+			majors++
+			assert.Equal(t, majorPeriod, period.End.Sub(period.Start))
+		}
+		if i > 0 {
+			assert.False(t, period.Start.Before(forecast.Periods[i-1].Start), "periods must be sorted")
+		}
+	}
+	assert.Equal(t, 2, majors)
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func assertNear(t *testing.T, expected *time.Time, actual *time.Time) {
+	if expected == nil {
+		assert.Nil(t, actual)
+		return
+	}
+	if assert.NotNil(t, actual) {
+		assert.WithinDuration(t, *expected, *actual, 3*time.Minute)
+	}
+}
+
+func TestDayPart(t *testing.T) {
+
+	t.Parallel()
+
+	// sunrise 04:43 and sunset 21:33 CEST, so each third of the daylight lasts 5h37m
+	location, err := time.LoadLocation("Europe/Berlin")
+	if !assert.NoError(t, err) {
+		return
+	}
+	at := func(hour, minute int) time.Time {
+		return time.Date(2023, 6, 21, hour, minute, 0, 0, location)
+	}
+
+	type test struct {
+		coordinates common.Coordinates
+		at          time.Time
+		expected    string
+	}
+
+	cases := map[string]test{
+		"before dawn": {coordinates: berlin, at: at(3, 30), expected: common.TimeNight},
+		"dawn":        {coordinates: berlin, at: at(4, 0), expected: common.TimeMorning},
+		"morning":     {coordinates: berlin, at: at(9, 0), expected: common.TimeMorning},
+		"noon":        {coordinates: berlin, at: at(13, 0), expected: common.TimeDay},
+		"afternoon":   {coordinates: berlin, at: at(17, 0), expected: common.TimeAfternoon},
+		"dusk":        {coordinates: berlin, at: at(22, 0), expected: common.TimeAfternoon},
+		"night":       {coordinates: berlin, at: at(23, 30), expected: common.TimeNight},
+		"polar day":   {coordinates: common.Coordinates{Latitude: 78.22, Longitude: 15.65}, at: at(1, 0), expected: common.TimeDay},
+		"polar night": {coordinates: common.Coordinates{Latitude: 78.22, Longitude: 15.65}, at: time.Date(2023, 12, 21, 12, 0, 0, 0, location), expected: common.TimeNight},
+	}
+
+	for name, tc := range cases {
+
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, DayPart(tc.coordinates, tc.at))
+		})
+	}
+}
+
+func TestDayParts(t *testing.T) {
+
+	t.Parallel()
+
+	location, err := time.LoadLocation("Europe/Berlin")
+	if !assert.NoError(t, err) {
+		return
+	}
+	date := time.Date(2023, 6, 21, 12, 0, 0, 0, location)
+
+	spans := DayParts(berlin, date)
+	var parts []string
+	for _, span := range spans {
+		parts = append(parts, span.Part)
+		for at := span.Start; at.Before(span.End); at = at.Add(20 * time.Minute) {
+			assert.Equal(t, span.Part, DayPart(berlin, at), at.String())
+		}
+	}
+	assert.Equal(t, []string{common.TimeNight, common.TimeMorning, common.TimeDay, common.TimeAfternoon, common.TimeNight}, parts)
+	assert.Equal(t, time.Date(2023, 6, 21, 0, 0, 0, 0, location), spans[0].Start)
+	assert.Equal(t, time.Date(2023, 6, 22, 0, 0, 0, 0, location), spans[len(spans)-1].End)
+
+	polar := DayParts(common.Coordinates{Latitude: 78.22, Longitude: 15.65}, date)
+	assert.Equal(t, []DaySpan{{Part: common.TimeDay, Start: spans[0].Start, End: spans[len(spans)-1].End}}, polar)
+}
@@ -0,0 +1,95 @@
+package astronomy
+
+import (
+	"fishfishes_backend/common"
+	"time"
+)
+
+// step is the sampling interval used to search for events. The times in between are interpolated linearly.
+const step = 10 * time.Minute
+
+// body returns the position of a celestial body and the altitude its center has at rise and set,
+// which accounts for refraction, the radius of the disc and, for the moon, the parallax.
+type body func(d float64) (equatorial, float64)
+
+func sun(d float64) (equatorial, float64) {
+	return sunPosition(d), -0.833
+}
+
+func moon(d float64) (equatorial, float64) {
+	position, parallax := moonPosition(d)
+	return position, 0.7275*parallax - 0.5667
+}
+
+// events holds the first occurrence of each event within a time range, nil if it does not occur.
+type events struct {
+	rise      *time.Time
+	set       *time.Time
+	transit   *time.Time // upper transit, the body is highest
+	underfoot *time.Time // lower transit, the body is lowest
+}
+
+type sample struct {
+	at        time.Time
+	altitude  float64 // altitude above the horizon altitude of the body
+	hourAngle float64
+}
+
+func sampleAt(b body, coordinates common.Coordinates, at time.Time) sample {
+	d := daysSinceJ2000(at)
+	position, horizon := b(d)
+	ha := hourAngle(d, coordinates.Longitude, position)
+	return sample{
+		at:        at,
+		altitude:  altitude(coordinates.Latitude, ha, position) - horizon,
+		hourAngle: ha,
+	}
+}
+
+// findEvents searches the events of a body in [start, end).
+func findEvents(b body, coordinates common.Coordinates, start, end time.Time) events {
+
+	var result events
+	prev := sampleAt(b, coordinates, start)
+
+	for at := start.Add(step); at.Before(end.Add(step)); at = at.Add(step) {
+		cur := sampleAt(b, coordinates, at)
+
+		switch {
+		case result.rise == nil && prev.altitude < 0 && cur.altitude >= 0:
+			result.rise = interpolate(prev.at, prev.altitude, cur.altitude, 0)
+		case result.set == nil && prev.altitude >= 0 && cur.altitude < 0:
+			result.set = interpolate(prev.at, prev.altitude, cur.altitude, 0)
+		}
+
+		switch {
+		case result.transit == nil && prev.hourAngle < 0 && cur.hourAngle >= 0 && cur.hourAngle-prev.hourAngle < 180:
+			result.transit = interpolate(prev.at, prev.hourAngle, cur.hourAngle, 0)
+		case result.underfoot == nil && prev.hourAngle > 0 && cur.hourAngle <= 0 && prev.hourAngle-cur.hourAngle > 180:
+			result.underfoot = interpolate(prev.at, prev.hourAngle, cur.hourAngle+360, 180)
+		}
+
+		prev = cur
+	}
+
+	result.rise = within(result.rise, start, end)
+	result.set = within(result.set, start, end)
+	result.transit = within(result.transit, start, end)
+	result.underfoot = within(result.underfoot, start, end)
+
+	return result
+}
+
+// interpolate returns the time between from and from+step at which the value crosses target.
+func interpolate(from time.Time, a, b, target float64) *time.Time {
+	fraction := (target - a) / (b - a)
+	at := from.Add(time.Duration(fraction * float64(step))).Truncate(time.Second)
+	return &at
+}
+
+func within(at *time.Time, start, end time.Time) *time.Time {
+	if at == nil || at.Before(start) || !at.Before(end) {
+		return nil
+	}
+	return at
+}
@@ -0,0 +1,206 @@
+package astronomy
+
+import (
+	"fishfishes_backend/common"
+	"fishfishes_backend/common/utils"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	synodicMonth float64 = 29.530588 // mean days from new moon to new moon
+
+	majorPeriod = 2 * time.Hour // centered on the upper and lower transit of the moon
+	minorPeriod = 1 * time.Hour // centered on moonrise and moonset
+)
+
+var phaseNames = []string{
+	"New Moon", "Waxing Crescent", "First Quarter", "Waxing Gibbous",
+	"Full Moon", "Waning Gibbous", "Last Quarter", "Waning Crescent",
+}
+
+// SunTimes returns sunrise and sunset at the coordinates on the day of date in the location of date.
+// A result is nil, if the sun does not rise or set on that day (polar day or night).
+func SunTimes(coordinates common.Coordinates, date time.Time) (*time.Time, *time.Time) {
+	start, end := day(date)
+	e := findEvents(sun, coordinates, start, end)
+	return inLocation(e.rise, date.Location()), inLocation(e.set, date.Location())
+}
+
+// MoonTimes returns moonrise and moonset at the coordinates on the day of date in the location of date.
+// A result is nil, if the moon does not rise or set on that day, which happens about once a month.
+func MoonTimes(coordinates common.Coordinates, date time.Time) (*time.Time, *time.Time) {
+	start, end := day(date)
+	e := findEvents(moon, coordinates, start, end)
+	return inLocation(e.rise, date.Location()), inLocation(e.set, date.Location())
+}
+
+// Phase returns the moon phase at the given time.
+func Phase(at time.Time) common.MoonPhase {
+
+	d := daysSinceJ2000(at)
+	moonLongitude, _, _ := moonEcliptic(d)
+	elongation := normalize(moonLongitude - sunLongitude(d))
+
+	return common.MoonPhase{
+		Name:         phaseNames[int(math.Floor(normalize(elongation+22.5)/45))%len(phaseNames)],
+		Illumination: utils.ScaleHalfUp((1-math.Cos(toRadians(elongation)))/2, 3),
+		Age:          utils.ScaleHalfUp(elongation/360*synodicMonth, 1),
+	}
+}
+
+// SolunarPeriods returns the major periods around the moon transits and the minor periods around
+// moonrise and moonset on the day of date, sorted by their start.
+func SolunarPeriods(coordinates common.Coordinates, date time.Time) []common.SolunarPeriod {
+	start, end := day(date)
+	return solunarPeriods(findEvents(moon, coordinates, start, end), date.Location())
+}
+
+// NewForecast computes sun and moon times, moon phase and solunar periods at the coordinates
+// for the day of date in the location of date.
+func NewForecast(coordinates common.Coordinates, date time.Time) common.Forecast {
+
+	start, end := day(date)
+	location := date.Location()
+	sunEvents := findEvents(sun, coordinates, start, end)
+	moonEvents := findEvents(moon, coordinates, start, end)
+
+	return common.Forecast{
+		Date:      start.Format("2006-01-02"),
+		Sunrise:   inLocation(sunEvents.rise, location),
+		Sunset:    inLocation(sunEvents.set, location),
+		Moonrise:  inLocation(moonEvents.rise, location),
+		Moonset:   inLocation(moonEvents.set, location),
+		MoonPhase: Phase(start.Add(12 * time.Hour)),
+		Periods:   solunarPeriods(moonEvents, location),
+	}
+}
+
+func solunarPeriods(e events, location *time.Location) []common.SolunarPeriod {
+
+	periods := make([]common.SolunarPeriod, 0)
+	add := func(at *time.Time, periodType string, duration time.Duration) {
+		if at == nil {
+			return
+		}
+		center := at.In(location)
+		periods = append(periods, common.SolunarPeriod{
+			Type:  periodType,
+			Start: center.Add(-duration / 2),
+			End:   center.Add(duration / 2),
+		})
+	}
+
+	add(e.transit, common.SolunarMajor, majorPeriod)
+	add(e.underfoot, common.SolunarMajor, majorPeriod)
+	add(e.rise, common.SolunarMinor, minorPeriod)
+	add(e.set, common.SolunarMinor, minorPeriod)
+
+	sort.Slice(periods, func(i, j int) bool {
+		return periods[i].Start.Before(periods[j].Start)
+	})
+
+	return periods
+}
+
+// day returns the start and end of the day of date in the location of date.
+func day(date time.Time) (time.Time, time.Time) {
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	return start, start.AddDate(0, 0, 1)
+}
+
+func inLocation(at *time.Time, location *time.Location) *time.Time {
+	if at == nil {
+		return nil
+	}
+	local := at.In(location)
+	return &local
+}
+
+// twilight is the time before sunrise and after sunset which still counts as morning and afternoon.
+const twilight = time.Hour
+
+// DayPart returns the part of the day the given time falls into at the coordinates. The daylight between
+// sunrise and sunset is split into thirds for morning, day and afternoon, the twilight before sunrise counts
+// as morning and the one after sunset as afternoon. On polar days and nights the sun altitude decides.
+func DayPart(coordinates common.Coordinates, at time.Time) string {
+	sunrise, sunset := SunTimes(coordinates, at)
+	return dayPart(coordinates, sunrise, sunset, at)
+}
+
+// DaySpan is a part of the day from start until before end.
+type DaySpan struct {
+	Part  string
+	Start time.Time
+	End   time.Time
+}
+
+// DayParts returns the parts of the day of date at the coordinates in the location of date, in order and
+// covering the whole day. The sun is only looked up once, unlike with DayPart for every time of the day.
+func DayParts(coordinates common.Coordinates, date time.Time) []DaySpan {
+
+	start, end := day(date)
+	sunrise, sunset := SunTimes(coordinates, date)
+
+	// the part of the day only changes at the bounds, or with the sun altitude if the sun does not rise and set
+	var bounds []time.Time
+	if sunrise == nil || sunset == nil || !sunset.After(*sunrise) {
+		for at := start.Add(step); at.Before(end); at = at.Add(step) {
+			bounds = append(bounds, at)
+		}
+	} else {
+		third := sunset.Sub(*sunrise) / 3
+		bounds = []time.Time{sunrise.Add(-twilight), sunrise.Add(third), sunrise.Add(2 * third), sunset.Add(twilight)}
+	}
+
+	var spans []DaySpan
+	from := start
+	for _, to := range append(bounds, end) {
+		if to.After(end) {
+			to = end
+		}
+		if !to.After(from) {
+			continue
+		}
+		part := dayPart(coordinates, sunrise, sunset, from.Add(to.Sub(from)/2))
+		if len(spans) > 0 && spans[len(spans)-1].Part == part {
+			spans[len(spans)-1].End = to
+		} else {
+			spans = append(spans, DaySpan{Part: part, Start: from, End: to})
+		}
+		from = to
+	}
+	return spans
+}
+
+// dayPart returns the part of the day of the time with the given sunrise and sunset of its day.
+func dayPart(coordinates common.Coordinates, sunrise *time.Time, sunset *time.Time, at time.Time) string {
+
+	if sunrise == nil || sunset == nil || !sunset.After(*sunrise) {
+		if SunAltitude(coordinates, at) > 0 {
+			return common.TimeDay
+		}
+		return common.TimeNight
+	}
+
+	third := sunset.Sub(*sunrise) / 3
+	switch {
+	case at.Before(sunrise.Add(-twilight)):
+		return common.TimeNight
+	case at.Before(sunrise.Add(third)):
+		return common.TimeMorning
+	case at.Before(sunrise.Add(2 * third)):
+		return common.TimeDay
+	case at.Before(sunset.Add(twilight)):
+		return common.TimeAfternoon
+	}
+	return common.TimeNight
+}
+
+// SunAltitude returns the altitude of the sun above the horizon at the coordinates in degrees.
+func SunAltitude(coordinates common.Coordinates, at time.Time) float64 {
+	d := daysSinceJ2000(at)
+	position := sunPosition(d)
+	return altitude(coordinates.Latitude, hourAngle(d, coordinates.Longitude, position), position)
+}
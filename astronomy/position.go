@@ -0,0 +1,116 @@
+// Package astronomy computes the positions of sun and moon and derived events like sunrise, moonset,
+// moon phase and solunar periods. It uses low precision formulas which are accurate to about a minute
+// for the sun and a few minutes for the moon, which is plenty for planning a fishing trip.
+package astronomy
+
+import (
+	"math"
+	"time"
+)
+
+// j2000 is the reference epoch 2000-01-01 12:00 TT, used as UTC here.
+var j2000 = time.Date(2000, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// equatorial is a position on the celestial sphere in degrees.
+type equatorial struct {
+	rightAscension float64
+	declination    float64
+}
+
+// daysSinceJ2000 returns the fractional days between the reference epoch and t.
+func daysSinceJ2000(t time.Time) float64 {
+	return t.Sub(j2000).Hours() / 24
+}
+
+// sunLongitude returns the apparent ecliptic longitude of the sun in degrees.
+func sunLongitude(d float64) float64 {
+	l := normalize(280.460 + 0.9856474*d)
+	g := toRadians(normalize(357.528 + 0.9856003*d))
+	return normalize(l + 1.915*math.Sin(g) + 0.020*math.Sin(2*g))
+}
+
+func sunPosition(d float64) equatorial {
+	return toEquatorial(sunLongitude(d), 0, d)
+}
+
+// moonEcliptic returns the ecliptic longitude, latitude and horizontal parallax of the moon in degrees.
+func moonEcliptic(d float64) (float64, float64, float64) {
+
+	t := d / 36525
+	sin := func(degrees float64) float64 { return math.Sin(toRadians(degrees)) }
+	cos := func(degrees float64) float64 { return math.Cos(toRadians(degrees)) }
+
+	longitude := 218.32 + 481267.881*t +
+		6.29*sin(135.0+477198.87*t) - 1.27*sin(259.3-413335.36*t) +
+		0.66*sin(235.7+890534.22*t) + 0.21*sin(269.9+954397.74*t) -
+		0.19*sin(357.5+35999.05*t) - 0.11*sin(186.5+966404.03*t)
+
+	latitude := 5.13*sin(93.3+483202.02*t) + 0.28*sin(228.2+960400.89*t) -
+		0.28*sin(318.3+6003.15*t) - 0.17*sin(217.6-407332.21*t)
+
+	parallax := 0.9508 + 0.0518*cos(135.0+477198.87*t) + 0.0095*cos(259.3-413335.36*t) +
+		0.0078*cos(235.7+890534.22*t) + 0.0028*cos(269.9+954397.74*t)
+
+	return normalize(longitude), latitude, parallax
+}
+
+func moonPosition(d float64) (equatorial, float64) {
+	longitude, latitude, parallax := moonEcliptic(d)
+	return toEquatorial(longitude, latitude, d), parallax
+}
+
+// toEquatorial converts ecliptic coordinates in degrees to equatorial coordinates.
+func toEquatorial(longitude, latitude, d float64) equatorial {
+
+	epsilon := toRadians(23.439 - 0.0000004*d)
+	lambda := toRadians(longitude)
+	beta := toRadians(latitude)
+
+	ra := math.Atan2(math.Sin(lambda)*math.Cos(epsilon)-math.Tan(beta)*math.Sin(epsilon), math.Cos(lambda))
+	dec := math.Asin(math.Sin(beta)*math.Cos(epsilon) + math.Cos(beta)*math.Sin(epsilon)*math.Sin(lambda))
+
+	return equatorial{
+		rightAscension: normalize(toDegrees(ra)),
+		declination:    toDegrees(dec),
+	}
+}
+
+// hourAngle returns the local hour angle of a position in degrees within (-180, 180].
+func hourAngle(d float64, longitude float64, position equatorial) float64 {
+	siderealTime := 280.46061837 + 360.98564736629*d + longitude
+	return wrap(siderealTime - position.rightAscension)
+}
+
+// altitude returns the geocentric altitude of a position above the horizon in degrees.
+func altitude(latitude float64, hourAngle float64, position equatorial) float64 {
+	phi := toRadians(latitude)
+	dec := toRadians(position.declination)
+	h := toRadians(hourAngle)
+	return toDegrees(math.Asin(math.Sin(phi)*math.Sin(dec) + math.Cos(phi)*math.Cos(dec)*math.Cos(h)))
+}
+
+// normalize maps an angle in degrees to [0, 360).
+func normalize(degrees float64) float64 {
+	degrees = math.Mod(degrees, 360)
+	if degrees < 0 {
+		degrees += 360
+	}
+	return degrees
+}
+
+// wrap maps an angle in degrees to (-180, 180].
+func wrap(degrees float64) float64 {
+	degrees = normalize(degrees)
+	if degrees > 180 {
+		degrees -= 360
+	}
+	return degrees
+}
+
+func toRadians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
+
+func toDegrees(radians float64) float64 {
+	return radians * 180 / math.Pi
+}
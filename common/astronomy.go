@@ -0,0 +1,30 @@
+package common
+
+import "time"
+
+const (
+	SolunarMajor string = "major"
+	SolunarMinor string = "minor"
+)
+
+type MoonPhase struct {
+	Name         string  `json:"name"`
+	Illumination float64 `json:"illumination"` // illuminated fraction of the disc, 0 to 1
+	Age          float64 `json:"age"`          // days since new moon
+}
+
+type SolunarPeriod struct {
+	Type  string    `json:"type"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+type Forecast struct {
+	Date      string          `json:"date"`
+	Sunrise   *time.Time      `json:"sunrise"`
+	Sunset    *time.Time      `json:"sunset"`
+	Moonrise  *time.Time      `json:"moonrise"`
+	Moonset   *time.Time      `json:"moonset"`
+	MoonPhase MoonPhase       `json:"moonPhase"`
+	Periods   []SolunarPeriod `json:"periods"`
+}
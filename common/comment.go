@@ -0,0 +1,141 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const MaxCommentLength int = 1000
+
+// The types of reactions.
+const (
+	ReactionLike    string = "like"
+	ReactionLove    string = "love"
+	ReactionWow     string = "wow"
+	ReactionRespect string = "respect"
+)
+
+var reactionTypes = []string{ReactionLike, ReactionLove, ReactionWow, ReactionRespect}
+
+// Target is a spot of a user or a catch at it, which other users can comment on and react to. CatchId is
+// empty for the spot itself.
+type Target struct {
+	OwnerId string `json:"ownerId" bson:"ownerId"`
+	SpotId  string `json:"spotId" bson:"spotId"`
+	CatchId string `json:"catchId,omitempty" bson:"catchId"`
+}
+
+// Comment is a comment on a target. Comments are threaded one level deep: a reply has the id of the comment
+// it answers as ParentId, and replies can not be answered. Flagged comments are held back by the moderation
+// and only shown to their author and the owner of the target.
+type Comment struct {
+	Target     `bson:",inline"`
+	Id         string     `json:"id" bson:"_id"`
+	ParentId   string     `json:"parentId,omitempty" bson:"parentId"`
+	AuthorId   string     `json:"authorId" bson:"authorId"`
+	AuthorName string     `json:"authorName" bson:"-"`
+	Text       string     `json:"text" bson:"text"`
+	Flagged    bool       `json:"flagged,omitempty" bson:"flagged,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt" bson:"createdAt"`
+	EditedAt   *time.Time `json:"editedAt,omitempty" bson:"editedAt,omitempty"`
+	Replies    []Comment  `json:"replies,omitempty" bson:"-"`
+}
+
+// Comments is a page of the comments on a target, the oldest first. NextCursor is empty on the last page.
+type Comments struct {
+	Items      []Comment `json:"items"`
+	NextCursor string    `json:"nextCursor,omitempty"`
+}
+
+// Reaction is the reaction of a user to a target. A user has at most one reaction per target.
+type Reaction struct {
+	Target    `bson:",inline"`
+	Id        string    `json:"id" bson:"_id"`
+	UserId    string    `json:"userId" bson:"userId"`
+	Type      string    `json:"type" bson:"type"`
+	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
+}
+
+// ReactionCount is the number of reactions of a type to a target.
+type ReactionCount struct {
+	SpotId  string `bson:"spotId"`
+	CatchId string `bson:"catchId"`
+	Type    string `bson:"type"`
+	Count   int    `bson:"count"`
+}
+
+// ReactionId returns the id of the reaction of the user to the target.
+func ReactionId(target Target, userId string) string {
+	return strings.Join([]string{target.OwnerId, target.SpotId, target.CatchId, userId}, "/")
+}
+
+// IsReaction tells whether the value is one of the reaction types.
+func IsReaction(value string) bool {
+	for _, t := range reactionTypes {
+		if t == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateCommentText checks that the text of a comment is not empty and not too long.
+func ValidateCommentText(text string) ValidationErrors {
+	text = strings.TrimSpace(text)
+	if len(text) == 0 {
+		return ValidationErrors{{Field: "text", Code: ErrorRequired, Message: "text must not be empty"}}
+	}
+	if utf8.RuneCountInString(text) > MaxCommentLength {
+		return ValidationErrors{{Field: "text", Code: ErrorTooLong, Message: fmt.Sprintf("text must not be longer than %d characters", MaxCommentLength)}}
+	}
+	return nil
+}
+
+// ValidateParent checks that the comment replies to a comment on the same target which is not a reply itself.
+// parent is nil, if the comment to reply to does not exist.
+func (c Comment) ValidateParent(parent *Comment) ValidationErrors {
+	if len(c.ParentId) == 0 {
+		return nil
+	}
+	if parent == nil || parent.Target != c.Target {
+		return ValidationErrors{{Field: "parentId", Code: ErrorInvalid, Message: fmt.Sprintf("comment '%s' does not exist", c.ParentId)}}
+	}
+	if len(parent.ParentId) > 0 {
+		return ValidationErrors{{Field: "parentId", Code: ErrorInvalid, Message: "replies can not be answered"}}
+	}
+	return nil
+}
+
+// CanDelete tells whether the user may delete the comment: its author and the owner of the target can.
+func (c Comment) CanDelete(userId string) bool {
+	return userId == c.AuthorId || userId == c.OwnerId
+}
+
+// VisibleTo tells whether the user can see the comment, which for flagged comments are only its author and
+// the owner of the target.
+func (c Comment) VisibleTo(userId string) bool {
+	return !c.Flagged || c.CanDelete(userId)
+}
+
+// AttachReactions sets the reaction counts of the spots of the owner and of their catches.
+func AttachReactions(spots []Fish_spot, counts []ReactionCount) {
+
+	type key struct{ spot, catch string }
+	byTarget := map[key]map[string]int{}
+	for _, count := range counts {
+		k := key{count.SpotId, count.CatchId}
+		if byTarget[k] == nil {
+			byTarget[k] = map[string]int{}
+		}
+		byTarget[k][count.Type] += count.Count
+	}
+
+	for i := range spots {
+		spots[i].Reactions = byTarget[key{spots[i].Id, ""}]
+		for j := range spots[i].Catches {
+			spots[i].Catches[j].Reactions = byTarget[key{spots[i].Id, spots[i].Catches[j].Id}]
+		}
+	}
+}
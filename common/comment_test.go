@@ -0,0 +1,87 @@
+package common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestCommentValidateParent(t *testing.T) {
+
+	t.Parallel()
+
+	target := Target{OwnerId: "anna", SpotId: "lake"}
+	comment := Comment{Target: target, Id: "c1"}
+	reply := Comment{Target: target, Id: "c2", ParentId: "c1"}
+
+	assert.Empty(t, comment.ValidateParent(nil))
+	assert.Empty(t, reply.ValidateParent(&comment))
+
+	other := comment
+	other.CatchId = "catch"
+	assert.Equal(t, ErrorInvalid, reply.ValidateParent(&other)[0].Code)
+	assert.Equal(t, "parentId", reply.ValidateParent(nil)[0].Field)
+
+	answer := Comment{Target: target, Id: "c3", ParentId: "c2"}
+	assert.Equal(t, "replies can not be answered", answer.ValidateParent(&reply)[0].Message)
+}
+
+func TestValidateCommentText(t *testing.T) {
+
+	t.Parallel()
+
+	assert.Empty(t, ValidateCommentText("Nice pike!"))
+	assert.Equal(t, ErrorRequired, ValidateCommentText("  ")[0].Code)
+	assert.Equal(t, ErrorTooLong, ValidateCommentText(strings.Repeat("a", MaxCommentLength+1))[0].Code)
+}
+
+func TestCommentPermissions(t *testing.T) {
+
+	t.Parallel()
+
+	comment := Comment{Target: Target{OwnerId: "anna", SpotId: "lake"}, AuthorId: "ben", Flagged: true}
+
+	assert.True(t, comment.CanDelete("ben"))
+	assert.True(t, comment.CanDelete("anna"))
+	assert.False(t, comment.CanDelete("carl"))
+
+	assert.True(t, comment.VisibleTo("anna"))
+	assert.False(t, comment.VisibleTo("carl"))
+	comment.Flagged = false
+	assert.True(t, comment.VisibleTo("carl"))
+}
+
+func TestAttachReactions(t *testing.T) {
+
+	t.Parallel()
+
+	spots := []Fish_spot{
+		{Id: "lake", Catches: []Catch{{Id: "c1"}, {Id: "c2"}}},
+		{Id: "river"},
+	}
+	AttachReactions(spots, []ReactionCount{
+		{SpotId: "lake", Type: ReactionLike, Count: 2},
+		{SpotId: "lake", CatchId: "c1", Type: ReactionWow, Count: 1},
+		{SpotId: "lake", CatchId: "c1", Type: ReactionLike, Count: 3},
+	})
+
+	assert.Equal(t, map[string]int{ReactionLike: 2}, spots[0].Reactions)
+	assert.Equal(t, map[string]int{ReactionWow: 1, ReactionLike: 3}, spots[0].Catches[0].Reactions)
+	assert.Nil(t, spots[0].Catches[1].Reactions)
+	assert.Nil(t, spots[1].Reactions)
+}
+
+func TestFishSpotVisibleTo(t *testing.T) {
+
+	t.Parallel()
+
+	private := Fish_spot{}
+	friends := Fish_spot{Visibility: VisibilityFriends}
+	public := Fish_spot{Visibility: VisibilityPublic}
+
+	assert.True(t, private.VisibleTo(true, false))
+	assert.False(t, private.VisibleTo(false, true))
+	assert.True(t, friends.VisibleTo(false, true))
+	assert.False(t, friends.VisibleTo(false, false))
+	assert.True(t, public.VisibleTo(false, false))
+}
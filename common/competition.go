@@ -0,0 +1,133 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// The scoring rules of a competition.
+const (
+	ScoringLongest     string = "longest"     // size of the largest single fish
+	ScoringTotalLength string = "totalLength" // sizes of all fish added up
+	ScoringCount       string = "count"       // number of fish
+)
+
+const (
+	MaxCompetitionNameLength int = 100
+
+	ErrorOutsideWindow   string = "outside_window"
+	ErrorOutsideBoundary string = "outside_boundary"
+	ErrorSpeciesExcluded string = "species_excluded"
+	ErrorClosed          string = "closed"
+)
+
+// Competition is a fishing competition a user runs for a group. Only the members of the group take part,
+// unless the competition is public. Catches count if they were made between start and end, of one of the
+// species and inside the boundary. Species are species ids or, for species not in the catalog, names; an
+// empty list allows all species.
+type Competition struct {
+	Id          string        `json:"id" bson:"_id"`
+	OrganizerId string        `json:"organizerId" bson:"organizerId"`
+	GroupId     string        `json:"groupId" bson:"groupId"`
+	Public      bool          `json:"public" bson:"public"` // open to all users rather than the members of the group
+	Name        string        `json:"name" bson:"name"`
+	Description string        `json:"description,omitempty" bson:"description,omitempty"`
+	Start       time.Time     `json:"start" bson:"start"`
+	End         time.Time     `json:"end" bson:"end"`
+	Species     []string      `json:"species" bson:"species"`
+	Scoring     string        `json:"scoring" bson:"scoring"`
+	Boundary    []Coordinates `json:"boundary" bson:"boundary"` // outline of the area, the last point may repeat the first
+	CreatedAt   time.Time     `json:"createdAt" bson:"createdAt"`
+}
+
+// Submission is a catch entered into a competition. It keeps the values the catch was checked and scored
+// with, so that later changes of the catch do not change the results.
+type Submission struct {
+	Id            string      `json:"id" bson:"_id"`
+	CompetitionId string      `json:"competitionId" bson:"competitionId"`
+	UserId        string      `json:"userId" bson:"userId"`
+	SpotId        string      `json:"spotId" bson:"spotId"`
+	CatchId       string      `json:"catchId" bson:"catchId"`
+	Species       string      `json:"species" bson:"species"`
+	Fish          string      `json:"fish" bson:"fish"`
+	Number        int         `json:"number" bson:"number"`
+	Size          float64     `json:"size" bson:"size"` // cm
+	Coordinates   Coordinates `json:"coordinates" bson:"coordinates"`
+	CaughtAt      time.Time   `json:"caughtAt" bson:"caughtAt"`
+	SubmittedAt   time.Time   `json:"submittedAt" bson:"submittedAt"`
+}
+
+// Standing is the place of a participant in a leaderboard. Participants with the same score share the rank,
+// unless one of them reached the score earlier. Score is in cm or, for ScoringCount, a number of fish.
+type Standing struct {
+	Rank        int       `json:"rank"`
+	UserId      string    `json:"userId"`
+	UserName    string    `json:"userName,omitempty"`
+	Score       float64   `json:"score"`
+	Unit        string    `json:"unit,omitempty"`
+	Catches     int       `json:"catches"`
+	ReachedAt   time.Time `json:"reachedAt"`
+	BestCatchId string    `json:"bestCatchId,omitempty"` // the largest fish, for ScoringLongest
+}
+
+// Leaderboard are the standings of a competition. It is final once the competition has ended.
+type Leaderboard struct {
+	CompetitionId string     `json:"competitionId"`
+	Scoring       string     `json:"scoring"`
+	Final         bool       `json:"final"`
+	Standings     []Standing `json:"standings"`
+}
+
+// SubmissionId returns the id of the submission of a catch to a competition.
+func SubmissionId(competitionId string, catchId string) string {
+	return competitionId + "/" + catchId
+}
+
+// IsScoring tells whether the value is one of the scoring rules.
+func IsScoring(value string) bool {
+	return value == ScoringLongest || value == ScoringTotalLength || value == ScoringCount
+}
+
+// Validate checks the group, name, window, scoring rule, species and boundary of the competition.
+func (c Competition) Validate() ValidationErrors {
+
+	var errs ValidationErrors
+
+	if len(c.GroupId) == 0 {
+		errs = append(errs, FieldError{Field: "groupId", Code: ErrorRequired, Message: "groupId must not be empty"})
+	}
+	name := strings.TrimSpace(c.Name)
+	if len(name) == 0 {
+		errs = append(errs, FieldError{Field: "name", Code: ErrorRequired, Message: "name must not be empty"})
+	} else if utf8.RuneCountInString(name) > MaxCompetitionNameLength {
+		errs = append(errs, FieldError{Field: "name", Code: ErrorTooLong, Message: fmt.Sprintf("name must not be longer than %d characters", MaxCompetitionNameLength)})
+	}
+	if utf8.RuneCountInString(c.Description) > MaxNotesLength {
+		errs = append(errs, FieldError{Field: "description", Code: ErrorTooLong, Message: fmt.Sprintf("description must not be longer than %d characters", MaxNotesLength)})
+	}
+
+	if c.Start.IsZero() {
+		errs = append(errs, FieldError{Field: "start", Code: ErrorRequired, Message: "start must be set"})
+	}
+	if c.End.IsZero() {
+		errs = append(errs, FieldError{Field: "end", Code: ErrorRequired, Message: "end must be set"})
+	} else if !c.Start.IsZero() && !c.End.After(c.Start) {
+		errs = append(errs, FieldError{Field: "end", Code: ErrorOutOfRange, Message: "end must be after start"})
+	}
+
+	if !IsScoring(c.Scoring) {
+		errs = append(errs, FieldError{Field: "scoring", Code: ErrorInvalid, Message: "scoring must be longest, totalLength or count"})
+	}
+	errs = append(errs, requireIds("species", c.Species)...)
+
+	if len(c.Boundary) < 3 {
+		errs = append(errs, FieldError{Field: "boundary", Code: ErrorRequired, Message: "boundary must have at least 3 points"})
+	}
+	for i, point := range c.Boundary {
+		errs = append(errs, point.Validate(fmt.Sprintf("boundary[%d]", i))...)
+	}
+
+	return errs
+}
@@ -0,0 +1,32 @@
+package common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestCompetitionValidate(t *testing.T) {
+
+	t.Parallel()
+
+	start := time.Date(2023, 6, 3, 5, 0, 0, 0, time.UTC)
+	boundary := []Coordinates{{Latitude: 52, Longitude: 13}, {Latitude: 52, Longitude: 14}, {Latitude: 53, Longitude: 14}}
+
+	assert.Empty(t, Competition{GroupId: "club", Name: "Club cup", Start: start, End: start.Add(8 * time.Hour), Scoring: ScoringLongest, Boundary: boundary}.Validate())
+
+	var fields []string
+	invalid := Competition{Name: " ", Start: start, End: start, Species: []string{""}, Scoring: "heaviest", Boundary: []Coordinates{{Latitude: 91}}}
+	for _, e := range invalid.Validate() {
+		fields = append(fields, e.Field+":"+e.Code)
+	}
+	assert.Equal(t, []string{
+		"groupId:required",
+		"name:required",
+		"end:out_of_range",
+		"scoring:invalid",
+		"species[0]:required",
+		"boundary:required",
+		"boundary[0].latitude:out_of_range",
+	}, fields)
+}
@@ -0,0 +1,63 @@
+package common
+
+import "time"
+
+// TripEffort is a trip of a user with the spots it visited and the catches it made, as aggregated for the
+// catch per unit effort.
+type TripEffort struct {
+	TripId  string        `bson:"_id"`
+	Start   time.Time     `bson:"start"`
+	End     *time.Time    `bson:"end"`
+	Baits   []string      `bson:"baits"`
+	Spots   []EffortSpot  `bson:"spots"`
+	Catches []EffortCatch `bson:"catches"`
+}
+
+type EffortSpot struct {
+	Id          string      `bson:"id"`
+	Title       string      `bson:"title"`
+	Coordinates Coordinates `bson:"coordinates"`
+}
+
+type EffortCatch struct {
+	SpotId    string `bson:"spotId"`
+	SpeciesId string `bson:"speciesId"`
+	Fish      string `bson:"fish"`
+	Number    int    `bson:"number"`
+	Time      string `bson:"time"`
+	Bait      string `bson:"bait"`
+}
+
+// Interval is a confidence interval.
+type Interval struct {
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+}
+
+// CPUEGroup is the catch per unit effort of the trips, or the share of them, falling into a group. Trips
+// counts the trips contributing effort to the group and SuccessfulTrips those of them with a catch in it.
+type CPUEGroup struct {
+	Key             string   `json:"key"`
+	Name            string   `json:"name,omitempty"`
+	Trips           int      `json:"trips"`
+	SuccessfulTrips int      `json:"successfulTrips"`
+	Hours           float64  `json:"hours"`
+	Catches         int      `json:"catches"`
+	Fish            int      `json:"fish"`
+	FishPerHour     float64  `json:"fishPerHour"`
+	FishPerHourCI   Interval `json:"fishPerHourCI"`
+	SuccessRate     float64  `json:"successRate"`
+	SuccessRateCI   Interval `json:"successRateCI"`
+}
+
+// CPUEAnalytics is the catch per unit effort of the trips of a user, overall and by spot, species, month,
+// part of the day and bait. The intervals are at the given confidence level.
+type CPUEAnalytics struct {
+	Confidence  float64     `json:"confidence"`
+	Totals      CPUEGroup   `json:"totals"`
+	BySpot      []CPUEGroup `json:"bySpot"`
+	BySpecies   []CPUEGroup `json:"bySpecies"`
+	ByMonth     []CPUEGroup `json:"byMonth"`
+	ByTimeOfDay []CPUEGroup `json:"byTimeOfDay"`
+	ByBait      []CPUEGroup `json:"byBait"`
+}
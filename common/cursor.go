@@ -0,0 +1,42 @@
+package common
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Cursor is the position of the last record of a page of records ordered by creation time; the next page
+// starts after it.
+type Cursor struct {
+	CreatedAt time.Time
+	Id        string
+}
+
+// Encode returns the cursor as an opaque string for query parameters.
+func (c Cursor) Encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.Id))
+}
+
+// DecodeCursor reads an encoded cursor. The empty cursor is the start of the records and returns nil.
+func DecodeCursor(value string) (*Cursor, error) {
+	if len(value) == 0 {
+		return nil, nil
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	at, id, found := strings.Cut(string(decoded), "|")
+	if !found {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, at)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	return &Cursor{CreatedAt: createdAt, Id: id}, nil
+}
@@ -0,0 +1,26 @@
+package common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestCursor(t *testing.T) {
+
+	t.Parallel()
+
+	at := time.Date(2023, 6, 3, 5, 0, 0, 123000000, time.UTC)
+	cursor, err := DecodeCursor(Cursor{CreatedAt: at, Id: "a2"}.Encode())
+	assert.NoError(t, err)
+	assert.Equal(t, &Cursor{CreatedAt: at, Id: "a2"}, cursor)
+
+	cursor, err = DecodeCursor("")
+	assert.NoError(t, err)
+	assert.Nil(t, cursor)
+
+	for _, invalid := range []string{"%%%", Cursor{Id: "a"}.Encode()[:8], "bm8tc2VwYXJhdG9y"} {
+		_, err = DecodeCursor(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
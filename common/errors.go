@@ -0,0 +1,12 @@
+package common
+
+import "errors"
+
+var (
+	// ErrNotFound is returned by the repository when the record to read, update or delete does not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrConflict is returned by the repository when a record to create collides with an existing one.
+	ErrConflict = errors.New("already exists")
+	// ErrForbidden is returned when a user may not act on a record of another user.
+	ErrForbidden = errors.New("forbidden")
+)
@@ -0,0 +1,56 @@
+package common
+
+import "time"
+
+// The kinds of activities in the feed.
+const (
+	ActivitySpot  string = "spot"  // a spot was saved
+	ActivityCatch string = "catch" // a catch was logged
+)
+
+// Activity is an event of a user shown in the feed of the friends of the user. It refers to the spot and the
+// catch, whose visibility is checked when the feed is read.
+type Activity struct {
+	Id        string    `json:"id" bson:"_id"`
+	UserId    string    `json:"userId" bson:"userId"`
+	Type      string    `json:"type" bson:"type"`
+	SpotId    string    `json:"spotId" bson:"spotId"`
+	CatchId   string    `json:"catchId,omitempty" bson:"catchId,omitempty"`
+	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
+}
+
+// FeedEntry is an activity with its spot and catch as they are now.
+type FeedEntry struct {
+	Activity `bson:",inline"`
+	Spot     Fish_spot `bson:"spot"`
+	Catch    *Catch    `bson:"catch,omitempty"`
+}
+
+// FeedCursor is the position of the last activity of a page of the feed; the next page starts after it.
+type FeedCursor = Cursor
+
+// FeedSpot is a spot as shown to other users. If the owner hides its location, the coordinates are only
+// those of the area around it.
+type FeedSpot struct {
+	Id             string      `json:"id"`
+	Title          string      `json:"title"`
+	Coordinates    Coordinates `json:"coordinates"`
+	LocationHidden bool        `json:"locationHidden,omitempty"`
+}
+
+type FeedItem struct {
+	Id        string    `json:"id"`
+	UserId    string    `json:"userId"`
+	UserName  string    `json:"userName"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"createdAt"`
+	Spot      FeedSpot  `json:"spot"`
+	Catch     *Catch    `json:"catch,omitempty"`
+	Text      string    `json:"text"`
+}
+
+// Feed is a page of the feed, the latest activities first. NextCursor is empty on the last page.
+type Feed struct {
+	Items      []FeedItem `json:"items"`
+	NextCursor string     `json:"nextCursor,omitempty"`
+}
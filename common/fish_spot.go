@@ -1,26 +1,75 @@
 package common
 
+import "time"
+
+// The parts of the day a catch is assigned to by its time.
+const (
+	TimeMorning   string = "Morning"
+	TimeDay       string = "Day"
+	TimeAfternoon string = "Afternoon"
+	TimeNight     string = "Night"
+)
+
 type Fish_spots struct {
 	Fish_spots []Fish_spot `json:"fish_spots"`
 }
 
+// The visibilities of a spot to other users.
+const (
+	VisibilityPrivate string = "private"
+	VisibilityFriends string = "friends"
+	VisibilityPublic  string = "public"
+)
+
 type Fish_spot struct {
-	Id      string  `json:"id"`
-	Marker  Marker  `json:"marker"`
-	Catches []Catch `json:"catches"`
+	Id           string         `json:"id"`
+	Marker       Marker         `json:"marker"`
+	Catches      []Catch        `json:"catches"`
+	Visibility   string         `json:"visibility,omitempty"`         // private (default), friends, public
+	HideLocation bool           `json:"hideLocation,omitempty"`       // shows other users only the rough area of the spot
+	Reactions    map[string]int `json:"reactions,omitempty" bson:"-"` // number of reactions of other users by type
 }
 
 type Catch struct {
-	Fish      string    `json:"id"`
-	Number    int       `json:"number"`
-	Size      float32   `json:"size"`
-	Equipment Equipment `json:"equipment"`
-	Deep      int       `json:"deep"`
-	Time      string    `json:"time"` //Morning, Day, Afternoon, night
+	Id         string         `json:"catchId,omitempty"`
+	Fish       string         `json:"id"`                  // name of the species, free text for species not in the catalog
+	SpeciesId  string         `json:"speciesId,omitempty"` // id of the species in the catalog
+	Number     int            `json:"number"`
+	Size       float32        `json:"size"`
+	SizeUnit   string         `json:"sizeUnit,omitempty"` // cm (default), mm, in
+	Weight     *float64       `json:"weight,omitempty"`
+	WeightUnit string         `json:"weightUnit,omitempty"` // kg (default), g, lb, oz
+	Equipment  Equipment      `json:"equipment"`
+	Deep       float64        `json:"deep"`
+	DeepUnit   string         `json:"deepUnit,omitempty"` // m (default), ft
+	Time       string         `json:"time"`               //Morning, Day, Afternoon, Night; derived from CaughtAt if given
+	CaughtAt   *time.Time     `json:"caughtAt,omitempty"`
+	Weather    *Weather       `json:"weather,omitempty"`
+	MoonPhase  *MoonPhase     `json:"moonPhase,omitempty"`
+	Reactions  map[string]int `json:"reactions,omitempty" bson:"-"` // number of reactions of other users by type
 }
 
+// Equipment describes the gear of a catch as free text. Gear of the tackle box of the user is referenced by
+// ItemIds, the text of the referenced slots is filled in from the items.
 type Equipment struct {
-	Name   string `json:"name"`
-	Bait   string `json:"bait"`
-	Leader string `json:"leader"`
+	Name    string   `json:"name"`
+	Bait    string   `json:"bait"`
+	Leader  string   `json:"leader"`
+	ItemIds []string `json:"itemIds,omitempty" bson:",omitempty"`
+}
+
+// IsVisibility tells whether the value is a known visibility. The empty visibility is private.
+func IsVisibility(value string) bool {
+	return len(value) == 0 || value == VisibilityPrivate || value == VisibilityFriends || value == VisibilityPublic
+}
+
+// Shared tells whether the spot is visible to the friends of its owner.
+func (s Fish_spot) Shared() bool {
+	return s.Visibility == VisibilityFriends || s.Visibility == VisibilityPublic
+}
+
+// VisibleTo tells whether a user can see the spot: its owner always, the friends of the owner if it is
+// shared and everyone else if it is public.
+func (s Fish_spot) VisibleTo(owner bool, friend bool) bool {
+	return owner || (friend && s.Shared()) || s.Visibility == VisibilityPublic
 }
@@ -0,0 +1,130 @@
+package common
+
+import (
+	"errors"
+	"time"
+)
+
+// The states of the relationship between two users.
+const (
+	FriendshipPending  string = "pending"
+	FriendshipAccepted string = "accepted"
+	FriendshipBlocked  string = "blocked"
+)
+
+// The actions a user can take on the relationship with another user.
+const (
+	FriendRequest  string = "request"
+	FriendAccept   string = "accept"
+	FriendDecline  string = "decline"
+	FriendCancel   string = "cancel"
+	FriendUnfollow string = "unfollow"
+	FriendBlock    string = "block"
+	FriendUnblock  string = "unblock"
+)
+
+// Friendship is the relationship between two users. RequesterId is the user who sent the friend request or,
+// for a blocked relationship, who blocked the other one.
+type Friendship struct {
+	Id          string    `json:"-" bson:"_id"`
+	RequesterId string    `json:"requesterId" bson:"requesterId"`
+	AddresseeId string    `json:"addresseeId" bson:"addresseeId"`
+	Status      string    `json:"status" bson:"status"`
+	CreatedAt   time.Time `json:"createdAt" bson:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+// Friend is a relationship as seen by one of its users. Direction tells whether the other user sent the
+// request or was blocked by the user (outgoing) or the other way around (incoming).
+type Friend struct {
+	UserId    string    `json:"userId"`
+	Name      string    `json:"name"`
+	Status    string    `json:"status"`
+	Direction string    `json:"direction"`
+	Since     time.Time `json:"since"`
+}
+
+const (
+	DirectionIncoming string = "incoming"
+	DirectionOutgoing string = "outgoing"
+)
+
+// FriendshipId returns the id of the relationship between two users, which is the same from both sides.
+func FriendshipId(userId string, otherId string) string {
+	if userId > otherId {
+		userId, otherId = otherId, userId
+	}
+	return userId + "/" + otherId
+}
+
+// Other returns the other user of the relationship.
+func (f Friendship) Other(userId string) string {
+	if f.RequesterId == userId {
+		return f.AddresseeId
+	}
+	return f.RequesterId
+}
+
+// ApplyFriendAction returns the relationship after the user took the action on the current relationship with
+// the other user, nil if there is none afterwards. current is nil if the users have no relationship yet.
+//
+// Declined, cancelled and unfollowed relationships are removed, so that a new request can be sent. A request
+// to a user who sent a request already accepts it. A blocked relationship can only be unblocked by the user
+// who blocked, all other actions on it are forbidden.
+func ApplyFriendAction(current *Friendship, action string, userId string, otherId string, at time.Time) (*Friendship, error) {
+
+	if current != nil && current.Status == FriendshipBlocked {
+		if action == FriendUnblock && current.RequesterId == userId {
+			return nil, nil
+		}
+		return nil, ErrForbidden
+	}
+
+	switch action {
+	case FriendRequest:
+		if current == nil {
+			return &Friendship{Id: FriendshipId(userId, otherId), RequesterId: userId, AddresseeId: otherId, Status: FriendshipPending, CreatedAt: at, UpdatedAt: at}, nil
+		}
+		if current.Status == FriendshipPending && current.AddresseeId == userId {
+			return accepted(*current, at), nil
+		}
+		return nil, ErrConflict
+
+	case FriendAccept:
+		if current == nil || current.Status != FriendshipPending || current.AddresseeId != userId {
+			return nil, ErrNotFound
+		}
+		return accepted(*current, at), nil
+
+	case FriendDecline, FriendCancel:
+		requester := otherId
+		if action == FriendCancel {
+			requester = userId
+		}
+		if current == nil || current.Status != FriendshipPending || current.RequesterId != requester {
+			return nil, ErrNotFound
+		}
+		return nil, nil
+
+	case FriendUnfollow:
+		if current == nil || current.Status != FriendshipAccepted {
+			return nil, ErrNotFound
+		}
+		return nil, nil
+
+	case FriendBlock:
+		blocked := Friendship{Id: FriendshipId(userId, otherId), RequesterId: userId, AddresseeId: otherId, Status: FriendshipBlocked, CreatedAt: at, UpdatedAt: at}
+		return &blocked, nil
+
+	case FriendUnblock:
+		return nil, ErrNotFound
+	}
+
+	return nil, errors.New("unknown action '" + action + "'")
+}
+
+func accepted(f Friendship, at time.Time) *Friendship {
+	f.Status = FriendshipAccepted
+	f.UpdatedAt = at
+	return &f
+}
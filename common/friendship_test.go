@@ -0,0 +1,71 @@
+package common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestApplyFriendAction(t *testing.T) {
+
+	t.Parallel()
+
+	at := time.Date(2023, 6, 3, 5, 0, 0, 0, time.UTC)
+	later := at.Add(time.Hour)
+	pending := &Friendship{Id: "anna/ben", RequesterId: "anna", AddresseeId: "ben", Status: FriendshipPending, CreatedAt: at, UpdatedAt: at}
+	friends := &Friendship{Id: "anna/ben", RequesterId: "anna", AddresseeId: "ben", Status: FriendshipAccepted, CreatedAt: at, UpdatedAt: at}
+	blocked := &Friendship{Id: "anna/ben", RequesterId: "ben", AddresseeId: "anna", Status: FriendshipBlocked, CreatedAt: at, UpdatedAt: at}
+
+	type test struct {
+		current  *Friendship
+		action   string
+		user     string
+		other    string
+		expected *Friendship
+		err      error
+	}
+
+	cases := map[string]test{
+		"request":                  {nil, FriendRequest, "anna", "ben", &Friendship{Id: "anna/ben", RequesterId: "anna", AddresseeId: "ben", Status: FriendshipPending, CreatedAt: later, UpdatedAt: later}, nil},
+		"request twice":            {pending, FriendRequest, "anna", "ben", nil, ErrConflict},
+		"request back accepts":     {pending, FriendRequest, "ben", "anna", &Friendship{Id: "anna/ben", RequesterId: "anna", AddresseeId: "ben", Status: FriendshipAccepted, CreatedAt: at, UpdatedAt: later}, nil},
+		"request a friend":         {friends, FriendRequest, "ben", "anna", nil, ErrConflict},
+		"accept":                   {pending, FriendAccept, "ben", "anna", &Friendship{Id: "anna/ben", RequesterId: "anna", AddresseeId: "ben", Status: FriendshipAccepted, CreatedAt: at, UpdatedAt: later}, nil},
+		"accept own request":       {pending, FriendAccept, "anna", "ben", nil, ErrNotFound},
+		"accept without request":   {nil, FriendAccept, "ben", "anna", nil, ErrNotFound},
+		"decline":                  {pending, FriendDecline, "ben", "anna", nil, nil},
+		"decline own request":      {pending, FriendDecline, "anna", "ben", nil, ErrNotFound},
+		"cancel":                   {pending, FriendCancel, "anna", "ben", nil, nil},
+		"cancel received request":  {pending, FriendCancel, "ben", "anna", nil, ErrNotFound},
+		"unfollow":                 {friends, FriendUnfollow, "ben", "anna", nil, nil},
+		"unfollow pending":         {pending, FriendUnfollow, "anna", "ben", nil, ErrNotFound},
+		"block friend":             {friends, FriendBlock, "ben", "anna", &Friendship{Id: "anna/ben", RequesterId: "ben", AddresseeId: "anna", Status: FriendshipBlocked, CreatedAt: later, UpdatedAt: later}, nil},
+		"request blocking user":    {blocked, FriendRequest, "anna", "ben", nil, ErrForbidden},
+		"block back":               {blocked, FriendBlock, "anna", "ben", nil, ErrForbidden},
+		"unblock":                  {blocked, FriendUnblock, "ben", "anna", nil, nil},
+		"unblock as blocked user":  {blocked, FriendUnblock, "anna", "ben", nil, ErrForbidden},
+		"unblock without blocking": {friends, FriendUnblock, "anna", "ben", nil, ErrNotFound},
+	}
+
+	for name, tc := range cases {
+
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := ApplyFriendAction(tc.current, tc.action, tc.user, tc.other, later)
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestFriendshipId(t *testing.T) {
+
+	t.Parallel()
+
+	assert.Equal(t, "anna/ben", FriendshipId("anna", "ben"))
+	assert.Equal(t, "anna/ben", FriendshipId("ben", "anna"))
+	assert.Equal(t, "ben", Friendship{RequesterId: "anna", AddresseeId: "ben"}.Other("anna"))
+}
@@ -0,0 +1,36 @@
+// Package geo provides geometric helper functions on coordinates.
+package geo
+
+import (
+	"fishfishes_backend/common"
+	"fishfishes_backend/common/utils"
+	"math"
+)
+
+const earthRadius float64 = 6371000 // mean earth radius in meters
+
+// Distance returns the great-circle distance between two coordinates in meters.
+func Distance(a, b common.Coordinates) float64 {
+
+	lat1 := toRadians(a.Latitude)
+	lat2 := toRadians(b.Latitude)
+	dLat := lat2 - lat1
+	dLon := toRadians(b.Longitude - a.Longitude)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+func toRadians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
+
+// Obfuscate returns the center of the cell of a grid with the given edge length in degrees the coordinates
+// fall into. Coordinates in the same cell always give the same center, so that repeated requests do not
+// narrow down the original location.
+func Obfuscate(c common.Coordinates, cellDegrees float64) common.Coordinates {
+	center := func(value float64) float64 {
+		return utils.ScaleHalfUp((math.Floor(value/cellDegrees)+0.5)*cellDegrees, 6)
+	}
+	return common.Coordinates{Latitude: center(c.Latitude), Longitude: center(c.Longitude)}
+}
@@ -0,0 +1,97 @@
+package geo
+
+import (
+	"fishfishes_backend/common"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+
+	t.Parallel()
+
+	type test struct {
+		a        common.Coordinates
+		b        common.Coordinates
+		expected float64
+	}
+
+	cases := map[string]test{
+		"same point": {
+			a:        common.Coordinates{Latitude: 52.52, Longitude: 13.405},
+			b:        common.Coordinates{Latitude: 52.52, Longitude: 13.405},
+			expected: 0,
+		},
+		"berlin to hamburg": {
+			a:        common.Coordinates{Latitude: 52.5200, Longitude: 13.4050},
+			b:        common.Coordinates{Latitude: 53.5511, Longitude: 9.9937},
+			expected: 255_500,
+		},
+		"one degree on the equator": {
+			a:        common.Coordinates{Latitude: 0, Longitude: 0},
+			b:        common.Coordinates{Latitude: 0, Longitude: 1},
+			expected: 111_195,
+		},
+	}
+
+	for name, tc := range cases {
+
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result := Distance(tc.a, tc.b)
+			assert.InDelta(t, tc.expected, result, 1000)
+		})
+	}
+}
+
+func TestPolygonContains(t *testing.T) {
+
+	t.Parallel()
+
+	square := func(min, max float64) Ring {
+		return Ring{
+			{Latitude: min, Longitude: min},
+			{Latitude: min, Longitude: max},
+			{Latitude: max, Longitude: max},
+			{Latitude: max, Longitude: min},
+		}
+	}
+	polygon := Polygon{square(0, 10), square(4, 6)}
+
+	type test struct {
+		coordinates common.Coordinates
+		expected    bool
+	}
+
+	cases := map[string]test{
+		"inside":          {coordinates: common.Coordinates{Latitude: 2, Longitude: 2}, expected: true},
+		"outside":         {coordinates: common.Coordinates{Latitude: 12, Longitude: 2}, expected: false},
+		"inside the hole": {coordinates: common.Coordinates{Latitude: 5, Longitude: 5}, expected: false},
+	}
+
+	for name, tc := range cases {
+
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, polygon.Contains(tc.coordinates))
+			assert.Equal(t, tc.expected, MultiPolygon{polygon}.Contains(tc.coordinates))
+		})
+	}
+}
+
+func TestObfuscate(t *testing.T) {
+
+	t.Parallel()
+
+	obfuscated := Obfuscate(common.Coordinates{Latitude: 52.5163, Longitude: 13.3777}, 0.02)
+	assert.Equal(t, common.Coordinates{Latitude: 52.51, Longitude: 13.37}, obfuscated)
+	assert.Equal(t, obfuscated, Obfuscate(common.Coordinates{Latitude: 52.5001, Longitude: 13.3799}, 0.02))
+
+	assert.Equal(t, common.Coordinates{Latitude: -33.87, Longitude: 151.21}, Obfuscate(common.Coordinates{Latitude: -33.8688, Longitude: 151.2093}, 0.02))
+}
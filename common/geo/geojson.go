@@ -0,0 +1,121 @@
+package geo
+
+import (
+	"encoding/json"
+	"fishfishes_backend/common"
+	"fmt"
+	"github.com/pkg/errors"
+	"io"
+)
+
+// Feature is an area read from a GeoJSON file.
+type Feature struct {
+	Properties map[string]interface{}
+	Geometry   MultiPolygon
+}
+
+// Property returns the string property of the given name or an empty string.
+func (f Feature) Property(name string) string {
+	if value, ok := f.Properties[name].(string); ok {
+		return value
+	}
+	return ""
+}
+
+type featureCollection struct {
+	Type     string `json:"type"`
+	Features []struct {
+		Properties map[string]interface{} `json:"properties"`
+		Geometry   json.RawMessage        `json:"geometry"`
+	} `json:"features"`
+}
+
+type geometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// ReadAreas reads the Polygon and MultiPolygon features of a GeoJSON FeatureCollection.
+// Features with other geometries are skipped.
+func ReadAreas(r io.Reader) ([]Feature, error) {
+
+	var collection featureCollection
+	err := json.NewDecoder(r).Decode(&collection)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse GeoJSON")
+	}
+	if collection.Type != "FeatureCollection" {
+		return nil, fmt.Errorf("expected a GeoJSON FeatureCollection, got '%s'", collection.Type)
+	}
+
+	var features []Feature
+	for i, raw := range collection.Features {
+		area, err := ParseArea(raw.Geometry)
+		if err != nil {
+			return nil, errors.Wrapf(err, "feature %d", i)
+		}
+		if area == nil {
+			continue
+		}
+		features = append(features, Feature{Properties: raw.Properties, Geometry: area})
+	}
+
+	return features, nil
+}
+
+// ParseArea parses a GeoJSON Polygon or MultiPolygon geometry. Other geometries result in nil.
+func ParseArea(raw json.RawMessage) (MultiPolygon, error) {
+
+	var g geometry
+	err := json.Unmarshal(raw, &g)
+	if err != nil {
+		return nil, err
+	}
+
+	switch g.Type {
+	case "Polygon":
+		var positions [][][]float64
+		err = json.Unmarshal(g.Coordinates, &positions)
+		if err != nil {
+			return nil, err
+		}
+		polygon, err := toPolygon(positions)
+		if err != nil {
+			return nil, err
+		}
+		return MultiPolygon{polygon}, nil
+	case "MultiPolygon":
+		var positions [][][][]float64
+		err = json.Unmarshal(g.Coordinates, &positions)
+		if err != nil {
+			return nil, err
+		}
+		var multi MultiPolygon
+		for _, p := range positions {
+			polygon, err := toPolygon(p)
+			if err != nil {
+				return nil, err
+			}
+			multi = append(multi, polygon)
+		}
+		return multi, nil
+	}
+
+	return nil, nil
+}
+
+// toPolygon converts GeoJSON positions, which are ordered longitude first, to a polygon.
+func toPolygon(positions [][][]float64) (Polygon, error) {
+	var polygon Polygon
+	for _, ring := range positions {
+		var r Ring
+		for _, position := range ring {
+			if len(position) < 2 {
+				return nil, fmt.Errorf("invalid position %v", position)
+			}
+			r = append(r, common.Coordinates{Latitude: position[1], Longitude: position[0]})
+		}
+		polygon = append(polygon, r)
+	}
+	return polygon, nil
+}
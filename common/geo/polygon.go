@@ -0,0 +1,49 @@
+package geo
+
+import "fishfishes_backend/common"
+
+// Ring is a closed line of coordinates. The last coordinate may repeat the first one.
+type Ring []common.Coordinates
+
+// Polygon is an outer ring followed by optional holes.
+type Polygon []Ring
+
+// MultiPolygon is a set of polygons, for example a country with islands.
+type MultiPolygon []Polygon
+
+// Contains returns true if the coordinates lie inside the ring, using the even-odd rule.
+func (r Ring) Contains(c common.Coordinates) bool {
+
+	inside := false
+	for i, j := 0, len(r)-1; i < len(r); j, i = i, i+1 {
+		a, b := r[i], r[j]
+		if (a.Latitude > c.Latitude) != (b.Latitude > c.Latitude) &&
+			c.Longitude < (b.Longitude-a.Longitude)*(c.Latitude-a.Latitude)/(b.Latitude-a.Latitude)+a.Longitude {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// Contains returns true if the coordinates lie inside the outer ring and outside of all holes.
+func (p Polygon) Contains(c common.Coordinates) bool {
+	if len(p) == 0 || !p[0].Contains(c) {
+		return false
+	}
+	for _, hole := range p[1:] {
+		if hole.Contains(c) {
+			return false
+		}
+	}
+	return true
+}
+
+// Contains returns true if one of the polygons contains the coordinates.
+func (m MultiPolygon) Contains(c common.Coordinates) bool {
+	for _, polygon := range m {
+		if polygon.Contains(c) {
+			return true
+		}
+	}
+	return false
+}
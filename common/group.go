@@ -0,0 +1,125 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// The roles of the members of a group.
+const (
+	GroupOwner  string = "owner"  // manages the roles and can delete the group
+	GroupAdmin  string = "admin"  // edits the group and its spots and invites and removes members
+	GroupMember string = "member" // views the spots of the group
+)
+
+// The states of a membership.
+const (
+	MembershipInvited string = "invited"
+	MembershipActive  string = "active"
+)
+
+const MaxGroupNameLength int = 100
+
+var roleRank = map[string]int{GroupMember: 1, GroupAdmin: 2, GroupOwner: 3}
+
+// Group is a club of users sharing a collection of spots.
+type Group struct {
+	Id          string    `json:"id" bson:"_id"`
+	Name        string    `json:"name" bson:"name"`
+	Description string    `json:"description,omitempty" bson:"description,omitempty"`
+	CreatedAt   time.Time `json:"createdAt" bson:"createdAt"`
+}
+
+// Membership is the role of a user in a group. An invited user becomes active by accepting the invitation.
+type Membership struct {
+	Id        string    `json:"-" bson:"_id"`
+	GroupId   string    `json:"groupId" bson:"groupId"`
+	UserId    string    `json:"userId" bson:"userId"`
+	UserName  string    `json:"userName,omitempty" bson:"-"`
+	Role      string    `json:"role" bson:"role"`
+	Status    string    `json:"status" bson:"status"`
+	InvitedBy string    `json:"invitedBy,omitempty" bson:"invitedBy,omitempty"`
+	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
+}
+
+// UserGroup is a group as seen by one of its members or invited users.
+type UserGroup struct {
+	Group  `bson:",inline"`
+	Role   string `json:"role"`
+	Status string `json:"status"`
+}
+
+// GroupSpot is a spot owned by a group, like the waters of a club.
+type GroupSpot struct {
+	Id        string    `json:"id" bson:"_id"`
+	GroupId   string    `json:"groupId" bson:"groupId"`
+	Marker    Marker    `json:"marker" bson:"marker"`
+	Notes     string    `json:"notes,omitempty" bson:"notes,omitempty"`
+	CreatedBy string    `json:"createdBy" bson:"createdBy"`
+	UpdatedBy string    `json:"updatedBy" bson:"updatedBy"`
+	UpdatedAt time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+// MembershipId returns the id of the membership of the user in the group.
+func MembershipId(groupId string, userId string) string {
+	return groupId + "/" + userId
+}
+
+// IsGroupRole tells whether the value is one of the roles.
+func IsGroupRole(value string) bool {
+	_, ok := roleRank[value]
+	return ok
+}
+
+// Active tells whether the user is a member of the group rather than invited.
+func (m Membership) Active() bool {
+	return m.Status == MembershipActive
+}
+
+// CanView tells whether the member can see the group, its members and its spots.
+func (m Membership) CanView() bool {
+	return m.Active()
+}
+
+// CanEdit tells whether the member can change the group and its spots and invite users.
+func (m Membership) CanEdit() bool {
+	return m.Active() && roleRank[m.Role] >= roleRank[GroupAdmin]
+}
+
+// CanRemove tells whether the member can remove the other member or invitation from the group, which
+// requires a higher role. Members leave the group on their own.
+func (m Membership) CanRemove(other Membership) bool {
+	return m.CanEdit() && roleRank[m.Role] > roleRank[other.Role]
+}
+
+// CanSetRole tells whether the member can give the other active member the role. Only the owner can, and
+// giving the owner role to another member hands the group over.
+func (m Membership) CanSetRole(other Membership, role string) bool {
+	return m.Active() && m.Role == GroupOwner && other.UserId != m.UserId && other.Active() && IsGroupRole(role)
+}
+
+// Validate checks that the group has a name.
+func (g Group) Validate() ValidationErrors {
+	name := strings.TrimSpace(g.Name)
+	if len(name) == 0 {
+		return ValidationErrors{{Field: "name", Code: ErrorRequired, Message: "name must not be empty"}}
+	}
+	if utf8.RuneCountInString(name) > MaxGroupNameLength {
+		return ValidationErrors{{Field: "name", Code: ErrorTooLong, Message: fmt.Sprintf("name must not be longer than %d characters", MaxGroupNameLength)}}
+	}
+	if utf8.RuneCountInString(g.Description) > MaxNotesLength {
+		return ValidationErrors{{Field: "description", Code: ErrorTooLong, Message: fmt.Sprintf("description must not be longer than %d characters", MaxNotesLength)}}
+	}
+	return nil
+}
+
+// Validate checks the marker and notes of the spot.
+func (s GroupSpot) Validate() ValidationErrors {
+	errs := s.Marker.Validate("marker")
+	if utf8.RuneCountInString(s.Notes) > MaxNotesLength {
+		errs = append(errs, FieldError{Field: "notes", Code: ErrorTooLong, Message: fmt.Sprintf("notes must not be longer than %d characters", MaxNotesLength)})
+	}
+	return errs
+}
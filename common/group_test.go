@@ -0,0 +1,55 @@
+package common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMembershipPermissions(t *testing.T) {
+
+	t.Parallel()
+
+	owner := Membership{UserId: "anna", Role: GroupOwner, Status: MembershipActive}
+	admin := Membership{UserId: "ben", Role: GroupAdmin, Status: MembershipActive}
+	member := Membership{UserId: "carl", Role: GroupMember, Status: MembershipActive}
+	invited := Membership{UserId: "dora", Role: GroupMember, Status: MembershipInvited}
+	invitedAdmin := Membership{UserId: "emil", Role: GroupAdmin, Status: MembershipInvited}
+
+	assert.True(t, member.CanView())
+	assert.False(t, invited.CanView())
+
+	assert.True(t, owner.CanEdit())
+	assert.True(t, admin.CanEdit())
+	assert.False(t, member.CanEdit())
+	assert.False(t, invitedAdmin.CanEdit())
+
+	assert.True(t, owner.CanRemove(admin))
+	assert.True(t, admin.CanRemove(member))
+	assert.True(t, admin.CanRemove(invited))
+	assert.False(t, admin.CanRemove(admin))
+	assert.False(t, admin.CanRemove(owner))
+	assert.False(t, member.CanRemove(invited))
+
+	assert.True(t, owner.CanSetRole(member, GroupAdmin))
+	assert.True(t, owner.CanSetRole(admin, GroupOwner))
+	assert.False(t, owner.CanSetRole(owner, GroupMember))
+	assert.False(t, owner.CanSetRole(invited, GroupAdmin))
+	assert.False(t, owner.CanSetRole(member, "president"))
+	assert.False(t, admin.CanSetRole(member, GroupAdmin))
+}
+
+func TestGroupValidate(t *testing.T) {
+
+	t.Parallel()
+
+	assert.Empty(t, Group{Name: "Anglerverein Müggelsee"}.Validate())
+	errs := Group{Name: " "}.Validate()
+	assert.Len(t, errs, 1)
+	assert.Equal(t, ErrorRequired, errs[0].Code)
+
+	var fields []string
+	for _, e := range (GroupSpot{Marker: Marker{Title: "", Coordinates: Coordinates{Latitude: 91}}}).Validate() {
+		fields = append(fields, e.Field+":"+e.Code)
+	}
+	assert.Equal(t, []string{"marker.title:required", "marker.coordinates.latitude:out_of_range"}, fields)
+}
@@ -0,0 +1,24 @@
+package common
+
+const (
+	ImportStatusNew       string = "new"
+	ImportStatusDuplicate string = "duplicate"
+	ImportStatusError     string = "error"
+)
+
+type ImportReport struct {
+	DryRun     bool        `json:"dryRun"`
+	Total      int         `json:"total"`
+	Imported   int         `json:"imported"`
+	Duplicates int         `json:"duplicates"`
+	Failed     int         `json:"failed"`
+	Rows       []ImportRow `json:"rows"`
+}
+
+type ImportRow struct {
+	Row         int          `json:"row"`
+	Status      string       `json:"status"`
+	Marker      Marker       `json:"marker"`
+	DuplicateOf string       `json:"duplicateOf,omitempty"` // id of the existing spot
+	Errors      []FieldError `json:"errors,omitempty"`
+}
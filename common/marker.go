@@ -4,6 +4,8 @@ type Marker struct {
 	Id          string      `json:"markertId"`
 	Title       string      `json:"title"`
 	Coordinates Coordinates `json:"coordinates"`
+	Layer       string      `json:"layer,omitempty" bson:"-"`     // id of the group of a group spot, empty for own spots
+	LayerName   string      `json:"layerName,omitempty" bson:"-"` // name of the group of a group spot
 }
 
 type Coordinates struct {
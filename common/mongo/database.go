@@ -131,11 +131,12 @@ func (db *Database) createIndex(ctx context.Context, collectionName string, inde
 
 // InstallIndex installs an index for the given collection, if it does not exist yet.
 // An already existing index will not be overwritten.
-func (db *Database) InstallIndex(collectionName string, name string, keys bson.D) error {
+func (db *Database) InstallIndex(collectionName string, name string, keys bson.D, setOptions ...func(opts *options.IndexOptions)) error {
 
 	db.Logger.Infof("Installing mongo db index '%s' in collection '%s'...", name, collectionName)
 
-	ctx, _ := context.WithTimeout(context.Background(), time.Duration(db.Config.Timeout)*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(db.Config.Timeout)*time.Second)
+	defer cancel()
 	exists, err := db.existIndex(ctx, collectionName, name)
 	if err != nil {
 		return errors.Wrapf(err, "Failed to check index '%s' in collection '%s'", name, collectionName)
@@ -150,9 +151,13 @@ func (db *Database) InstallIndex(collectionName string, name string, keys bson.D
 				Background: utils.ToBoolPtr(true), // create the index in the background to avoid any blocking
 			},
 		}
+		for _, opt := range setOptions {
+			opt(index.Options)
+		}
 
 		// new context with new timeout for this second operation
-		ctx, _ := context.WithTimeout(context.Background(), time.Duration(db.Config.Timeout)*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(db.Config.Timeout)*time.Second)
+		defer cancel()
 		err = db.createIndex(ctx, collectionName, index)
 		if err != nil {
 			return errors.Wrapf(err, "Failed to create index '%s' in collection '%s'", name, collectionName)
@@ -0,0 +1,10 @@
+package common
+
+type UserPreferences struct {
+	UnitSystem string `json:"unitSystem" bson:"unitSystem"` // metric (default) or imperial
+}
+
+// DefaultPreferences are used for users who have not saved any preferences yet.
+var DefaultPreferences = UserPreferences{
+	UnitSystem: UnitSystemMetric,
+}
@@ -0,0 +1,33 @@
+package common
+
+const (
+	RecommendationSourceOwn    string = "own"
+	RecommendationSourcePublic string = "public"
+)
+
+type RecommendationQuery struct {
+	Fish        string
+	SpotId      string
+	Coordinates *Coordinates
+	Time        string
+	Deep        *float64 // m
+}
+
+type EquipmentStats struct {
+	Equipment   Equipment `bson:"_id"`
+	Catches     int       `bson:"catches"`
+	Fish        int       `bson:"fish"`
+	FishAtTime  int       `bson:"fishAtTime"`
+	FishAtDepth int       `bson:"fishAtDepth"`
+}
+
+type Recommendation struct {
+	Equipment   Equipment `json:"equipment"`
+	Source      string    `json:"source"`
+	Score       float64   `json:"score"`
+	Catches     int       `json:"catches"`
+	Fish        int       `json:"fish"`
+	FishAtTime  int       `json:"fishAtTime"`
+	FishAtDepth int       `json:"fishAtDepth"`
+	Explanation string    `json:"explanation"`
+}
@@ -0,0 +1,39 @@
+package common
+
+import "time"
+
+// The kinds of personal records.
+const (
+	RecordLargest  string = "largest"  // size of a single fish
+	RecordHeaviest string = "heaviest" // weight of a single fish
+	RecordMostFish string = "mostFish" // fish of a species caught at one spot on one day
+)
+
+// Record is the best value of a kind of record of a user for a species with the catch and spot it was set
+// with. Sizes are in cm and weights in kg. A session record refers to the first catch of the session.
+type Record struct {
+	Kind      string     `json:"kind" bson:"kind"`
+	Value     float64    `json:"value" bson:"value"`
+	Unit      string     `json:"unit,omitempty" bson:"unit,omitempty"`
+	CatchId   string     `json:"catchId" bson:"catchId"`
+	SpotId    string     `json:"spotId" bson:"spotId"`
+	SpotTitle string     `json:"spotTitle" bson:"spotTitle"`
+	CaughtAt  *time.Time `json:"caughtAt,omitempty" bson:"caughtAt,omitempty"`
+}
+
+// RecordChange is an entry of the history of the records: a record was set, or revised because the catch it
+// was set with was changed or deleted. Previous is the value before, nil for the first record of its kind.
+type RecordChange struct {
+	Record     `bson:",inline"`
+	Previous   *float64  `json:"previous,omitempty" bson:"previous,omitempty"`
+	RecordedAt time.Time `json:"recordedAt" bson:"recordedAt"`
+}
+
+// PersonalRecords are the current records of a user for a species and their history. Species is the id of
+// the species in the catalog or, for species not in the catalog, the name of the catches.
+type PersonalRecords struct {
+	Species string         `json:"species" bson:"species"`
+	Fish    string         `json:"fish" bson:"fish"`
+	Records []Record       `json:"records" bson:"records"`
+	History []RecordChange `json:"history" bson:"history"`
+}
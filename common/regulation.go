@@ -0,0 +1,43 @@
+package common
+
+// The codes of the regulation warnings.
+const (
+	WarningUndersized   string = "undersized"
+	WarningOversized    string = "oversized"
+	WarningClosedSeason string = "closed_season"
+	WarningBagLimit     string = "bag_limit"
+)
+
+// RegulationRule restricts the catches of a species in a region. Sizes are in cm, closed seasons are day
+// ranges like "02-15" to "04-30", which may span the turn of the year.
+type RegulationRule struct {
+	SpeciesId     string         `json:"speciesId"`
+	MinSize       *float64       `json:"minSize,omitempty"`
+	MaxSize       *float64       `json:"maxSize,omitempty"`
+	BagLimit      *int           `json:"bagLimit,omitempty"`
+	ClosedSeasons []ClosedSeason `json:"closedSeasons,omitempty"`
+}
+
+type ClosedSeason struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// RegionRules are the rules of a region, as returned for coordinates in it.
+type RegionRules struct {
+	RegionId string           `json:"regionId"`
+	Region   string           `json:"region"`
+	SizeUnit string           `json:"sizeUnit"`
+	Rules    []RegulationRule `json:"rules"`
+}
+
+// RegulationWarning tells that a catch breaks a rule of the region it was made in. Field is the path of the
+// catch in the payload, e.g. "catches[1]".
+type RegulationWarning struct {
+	Field     string `json:"field"`
+	Code      string `json:"code"`
+	RegionId  string `json:"regionId"`
+	Region    string `json:"region"`
+	SpeciesId string `json:"speciesId"`
+	Message   string `json:"message"`
+}
@@ -0,0 +1,42 @@
+package common
+
+// SearchResult is a spot found by a full-text search, with a snippet for every matching field.
+type SearchResult struct {
+	SpotId      string        `json:"spotId"`
+	UserId      string        `json:"userId,omitempty"`  // the owner of the spot
+	GroupId     string        `json:"groupId,omitempty"` // the group owning the spot instead of a user
+	Title       string        `json:"title"`
+	Coordinates Coordinates   `json:"coordinates"`
+	Hidden      bool          `json:"locationHidden,omitempty"` // the coordinates are only the rough area
+	Score       float64       `json:"score"`
+	Matches     []SearchMatch `json:"matches"`
+}
+
+// SearchMatch is a field of a spot matching the search, e.g. "catches[0].equipment.bait". Matching words of
+// the snippet are wrapped in <em> tags.
+type SearchMatch struct {
+	Field   string `json:"field"`
+	Snippet string `json:"snippet"`
+}
+
+// SearchHit is a spot found by a full-text search with the relevance score of the database.
+type SearchHit struct {
+	UserId string    `bson:"userId"`
+	Spot   Fish_spot `bson:"spot"`
+	Score  float64   `bson:"score"`
+}
+
+// GroupSearchHit is a spot of a group found by a full-text search with the relevance score of the database.
+type GroupSearchHit struct {
+	Spot  GroupSpot `bson:",inline"`
+	Score float64   `bson:"score"`
+}
+
+// SearchScope are the spots a user can see: the own ones, those the friends share, the public spots of all
+// users but the blocked ones and the spots of the groups the user is a member of.
+type SearchScope struct {
+	UserId     string
+	FriendIds  []string
+	BlockedIds []string
+	GroupIds   []string
+}
@@ -0,0 +1,253 @@
+package common
+
+import (
+	"fmt"
+	"golang.org/x/text/language"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DefaultLocale is the locale of the common names of the species.
+const DefaultLocale string = "en"
+
+var speciesIdPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Species is an entry of the species catalog. The id is a stable slug like "atlantic-cod", catches refer to
+// the species by its common name, one of its aliases or localized names. The localized names are keyed by
+// lower case language tags like "de" or "pt-br"; Name is the name in the locale of the request.
+type Species struct {
+	Id             string            `json:"id" bson:"_id"`
+	Name           string            `json:"name,omitempty" bson:"-"`
+	CommonName     string            `json:"commonName" bson:"commonName"`
+	ScientificName string            `json:"scientificName" bson:"scientificName"`
+	WaterTypes     []string          `json:"waterTypes" bson:"waterTypes"`
+	Family         string            `json:"family" bson:"family"`
+	Aliases        []string          `json:"aliases" bson:"aliases"`
+	LocalizedNames map[string]string `json:"localizedNames,omitempty" bson:"localizedNames,omitempty"`
+}
+
+// IsWaterType tells whether the value is one of the water types fresh, salt or brackish.
+func IsWaterType(value string) bool {
+	return value == WaterTypeFresh || value == WaterTypeSalt || value == WaterTypeBrackish
+}
+
+// Names returns the common name, the aliases and the localized names of the species.
+func (s Species) Names() []string {
+	names := append([]string{s.CommonName}, s.Aliases...)
+	for _, locale := range s.locales() {
+		names = append(names, s.LocalizedNames[locale])
+	}
+	return names
+}
+
+func (s Species) locales() []string {
+	locales := make([]string, 0, len(s.LocalizedNames))
+	for locale := range s.LocalizedNames {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// LocalizedName returns the name of the species in the first of the locales it has a name for. A regional
+// locale like "de-at" falls back to its language "de", the last resort is the common name.
+func (s Species) LocalizedName(locales []string) string {
+	for _, locale := range locales {
+		if locale == DefaultLocale {
+			return s.CommonName
+		}
+		if name, ok := s.LocalizedNames[locale]; ok {
+			return name
+		}
+		if base, _, found := strings.Cut(locale, "-"); found {
+			if base == DefaultLocale {
+				return s.CommonName
+			}
+			if name, ok := s.LocalizedNames[base]; ok {
+				return name
+			}
+		}
+	}
+	return s.CommonName
+}
+
+// AcceptedLocales returns the lower case language tags of an Accept-Language header, the most preferred first.
+// Invalid headers and the wildcard yield no locales.
+func AcceptedLocales(header string) []string {
+	tags, _, err := language.ParseAcceptLanguage(header)
+	if err != nil {
+		return nil
+	}
+
+	var locales []string
+	for _, tag := range tags {
+		if tag == language.Und || tag == language.Make("mul") {
+			continue
+		}
+		locales = append(locales, strings.ToLower(tag.String()))
+	}
+	return locales
+}
+
+// HasWaterType tells whether the species lives in the given water type.
+func (s Species) HasWaterType(waterType string) bool {
+	for _, t := range s.WaterTypes {
+		if t == waterType {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks that the species has a slug as id, a common name and at least one known water type.
+func (s Species) Validate() ValidationErrors {
+
+	var errs ValidationErrors
+	if !speciesIdPattern.MatchString(s.Id) {
+		errs = append(errs, FieldError{Field: "id", Code: ErrorInvalid, Message: "id must consist of lower case letters, digits and single dashes"})
+	}
+	if len(strings.TrimSpace(s.CommonName)) == 0 {
+		errs = append(errs, FieldError{Field: "commonName", Code: ErrorRequired, Message: "commonName must not be empty"})
+	}
+	if len(s.WaterTypes) == 0 {
+		errs = append(errs, FieldError{Field: "waterTypes", Code: ErrorRequired, Message: "waterTypes must not be empty"})
+	}
+	for i, t := range s.WaterTypes {
+		if !IsWaterType(t) {
+			errs = append(errs, FieldError{Field: fmt.Sprintf("waterTypes[%d]", i), Code: ErrorInvalid, Message: "water type must be fresh, salt or brackish"})
+		}
+	}
+	for i, alias := range s.Aliases {
+		if len(strings.TrimSpace(alias)) == 0 {
+			errs = append(errs, FieldError{Field: fmt.Sprintf("aliases[%d]", i), Code: ErrorRequired, Message: "alias must not be empty"})
+		}
+	}
+	for _, locale := range s.locales() {
+		name := s.LocalizedNames[locale]
+		field := fmt.Sprintf("localizedNames.%s", locale)
+		if tag, err := language.Parse(locale); err != nil || strings.ToLower(tag.String()) != locale {
+			errs = append(errs, FieldError{Field: field, Code: ErrorInvalid, Message: "locale must be a lower case language tag like de or pt-br"})
+		} else if len(strings.TrimSpace(name)) == 0 {
+			errs = append(errs, FieldError{Field: field, Code: ErrorRequired, Message: "localized name must not be empty"})
+		}
+	}
+	return errs
+}
+
+// NormalizeName returns the name in lower case without diacritics and with single spaces between its words,
+// so that "Brochet", "brochet " and "Bróchet" compare equal.
+func NormalizeName(name string) string {
+	stripped, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), name)
+	if err != nil {
+		stripped = name
+	}
+	fields := strings.FieldsFunc(strings.ToLower(stripped), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(fields, " ")
+}
+
+// SpeciesIndex finds species by their ids, common names, aliases and localized names.
+type SpeciesIndex struct {
+	byId   map[string]Species
+	byName map[string]Species
+}
+
+// NewSpeciesIndex indexes the species. A name shared by several species resolves to the species having it
+// as common name, then as alias.
+func NewSpeciesIndex(species []Species) SpeciesIndex {
+	index := SpeciesIndex{byId: map[string]Species{}, byName: map[string]Species{}}
+	for _, s := range species {
+		index.byId[s.Id] = s
+		for _, name := range s.LocalizedNames {
+			index.byName[NormalizeName(name)] = s
+		}
+	}
+	for _, s := range species {
+		for _, alias := range s.Aliases {
+			index.byName[NormalizeName(alias)] = s
+		}
+	}
+	for _, s := range species {
+		index.byName[NormalizeName(s.CommonName)] = s
+	}
+	return index
+}
+
+// Get returns the species with the given id.
+func (i SpeciesIndex) Get(id string) (Species, bool) {
+	s, ok := i.byId[id]
+	return s, ok
+}
+
+// Resolve returns the species with the given name, ignoring case, diacritics and punctuation.
+func (i SpeciesIndex) Resolve(name string) (Species, bool) {
+	normalized := NormalizeName(name)
+	if len(normalized) == 0 {
+		return Species{}, false
+	}
+	s, ok := i.byName[normalized]
+	return s, ok
+}
+
+// Link sets the species id of a catch named by a known species and replaces the name with the common name
+// of the species. Catches with a known id only get the common name. It returns false, if the catch names no
+// species of the catalog, and leaves it as free text then.
+func (i SpeciesIndex) Link(catch *Catch) bool {
+	s, ok := i.Get(catch.SpeciesId)
+	if !ok {
+		s, ok = i.Resolve(catch.Fish)
+	}
+	if !ok {
+		return false
+	}
+	catch.SpeciesId = s.Id
+	catch.Fish = s.CommonName
+	return true
+}
+
+// Catalog returns a lookup telling whether a species id is known.
+func (i SpeciesIndex) Catalog() SpeciesCatalog {
+	return func(id string) bool {
+		_, ok := i.Get(id)
+		return ok
+	}
+}
+
+// Matches tells whether one of the names or the scientific name of the species contains the query, ignoring case.
+func (s Species) Matches(query string) bool {
+	query = strings.ToLower(strings.TrimSpace(query))
+	for _, name := range append(s.Names(), s.ScientificName) {
+		if strings.Contains(strings.ToLower(name), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// SpeciesSuggestion is a species proposed for a partially typed name. Matched is the name, alias or scientific
+// name the input matched, Catches the number of catches of the species by the user.
+type SpeciesSuggestion struct {
+	Id      string  `json:"id"`
+	Name    string  `json:"name"`
+	Matched string  `json:"matched"`
+	Score   float64 `json:"score"`
+	Catches int     `json:"catches"`
+}
+
+// SpeciesMigration reports the linking of the catches to the species catalog.
+type SpeciesMigration struct {
+	Linked    int                `json:"linked"`
+	Unmatched []UnmatchedSpecies `json:"unmatched"`
+}
+
+// UnmatchedSpecies is a species name of catches which matches no species of the catalog.
+type UnmatchedSpecies struct {
+	Fish    string `json:"fish"`
+	Catches int    `json:"catches"`
+}
@@ -0,0 +1,128 @@
+package common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSpeciesValidate(t *testing.T) {
+
+	t.Parallel()
+
+	valid := Species{Id: "atlantic-cod", CommonName: "Atlantic Cod", WaterTypes: []string{WaterTypeSalt, WaterTypeBrackish}, Aliases: []string{"Cod"}}
+	assert.Empty(t, valid.Validate())
+
+	invalid := Species{Id: "Atlantic Cod", WaterTypes: []string{"lake"}, Aliases: []string{" "}}
+	var fields []string
+	for _, e := range invalid.Validate() {
+		fields = append(fields, e.Field+":"+e.Code)
+	}
+	assert.Equal(t, []string{"id:invalid", "commonName:required", "waterTypes[0]:invalid", "aliases[0]:required"}, fields)
+
+	assert.Equal(t, "waterTypes", Species{Id: "cod", CommonName: "Cod"}.Validate()[0].Field)
+}
+
+func TestSpeciesCatalog(t *testing.T) {
+
+	t.Parallel()
+
+	catalog := NewSpeciesIndex([]Species{
+		{Id: "northern-pike", CommonName: "The Northern Pike", Aliases: []string{"Pike"}},
+	}).Catalog()
+	assert.True(t, catalog("northern-pike"))
+	assert.False(t, catalog("Pike"))
+	assert.False(t, catalog(""))
+}
+
+func TestSpeciesLocalizedName(t *testing.T) {
+
+	t.Parallel()
+
+	pike := Species{CommonName: "The Northern Pike", ScientificName: "Esox lucius", LocalizedNames: map[string]string{"de": "Hecht", "de-ch": "Hächt", "fr": "Brochet"}}
+
+	assert.Equal(t, "Hecht", pike.LocalizedName([]string{"de-at", "fr"}))
+	assert.Equal(t, "Hächt", pike.LocalizedName([]string{"de-ch"}))
+	assert.Equal(t, "Brochet", pike.LocalizedName([]string{"it", "fr-ca"}))
+	assert.Equal(t, "The Northern Pike", pike.LocalizedName([]string{"en-gb", "de"}))
+	assert.Equal(t, "The Northern Pike", pike.LocalizedName(nil))
+
+	assert.Equal(t, []string{"The Northern Pike", "Hecht", "Hächt", "Brochet"}, pike.Names())
+	assert.True(t, pike.Matches("hecht"))
+	assert.True(t, pike.Matches("lucius"))
+	assert.False(t, pike.Matches("perch"))
+}
+
+func TestAcceptedLocales(t *testing.T) {
+
+	t.Parallel()
+
+	assert.Equal(t, []string{"de-ch", "de", "en"}, AcceptedLocales("de-CH, en;q=0.5, de;q=0.9"))
+	assert.Empty(t, AcceptedLocales("*"))
+	assert.Empty(t, AcceptedLocales(""))
+	assert.Empty(t, AcceptedLocales("1234567890"))
+}
+
+func TestSpeciesValidateLocalizedNames(t *testing.T) {
+
+	t.Parallel()
+
+	s := Species{Id: "tench", CommonName: "Tench", WaterTypes: []string{WaterTypeFresh}, LocalizedNames: map[string]string{"de": "Schleie", "DE-AT": "Schleie", "fr": ""}}
+	var fields []string
+	for _, e := range s.Validate() {
+		fields = append(fields, e.Field+":"+e.Code)
+	}
+	assert.Equal(t, []string{"localizedNames.DE-AT:invalid", "localizedNames.fr:required"}, fields)
+}
+
+func TestSpeciesIndex(t *testing.T) {
+
+	t.Parallel()
+
+	index := NewSpeciesIndex([]Species{
+		{Id: "northern-pike", CommonName: "The Northern Pike", Aliases: []string{"Pike"}, LocalizedNames: map[string]string{"de": "Hecht"}},
+		{Id: "bonito", CommonName: "Bonito", LocalizedNames: map[string]string{"es": "Bonito"}},
+		{Id: "skipjack", CommonName: "Skipjack Tuna", LocalizedNames: map[string]string{"es": "Bonito"}},
+	})
+
+	for _, name := range []string{"The Northern Pike", "the  northern-pike", "PIKE", "hécht"} {
+		s, ok := index.Resolve(name)
+		assert.True(t, ok, name)
+		assert.Equal(t, "northern-pike", s.Id, name)
+	}
+
+	s, _ := index.Resolve("bonito")
+	assert.Equal(t, "bonito", s.Id)
+
+	_, ok := index.Resolve("Nothern Pike")
+	assert.False(t, ok)
+	_, ok = index.Resolve(" - ")
+	assert.False(t, ok)
+
+	s, ok = index.Get("skipjack")
+	assert.True(t, ok)
+	assert.Equal(t, "Skipjack Tuna", s.CommonName)
+
+	assert.Equal(t, "eglefin", NormalizeName("Églefin"))
+	assert.Equal(t, "mahi mahi", NormalizeName(" Mahi-Mahi "))
+}
+
+func TestSpeciesIndexLink(t *testing.T) {
+
+	t.Parallel()
+
+	index := NewSpeciesIndex([]Species{
+		{Id: "northern-pike", CommonName: "The Northern Pike", Aliases: []string{"Pike"}, LocalizedNames: map[string]string{"de": "Hecht"}},
+	})
+
+	catch := Catch{Fish: "hecht"}
+	assert.True(t, index.Link(&catch))
+	assert.Equal(t, Catch{Fish: "The Northern Pike", SpeciesId: "northern-pike"}, catch)
+
+	catch = Catch{SpeciesId: "northern-pike", Fish: "my big one"}
+	assert.True(t, index.Link(&catch))
+	assert.Equal(t, "The Northern Pike", catch.Fish)
+
+	catch = Catch{Fish: "Nothern Pike"}
+	assert.False(t, index.Link(&catch))
+	assert.Equal(t, Catch{Fish: "Nothern Pike"}, catch)
+}
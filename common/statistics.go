@@ -0,0 +1,55 @@
+package common
+
+import "time"
+
+const (
+	WaterTypeSalt     string = "salt"
+	WaterTypeFresh    string = "fresh"
+	WaterTypeBrackish string = "brackish"
+)
+
+type StatisticsFilter struct {
+	SpotId    string
+	From      *time.Time
+	To        *time.Time
+	WaterType string
+}
+
+type CatchStatistics struct {
+	SizeUnit      string            `json:"sizeUnit"`
+	DeepUnit      string            `json:"deepUnit"`
+	Totals        StatisticsTotals  `json:"totals"`
+	BySpecies     []StatisticsGroup `json:"bySpecies"`
+	ByTime        []StatisticsGroup `json:"byTime"`
+	ByBait        []StatisticsGroup `json:"byBait"`
+	ByDepth       []StatisticsGroup `json:"byDepth"`
+	ByMoonPhase   []StatisticsGroup `json:"byMoonPhase"`
+	PersonalBests []PersonalBest    `json:"personalBests"`
+}
+
+type StatisticsTotals struct {
+	Catches   int     `json:"catches" bson:"catches"`
+	Fish      int     `json:"fish" bson:"fish"`
+	AvgSize   float64 `json:"avgSize" bson:"avgSize"`
+	AvgNumber float64 `json:"avgNumber" bson:"avgNumber"`
+	AvgDeep   float64 `json:"avgDeep" bson:"avgDeep"`
+}
+
+type StatisticsGroup struct {
+	Key       string  `json:"key" bson:"_id"`
+	Catches   int     `json:"catches" bson:"catches"`
+	Fish      int     `json:"fish" bson:"fish"`
+	AvgSize   float64 `json:"avgSize" bson:"avgSize"`
+	MaxSize   float64 `json:"maxSize" bson:"maxSize"`
+	AvgNumber float64 `json:"avgNumber" bson:"avgNumber"`
+	AvgDeep   float64 `json:"avgDeep" bson:"avgDeep"`
+}
+
+type PersonalBest struct {
+	Fish      string    `json:"fish" bson:"_id"`
+	Size      float64   `json:"size" bson:"size"`
+	Number    int       `json:"number" bson:"number"`
+	SpotId    string    `json:"spotId" bson:"spotId"`
+	SpotTitle string    `json:"spotTitle" bson:"spotTitle"`
+	Equipment Equipment `json:"equipment" bson:"equipment"`
+}
@@ -0,0 +1,105 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// The types of tackle items.
+const (
+	TackleRod    string = "rod"
+	TackleReel   string = "reel"
+	TackleLine   string = "line"
+	TackleLeader string = "leader"
+	TackleBait   string = "bait"
+	TackleLure   string = "lure"
+
+	MaxTackleNameLength int = 100
+
+	ErrorUnknownItem string = "unknown_item"
+)
+
+var tackleTypes = []string{TackleRod, TackleReel, TackleLine, TackleLeader, TackleBait, TackleLure}
+
+// TackleItem is a piece of gear in the tackle box of a user. Which attributes apply depends on the type:
+// weights are in g, lengths in cm, line strengths in kg and diameters in mm.
+type TackleItem struct {
+	Id           string   `json:"id" bson:"_id"`
+	Type         string   `json:"type" bson:"type"`
+	Name         string   `json:"name" bson:"name"`
+	Brand        string   `json:"brand,omitempty" bson:"brand,omitempty"`
+	Model        string   `json:"model,omitempty" bson:"model,omitempty"`
+	LureType     string   `json:"lureType,omitempty" bson:"lureType,omitempty"` // e.g. spinner, wobbler, soft bait
+	Color        string   `json:"color,omitempty" bson:"color,omitempty"`
+	Weight       *float64 `json:"weight,omitempty" bson:"weight,omitempty"`
+	Length       *float64 `json:"length,omitempty" bson:"length,omitempty"`
+	LineStrength *float64 `json:"lineStrength,omitempty" bson:"lineStrength,omitempty"`
+	Diameter     *float64 `json:"diameter,omitempty" bson:"diameter,omitempty"`
+	Notes        string   `json:"notes,omitempty" bson:"notes,omitempty"`
+}
+
+// IsTackleType tells whether the value is one of the tackle types.
+func IsTackleType(value string) bool {
+	for _, t := range tackleTypes {
+		if t == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks that the item has a known type, a name and positive measures.
+func (t TackleItem) Validate() ValidationErrors {
+
+	var errs ValidationErrors
+	if !IsTackleType(t.Type) {
+		errs = append(errs, FieldError{Field: "type", Code: ErrorInvalid, Message: "type must be one of " + strings.Join(tackleTypes, ", ")})
+	}
+
+	name := strings.TrimSpace(t.Name)
+	if len(name) == 0 {
+		errs = append(errs, FieldError{Field: "name", Code: ErrorRequired, Message: "name must not be empty"})
+	} else if utf8.RuneCountInString(name) > MaxTackleNameLength {
+		errs = append(errs, FieldError{Field: "name", Code: ErrorTooLong, Message: fmt.Sprintf("name must not be longer than %d characters", MaxTackleNameLength)})
+	}
+
+	measures := []struct {
+		field string
+		value *float64
+	}{{"weight", t.Weight}, {"length", t.Length}, {"lineStrength", t.LineStrength}, {"diameter", t.Diameter}}
+	for _, m := range measures {
+		if m.value != nil && *m.value <= 0 {
+			errs = append(errs, FieldError{Field: m.field, Code: ErrorOutOfRange, Message: m.field + " must be positive"})
+		}
+	}
+	return errs
+}
+
+// Link fills the text of the equipment from the referenced items of the tackle box: baits and lures set
+// the bait, leaders the leader, rods, reels and lines make up the name. Slots without items keep their text.
+// Ids not in the tackle box are reported as errors of the given field.
+func (e *Equipment) Link(tackle map[string]TackleItem, field string) ValidationErrors {
+
+	var errs ValidationErrors
+	var gear []string
+	for i, id := range e.ItemIds {
+		item, ok := tackle[id]
+		if !ok {
+			errs = append(errs, FieldError{Field: fmt.Sprintf("%s[%d]", fieldPath(field, "itemIds"), i), Code: ErrorUnknownItem, Message: fmt.Sprintf("item '%s' is not in the tackle box", id)})
+			continue
+		}
+		switch item.Type {
+		case TackleBait, TackleLure:
+			e.Bait = item.Name
+		case TackleLeader:
+			e.Leader = item.Name
+		default:
+			gear = append(gear, item.Name)
+		}
+	}
+	if len(gear) > 0 {
+		e.Name = strings.Join(gear, " + ")
+	}
+	return errs
+}
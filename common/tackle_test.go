@@ -0,0 +1,45 @@
+package common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestTackleItemValidate(t *testing.T) {
+
+	t.Parallel()
+
+	weight := 12.0
+	assert.Empty(t, TackleItem{Type: TackleLure, Name: "Mepps Aglia 3", LureType: "spinner", Weight: &weight}.Validate())
+
+	negative := -1.0
+	var fields []string
+	for _, e := range (TackleItem{Type: "boat", Name: " ", Diameter: &negative}).Validate() {
+		fields = append(fields, e.Field+":"+e.Code)
+	}
+	assert.Equal(t, []string{"type:invalid", "name:required", "diameter:out_of_range"}, fields)
+}
+
+func TestEquipmentLink(t *testing.T) {
+
+	t.Parallel()
+
+	tackle := map[string]TackleItem{
+		"rod":    {Id: "rod", Type: TackleRod, Name: "Spin 270"},
+		"reel":   {Id: "reel", Type: TackleReel, Name: "Shimano 2500"},
+		"lure":   {Id: "lure", Type: TackleLure, Name: "Mepps Aglia 3"},
+		"leader": {Id: "leader", Type: TackleLeader, Name: "Fluoro 0.30"},
+	}
+
+	equipment := Equipment{Name: "shimano2500", Bait: "Worm", ItemIds: []string{"rod", "reel", "lure"}}
+	assert.Empty(t, equipment.Link(tackle, "catches[0].equipment"))
+	assert.Equal(t, Equipment{Name: "Spin 270 + Shimano 2500", Bait: "Mepps Aglia 3", ItemIds: []string{"rod", "reel", "lure"}}, equipment)
+
+	equipment = Equipment{Name: "Old rod", ItemIds: []string{"leader", "gone"}}
+	errs := equipment.Link(tackle, "equipment")
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "equipment.itemIds[1]", errs[0].Field)
+	assert.Equal(t, ErrorUnknownItem, errs[0].Code)
+	assert.Equal(t, "Old rod", equipment.Name)
+	assert.Equal(t, "Fluoro 0.30", equipment.Leader)
+}
@@ -0,0 +1,106 @@
+package common
+
+import (
+	"fmt"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	MaxNotesLength int = 2000
+
+	ErrorUnknownSpot  string = "unknown_spot"
+	ErrorUnknownCatch string = "unknown_catch"
+	ErrorNotFriend    string = "not_friend"
+)
+
+// MaxTripDuration is the longest a trip lasts. Longer trips have a mistyped time or were never ended.
+const MaxTripDuration = 30 * 24 * time.Hour
+
+// Trip is an outing of a user: the time it lasted, the spots visited, the other users who came along and
+// the catches made. A trip without catches is a blank, which still counts as effort.
+type Trip struct {
+	Id         string     `json:"id" bson:"_id"`
+	Start      time.Time  `json:"start" bson:"start"`
+	End        *time.Time `json:"end,omitempty" bson:"end,omitempty"` // nil while the trip is going on
+	SpotIds    []string   `json:"spotIds" bson:"spotIds"`
+	Companions []string   `json:"companions" bson:"companions"` // ids of the users
+	CatchIds   []string   `json:"catchIds" bson:"catchIds"`
+	Baits      []string   `json:"baits,omitempty" bson:"baits,omitempty"` // baits and lures fished, also those without a bite
+	Notes      string     `json:"notes,omitempty" bson:"notes,omitempty"`
+}
+
+// Hours returns the duration of the trip in hours. A trip going on lasts until now.
+func (t Trip) Hours(now time.Time) float64 {
+	end := t.EndAt(now)
+	if end.Before(t.Start) {
+		return 0
+	}
+	return end.Sub(t.Start).Hours()
+}
+
+// EndAt returns the end of the trip. A trip going on lasts until now, but at most MaxTripDuration.
+func (t Trip) EndAt(now time.Time) time.Time {
+	if t.End != nil {
+		return *t.End
+	}
+	if now.Sub(t.Start) > MaxTripDuration {
+		return t.Start.Add(MaxTripDuration)
+	}
+	return now
+}
+
+// Validate checks the times and notes of the trip and that its references are not empty. Whether the
+// referenced spots, catches and users exist is up to the caller.
+func (t Trip) Validate() ValidationErrors {
+
+	var errs ValidationErrors
+	if t.Start.IsZero() {
+		errs = append(errs, FieldError{Field: "start", Code: ErrorRequired, Message: "start must be set"})
+	} else if t.End != nil && t.End.Before(t.Start) {
+		errs = append(errs, FieldError{Field: "end", Code: ErrorOutOfRange, Message: "end must not be before start"})
+	} else if t.End != nil && t.End.Sub(t.Start) > MaxTripDuration {
+		errs = append(errs, FieldError{Field: "end", Code: ErrorOutOfRange, Message: fmt.Sprintf("a trip must not last longer than %d days", int(MaxTripDuration.Hours()/24))})
+	}
+
+	if utf8.RuneCountInString(t.Notes) > MaxNotesLength {
+		errs = append(errs, FieldError{Field: "notes", Code: ErrorTooLong, Message: fmt.Sprintf("notes must not be longer than %d characters", MaxNotesLength)})
+	}
+
+	errs = append(errs, requireIds("spotIds", t.SpotIds)...)
+	errs = append(errs, requireIds("companions", t.Companions)...)
+	errs = append(errs, requireIds("catchIds", t.CatchIds)...)
+	return errs
+}
+
+func requireIds(field string, ids []string) ValidationErrors {
+	var errs ValidationErrors
+	for i, id := range ids {
+		if len(id) == 0 {
+			errs = append(errs, FieldError{Field: fmt.Sprintf("%s[%d]", field, i), Code: ErrorRequired, Message: "id must not be empty"})
+		}
+	}
+	return errs
+}
+
+// TripGroup are the totals of the catches of a trip with the same species or at the same spot. Key is the
+// species key or the spot id, Name the name of the species or the title of the spot.
+type TripGroup struct {
+	Key     string `json:"key"`
+	Name    string `json:"name"`
+	Catches int    `json:"catches"`
+	Fish    int    `json:"fish"`
+}
+
+// TripSummary are the totals of a trip, overall and by species and spot. Spots visited without a catch are
+// listed with zero totals.
+type TripSummary struct {
+	TripId      string      `json:"tripId"`
+	Hours       float64     `json:"hours"`
+	Blank       bool        `json:"blank"`
+	Catches     int         `json:"catches"`
+	Fish        int         `json:"fish"`
+	FishPerHour float64     `json:"fishPerHour"`
+	BySpecies   []TripGroup `json:"bySpecies"`
+	BySpot      []TripGroup `json:"bySpot"`
+}
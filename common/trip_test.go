@@ -0,0 +1,47 @@
+package common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestTripValidate(t *testing.T) {
+
+	t.Parallel()
+
+	start := time.Date(2023, 6, 3, 5, 0, 0, 0, time.UTC)
+	end := start.Add(-time.Hour)
+
+	assert.Empty(t, Trip{Start: start, SpotIds: []string{"lake"}}.Validate())
+
+	var fields []string
+	for _, e := range (Trip{Start: start, End: &end, Companions: []string{""}}).Validate() {
+		fields = append(fields, e.Field+":"+e.Code)
+	}
+	assert.Equal(t, []string{"end:out_of_range", "companions[0]:required"}, fields)
+
+	end = start.AddDate(1, 0, 0)
+	errs := Trip{Start: start, End: &end}.Validate()
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "end", errs[0].Field)
+	assert.Equal(t, ErrorOutOfRange, errs[0].Code)
+
+	errs = Trip{}.Validate()
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "start", errs[0].Field)
+	assert.Equal(t, ErrorRequired, errs[0].Code)
+}
+
+func TestTripHours(t *testing.T) {
+
+	t.Parallel()
+
+	start := time.Date(2023, 6, 3, 5, 0, 0, 0, time.UTC)
+	end := start.Add(150 * time.Minute)
+
+	assert.Equal(t, 2.5, Trip{Start: start, End: &end}.Hours(start))
+	assert.Equal(t, 1.0, Trip{Start: start}.Hours(start.Add(time.Hour)))
+	assert.Equal(t, 0.0, Trip{Start: start}.Hours(start.Add(-time.Hour)))
+	assert.Equal(t, MaxTripDuration.Hours(), Trip{Start: start}.Hours(start.AddDate(1, 0, 0)))
+}
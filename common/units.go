@@ -0,0 +1,140 @@
+package common
+
+import (
+	"fishfishes_backend/common/utils"
+	"fmt"
+)
+
+const (
+	UnitSystemMetric   string = "metric"
+	UnitSystemImperial string = "imperial"
+
+	UnitCentimeter string = "cm"
+	UnitMillimeter string = "mm"
+	UnitInch       string = "in"
+	UnitMeter      string = "m"
+	UnitFoot       string = "ft"
+	UnitKilogram   string = "kg"
+	UnitGram       string = "g"
+	UnitPound      string = "lb"
+	UnitOunce      string = "oz"
+)
+
+// Factors to the canonical units, which are cm for sizes, m for depths and kg for weights.
+var (
+	sizeUnits   = map[string]float64{UnitCentimeter: 1, UnitMillimeter: 0.1, UnitInch: 2.54}
+	depthUnits  = map[string]float64{UnitMeter: 1, UnitFoot: 0.3048}
+	weightUnits = map[string]float64{UnitKilogram: 1, UnitGram: 0.001, UnitPound: 0.45359237, UnitOunce: 0.028349523125}
+)
+
+// unitScale is the number of decimals a converted value in the given unit is rounded to.
+var unitScale = map[string]int{
+	UnitCentimeter: 1, UnitMillimeter: 0, UnitInch: 1,
+	UnitMeter: 1, UnitFoot: 1,
+	UnitKilogram: 3, UnitGram: 0, UnitPound: 2, UnitOunce: 1,
+}
+
+// IsUnitSystem returns true if the value is a known unit system.
+func IsUnitSystem(value string) bool {
+	return value == UnitSystemMetric || value == UnitSystemImperial
+}
+
+// ConvertSize converts a size between two size units. An empty unit means cm.
+func ConvertSize(value float64, from, to string) (float64, error) {
+	return convert(sizeUnits, UnitCentimeter, value, from, to)
+}
+
+// ConvertDepth converts a depth between two depth units. An empty unit means m.
+func ConvertDepth(value float64, from, to string) (float64, error) {
+	return convert(depthUnits, UnitMeter, value, from, to)
+}
+
+// ConvertWeight converts a weight between two weight units. An empty unit means kg.
+func ConvertWeight(value float64, from, to string) (float64, error) {
+	return convert(weightUnits, UnitKilogram, value, from, to)
+}
+
+func convert(units map[string]float64, canonical string, value float64, from, to string) (float64, error) {
+	if len(from) == 0 {
+		from = canonical
+	}
+	if len(to) == 0 {
+		to = canonical
+	}
+
+	fromFactor, ok := units[from]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit '%s'", from)
+	}
+	toFactor, ok := units[to]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit '%s'", to)
+	}
+	if from == to {
+		return value, nil
+	}
+
+	return utils.ScaleHalfUp(value*fromFactor/toFactor, unitScale[to]), nil
+}
+
+// unitsOf returns the size, depth and weight units of a unit system.
+func unitsOf(system string) (string, string, string) {
+	if system == UnitSystemImperial {
+		return UnitInch, UnitFoot, UnitPound
+	}
+	return UnitCentimeter, UnitMeter, UnitKilogram
+}
+
+// SizeUnitOf returns the size unit of a unit system.
+func SizeUnitOf(system string) string {
+	size, _, _ := unitsOf(system)
+	return size
+}
+
+// DepthUnitOf returns the depth unit of a unit system.
+func DepthUnitOf(system string) string {
+	_, depth, _ := unitsOf(system)
+	return depth
+}
+
+// WeightUnitOf returns the weight unit of a unit system.
+func WeightUnitOf(system string) string {
+	_, _, weight := unitsOf(system)
+	return weight
+}
+
+// ToUnitSystem returns the catch with size, depth and weight converted to the units of the given system.
+// The catch must have valid units; catches without units are taken as metric.
+func (c Catch) ToUnitSystem(system string) (Catch, error) {
+
+	sizeUnit, depthUnit, weightUnit := unitsOf(system)
+
+	size, err := ConvertSize(float64(c.Size), c.SizeUnit, sizeUnit)
+	if err != nil {
+		return c, err
+	}
+	depth, err := ConvertDepth(c.Deep, c.DeepUnit, depthUnit)
+	if err != nil {
+		return c, err
+	}
+	if c.Weight != nil {
+		weight, err := ConvertWeight(*c.Weight, c.WeightUnit, weightUnit)
+		if err != nil {
+			return c, err
+		}
+		c.Weight = &weight
+		c.WeightUnit = weightUnit
+	}
+
+	c.Size = float32(size)
+	c.SizeUnit = sizeUnit
+	c.Deep = depth
+	c.DeepUnit = depthUnit
+
+	return c, nil
+}
+
+// ToMetric returns the catch in the canonical units it is stored with.
+func (c Catch) ToMetric() (Catch, error) {
+	return c.ToUnitSystem(UnitSystemMetric)
+}
@@ -0,0 +1,84 @@
+package common
+
+import (
+	"fishfishes_backend/common/utils"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+
+	t.Parallel()
+
+	type test struct {
+		convert  func(value float64, from, to string) (float64, error)
+		value    float64
+		from     string
+		to       string
+		expected float64
+		hasError bool
+	}
+
+	cases := map[string]test{
+		"inch to cm":           {convert: ConvertSize, value: 28.5, from: UnitInch, to: UnitCentimeter, expected: 72.4},
+		"cm to inch":           {convert: ConvertSize, value: 72, from: UnitCentimeter, to: UnitInch, expected: 28.3},
+		"mm to default":        {convert: ConvertSize, value: 455, from: UnitMillimeter, to: "", expected: 45.5},
+		"same unit untouched":  {convert: ConvertSize, value: 45.55, from: UnitCentimeter, to: UnitCentimeter, expected: 45.55},
+		"feet to m":            {convert: ConvertDepth, value: 10, from: UnitFoot, to: UnitMeter, expected: 3},
+		"m to feet":            {convert: ConvertDepth, value: 3.5, from: "", to: UnitFoot, expected: 11.5},
+		"pound to kg":          {convert: ConvertWeight, value: 4.4, from: UnitPound, to: UnitKilogram, expected: 1.996},
+		"gram to kg":           {convert: ConvertWeight, value: 1250, from: UnitGram, to: UnitKilogram, expected: 1.25},
+		"kg to ounce":          {convert: ConvertWeight, value: 1, from: UnitKilogram, to: UnitOunce, expected: 35.3},
+		"unknown source unit":  {convert: ConvertSize, value: 1, from: "yard", to: UnitCentimeter, hasError: true},
+		"depth unit for sizes": {convert: ConvertSize, value: 1, from: UnitFoot, to: UnitCentimeter, hasError: true},
+	}
+
+	for name, tc := range cases {
+
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := tc.convert(tc.value, tc.from, tc.to)
+			if tc.hasError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.InDelta(t, tc.expected, result, 0.0001)
+		})
+	}
+}
+
+func TestCatchToUnitSystem(t *testing.T) {
+
+	t.Parallel()
+
+	catch := Catch{
+		Fish:       "Common Carp",
+		Number:     1,
+		Size:       30,
+		SizeUnit:   UnitInch,
+		Weight:     utils.ToFloat64Ptr(22),
+		WeightUnit: UnitPound,
+		Deep:       12,
+		DeepUnit:   UnitFoot,
+	}
+
+	metric, err := catch.ToMetric()
+	assert.NoError(t, err)
+	assert.InDelta(t, 76.2, metric.Size, 0.0001)
+	assert.Equal(t, UnitCentimeter, metric.SizeUnit)
+	assert.InDelta(t, 9.979, *metric.Weight, 0.0001)
+	assert.Equal(t, UnitKilogram, metric.WeightUnit)
+	assert.InDelta(t, 3.7, metric.Deep, 0.0001)
+	assert.Equal(t, UnitMeter, metric.DeepUnit)
+	assert.InDelta(t, 22, *catch.Weight, 0.0001, "the original catch must not change")
+
+	imperial, err := metric.ToUnitSystem(UnitSystemImperial)
+	assert.NoError(t, err)
+	assert.InDelta(t, 30, imperial.Size, 0.0001)
+	assert.InDelta(t, 22, *imperial.Weight, 0.0001)
+	assert.InDelta(t, 12.1, imperial.Deep, 0.0001)
+}
@@ -0,0 +1,129 @@
+package common
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	MaxTitleLength int = 100
+
+	ErrorRequired       string = "required"
+	ErrorInvalid        string = "invalid"
+	ErrorOutOfRange     string = "out_of_range"
+	ErrorTooLong        string = "too_long"
+	ErrorUnknownSpecies string = "unknown_species"
+	ErrorUnknownUnit    string = "unknown_unit"
+)
+
+// FieldError describes why the value of a field was rejected. Field is the path of the field in the JSON payload.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ValidationErrors is the list of all field errors of a payload.
+type ValidationErrors []FieldError
+
+func (v ValidationErrors) Error() string {
+	var messages []string
+	for _, e := range v {
+		messages = append(messages, fmt.Sprintf("%s: %s", e.Field, e.Message))
+	}
+	return strings.Join(messages, ", ")
+}
+
+// SpeciesCatalog tells whether a species id is known.
+type SpeciesCatalog func(id string) bool
+
+// Validate checks the spot and all of its catches.
+func (s Fish_spot) Validate(species SpeciesCatalog) ValidationErrors {
+
+	var errs ValidationErrors
+	errs = append(errs, s.Marker.Validate("marker")...)
+	if !IsVisibility(s.Visibility) {
+		errs = append(errs, FieldError{Field: "visibility", Code: ErrorInvalid, Message: "visibility must be private, friends or public"})
+	}
+	for i, catch := range s.Catches {
+		errs = append(errs, catch.Validate(fmt.Sprintf("catches[%d]", i), species)...)
+	}
+	return errs
+}
+
+// Validate checks that the marker has a title of at most MaxTitleLength characters and valid coordinates.
+func (m Marker) Validate(field string) ValidationErrors {
+
+	var errs ValidationErrors
+
+	title := strings.TrimSpace(m.Title)
+	if len(title) == 0 {
+		errs = append(errs, FieldError{Field: fieldPath(field, "title"), Code: ErrorRequired, Message: "title must not be empty"})
+	} else if utf8.RuneCountInString(title) > MaxTitleLength {
+		errs = append(errs, FieldError{Field: fieldPath(field, "title"), Code: ErrorTooLong, Message: fmt.Sprintf("title must not be longer than %d characters", MaxTitleLength)})
+	}
+
+	return append(errs, m.Coordinates.Validate(fieldPath(field, "coordinates"))...)
+}
+
+// Validate checks that latitude and longitude are numbers within their ranges.
+func (c Coordinates) Validate(field string) ValidationErrors {
+
+	var errs ValidationErrors
+	if math.IsNaN(c.Latitude) || c.Latitude < -90 || c.Latitude > 90 {
+		errs = append(errs, FieldError{Field: fieldPath(field, "latitude"), Code: ErrorOutOfRange, Message: "latitude must be between -90 and 90"})
+	}
+	if math.IsNaN(c.Longitude) || c.Longitude < -180 || c.Longitude > 180 {
+		errs = append(errs, FieldError{Field: fieldPath(field, "longitude"), Code: ErrorOutOfRange, Message: "longitude must be between -180 and 180"})
+	}
+	return errs
+}
+
+// Validate checks that the catch names a species, by a known id or as free text, and has a positive number and size and no negative depth.
+func (c Catch) Validate(field string, species SpeciesCatalog) ValidationErrors {
+
+	var errs ValidationErrors
+
+	if len(c.SpeciesId) > 0 {
+		if species != nil && !species(c.SpeciesId) {
+			errs = append(errs, FieldError{Field: fieldPath(field, "speciesId"), Code: ErrorUnknownSpecies, Message: fmt.Sprintf("species '%s' is not in the catalog", c.SpeciesId)})
+		}
+	} else if len(strings.TrimSpace(c.Fish)) == 0 {
+		errs = append(errs, FieldError{Field: fieldPath(field, "id"), Code: ErrorRequired, Message: "species must not be empty"})
+	}
+	if c.Number < 1 {
+		errs = append(errs, FieldError{Field: fieldPath(field, "number"), Code: ErrorOutOfRange, Message: "number must be at least 1"})
+	}
+	if c.Size <= 0 {
+		errs = append(errs, FieldError{Field: fieldPath(field, "size"), Code: ErrorOutOfRange, Message: "size must be positive"})
+	}
+	if c.Deep < 0 {
+		errs = append(errs, FieldError{Field: fieldPath(field, "deep"), Code: ErrorOutOfRange, Message: "deep must not be negative"})
+	}
+	if c.Weight != nil && *c.Weight <= 0 {
+		errs = append(errs, FieldError{Field: fieldPath(field, "weight"), Code: ErrorOutOfRange, Message: "weight must be positive"})
+	}
+
+	errs = append(errs, validateUnit(fieldPath(field, "sizeUnit"), c.SizeUnit, sizeUnits)...)
+	errs = append(errs, validateUnit(fieldPath(field, "deepUnit"), c.DeepUnit, depthUnits)...)
+	errs = append(errs, validateUnit(fieldPath(field, "weightUnit"), c.WeightUnit, weightUnits)...)
+
+	return errs
+}
+
+func validateUnit(field string, unit string, units map[string]float64) ValidationErrors {
+	if _, ok := units[unit]; len(unit) > 0 && !ok {
+		return ValidationErrors{{Field: field, Code: ErrorUnknownUnit, Message: fmt.Sprintf("unit '%s' is not supported", unit)}}
+	}
+	return nil
+}
+
+// fieldPath joins the path of a field with the name of one of its children. An empty path is the root.
+func fieldPath(field string, name string) string {
+	if len(field) == 0 {
+		return name
+	}
+	return field + "." + name
+}
@@ -0,0 +1,101 @@
+package common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestFishSpotValidate(t *testing.T) {
+
+	t.Parallel()
+
+	species := func(id string) bool {
+		return id == "tench"
+	}
+	valid := func() Fish_spot {
+		return Fish_spot{
+			Marker: Marker{Title: "Lake", Coordinates: Coordinates{Latitude: 52.5, Longitude: 13.4}},
+			Catches: []Catch{
+				{Fish: "Tench", Number: 1, Size: 35.5, Deep: 2},
+			},
+		}
+	}
+
+	type test struct {
+		modify   func(spot *Fish_spot)
+		expected []string // field:code
+	}
+
+	cases := map[string]test{
+		"valid spot": {
+			modify: func(spot *Fish_spot) {},
+		},
+		"latitude out of range": {
+			modify:   func(spot *Fish_spot) { spot.Marker.Coordinates.Latitude = 500 },
+			expected: []string{"marker.coordinates.latitude:out_of_range"},
+		},
+		"coordinates not a number": {
+			modify: func(spot *Fish_spot) {
+				spot.Marker.Coordinates = Coordinates{Latitude: math.NaN(), Longitude: math.NaN()}
+			},
+			expected: []string{"marker.coordinates.latitude:out_of_range", "marker.coordinates.longitude:out_of_range"},
+		},
+		"longitude out of range": {
+			modify:   func(spot *Fish_spot) { spot.Marker.Coordinates.Longitude = -180.5 },
+			expected: []string{"marker.coordinates.longitude:out_of_range"},
+		},
+		"empty title": {
+			modify:   func(spot *Fish_spot) { spot.Marker.Title = "  " },
+			expected: []string{"marker.title:required"},
+		},
+		"long title": {
+			modify:   func(spot *Fish_spot) { spot.Marker.Title = strings.Repeat("ä", MaxTitleLength+1) },
+			expected: []string{"marker.title:too_long"},
+		},
+		"invalid catch": {
+			modify: func(spot *Fish_spot) {
+				spot.Catches = append(spot.Catches, Catch{Fish: "", Number: -1, Size: 0, Deep: -3})
+			},
+			expected: []string{
+				"catches[1].id:required",
+				"catches[1].number:out_of_range",
+				"catches[1].size:out_of_range",
+				"catches[1].deep:out_of_range",
+			},
+		},
+		"unknown species id": {
+			modify:   func(spot *Fish_spot) { spot.Catches[0].SpeciesId = "nothern-pike" },
+			expected: []string{"catches[0].speciesId:unknown_species"},
+		},
+		"known species id": {
+			modify: func(spot *Fish_spot) { spot.Catches[0] = Catch{SpeciesId: "tench", Number: 1, Size: 20} },
+		},
+		"unknown visibility": {
+			modify:   func(spot *Fish_spot) { spot.Visibility = "everyone" },
+			expected: []string{"visibility:invalid"},
+		},
+		"free text species": {
+			modify: func(spot *Fish_spot) { spot.Catches[0].Fish = "Nothern Pike" },
+		},
+	}
+
+	for name, tc := range cases {
+
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			spot := valid()
+			tc.modify(&spot)
+
+			var result []string
+			for _, e := range spot.Validate(species) {
+				result = append(result, e.Field+":"+e.Code)
+			}
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package common
+
+import "time"
+
+type Weather struct {
+	ObservedAt    time.Time `json:"observedAt"`
+	Pressure      float64   `json:"pressure"`      // hPa
+	WindSpeed     float64   `json:"windSpeed"`     // m/s
+	WindDirection int       `json:"windDirection"` // degrees, 0 = north
+	Temperature   float64   `json:"temperature"`   // °C
+	CloudCover    int       `json:"cloudCover"`    // percent
+	Unavailable   bool      `json:"unavailable,omitempty"`
+}
+
+// CatchReference addresses a single catch within the catches of a stored spot. The index is where the catch
+// was when it was read; the catch is found by its id, as catches may have been removed since.
+type CatchReference struct {
+	SpotEntityId string      `bson:"_id"`
+	CatchId      string      `bson:"catchId"`
+	Index        int         `bson:"index"`
+	Coordinates  Coordinates `bson:"coordinates"`
+	At           time.Time   `bson:"at"`
+}
@@ -0,0 +1,167 @@
+// Package competitions checks catches against the rules of fishing competitions and ranks the participants.
+package competitions
+
+import (
+	"fishfishes_backend/common"
+	"fishfishes_backend/common/geo"
+	"fishfishes_backend/common/utils"
+	"fishfishes_backend/records"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SubmissionGrace is how long after the end of a competition catches can still be submitted. The results are
+// final after it.
+const SubmissionGrace = 24 * time.Hour
+
+// Open tells whether catches can be submitted to the competition at the given time.
+func Open(competition common.Competition, now time.Time) bool {
+	return !now.Before(competition.Start) && !now.After(competition.End.Add(SubmissionGrace))
+}
+
+// Final tells whether the results of the competition can no longer change.
+func Final(competition common.Competition, now time.Time) bool {
+	return now.After(competition.End.Add(SubmissionGrace))
+}
+
+// Visible tells whether the user can see the competition and take part in it: its organizer and the members
+// of its group can, everybody if it is public. membership is the one of the user in the group, if any.
+func Visible(competition common.Competition, userId string, membership *common.Membership) bool {
+	return competition.Public || competition.OrganizerId == userId || (membership != nil && membership.CanView())
+}
+
+// Allows tells whether the species of the catch is one of the species of the competition. Species not in the
+// catalog match by name, ignoring case.
+func Allows(competition common.Competition, catch common.Catch) bool {
+	if len(competition.Species) == 0 {
+		return true
+	}
+	key := records.SpeciesKey(catch)
+	for _, species := range competition.Species {
+		if species == key || (len(catch.SpeciesId) == 0 && strings.EqualFold(species, catch.Fish)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Submit checks the catch made at the spot against the rules of the competition and returns the submission
+// of the user. It returns an error for each rule the catch breaks.
+func Submit(competition common.Competition, userId string, spot common.Fish_spot, catch common.Catch, now time.Time) (common.Submission, common.ValidationErrors) {
+
+	var errs common.ValidationErrors
+	if !Open(competition, now) {
+		errs = append(errs, common.FieldError{Field: "competitionId", Code: common.ErrorClosed, Message: "the competition does not take submissions"})
+	}
+
+	if catch.CaughtAt == nil {
+		errs = append(errs, common.FieldError{Field: "caughtAt", Code: common.ErrorRequired, Message: "the catch must have a time"})
+	} else if catch.CaughtAt.Before(competition.Start) || catch.CaughtAt.After(competition.End) {
+		errs = append(errs, common.FieldError{Field: "caughtAt", Code: common.ErrorOutsideWindow, Message: "the catch was not made during the competition"})
+	}
+
+	if !Allows(competition, catch) {
+		errs = append(errs, common.FieldError{Field: "species", Code: common.ErrorSpeciesExcluded, Message: "the species does not count in the competition"})
+	}
+
+	boundary := geo.Polygon{geo.Ring(competition.Boundary)}
+	if !boundary.Contains(spot.Marker.Coordinates) {
+		errs = append(errs, common.FieldError{Field: "spotId", Code: common.ErrorOutsideBoundary, Message: "the spot lies outside of the competition area"})
+	}
+
+	metric, err := catch.ToMetric()
+	if err != nil {
+		errs = append(errs, common.FieldError{Field: "size", Code: common.ErrorUnknownUnit, Message: err.Error()})
+	}
+
+	if len(errs) > 0 {
+		return common.Submission{}, errs
+	}
+
+	return common.Submission{
+		Id:            common.SubmissionId(competition.Id, catch.Id),
+		CompetitionId: competition.Id,
+		UserId:        userId,
+		SpotId:        spot.Id,
+		CatchId:       catch.Id,
+		Species:       records.SpeciesKey(catch),
+		Fish:          catch.Fish,
+		Number:        catch.Number,
+		Size:          float64(metric.Size),
+		Coordinates:   spot.Marker.Coordinates,
+		CaughtAt:      *catch.CaughtAt,
+		SubmittedAt:   now,
+	}, nil
+}
+
+// Rank scores the submissions of each participant by the scoring rule of the competition and orders them by
+// score. Of equal scores the one reached first ranks higher; participants reaching the same score at the
+// same time share the rank.
+func Rank(competition common.Competition, submissions []common.Submission, now time.Time) common.Leaderboard {
+
+	byUser := map[string]*common.Standing{}
+	for _, submission := range submissions {
+		standing, ok := byUser[submission.UserId]
+		if !ok {
+			standing = &common.Standing{UserId: submission.UserId}
+			byUser[submission.UserId] = standing
+		}
+		standing.Catches++
+		score(competition.Scoring, standing, submission)
+	}
+
+	standings := []common.Standing{}
+	for _, standing := range byUser {
+		standing.Score = utils.ScaleHalfUp(standing.Score, 1)
+		if competition.Scoring != common.ScoringCount {
+			standing.Unit = common.UnitCentimeter
+		}
+		standings = append(standings, *standing)
+	}
+
+	sort.Slice(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if !a.ReachedAt.Equal(b.ReachedAt) {
+			return a.ReachedAt.Before(b.ReachedAt)
+		}
+		return a.UserId < b.UserId
+	})
+	for i := range standings {
+		standings[i].Rank = i + 1
+		if i > 0 && standings[i].Score == standings[i-1].Score && standings[i].ReachedAt.Equal(standings[i-1].ReachedAt) {
+			standings[i].Rank = standings[i-1].Rank
+		}
+	}
+
+	return common.Leaderboard{
+		CompetitionId: competition.Id,
+		Scoring:       competition.Scoring,
+		Final:         Final(competition, now),
+		Standings:     standings,
+	}
+}
+
+// score adds the submission to the standing. For the largest fish the score is reached with the earliest
+// catch of that size, for the sums with the latest catch.
+func score(scoring string, standing *common.Standing, submission common.Submission) {
+	switch scoring {
+	case common.ScoringLongest:
+		if submission.Size > standing.Score || (submission.Size == standing.Score && submission.CaughtAt.Before(standing.ReachedAt)) {
+			standing.Score = submission.Size
+			standing.ReachedAt = submission.CaughtAt
+			standing.BestCatchId = submission.CatchId
+		}
+		return
+	case common.ScoringTotalLength:
+		standing.Score += submission.Size * float64(submission.Number)
+	case common.ScoringCount:
+		standing.Score += float64(submission.Number)
+	}
+	if submission.CaughtAt.After(standing.ReachedAt) {
+		standing.ReachedAt = submission.CaughtAt
+	}
+}
@@ -0,0 +1,160 @@
+package competitions
+
+import (
+	"fishfishes_backend/common"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+var start = time.Date(2023, 6, 3, 5, 0, 0, 0, time.UTC)
+
+var competition = common.Competition{
+	Id:      "cup",
+	Start:   start,
+	End:     start.Add(8 * time.Hour),
+	Species: []string{"northern-pike", "Zander"},
+	Scoring: common.ScoringLongest,
+	Boundary: []common.Coordinates{
+		{Latitude: 52, Longitude: 13}, {Latitude: 52, Longitude: 14}, {Latitude: 53, Longitude: 14}, {Latitude: 53, Longitude: 13},
+	},
+}
+
+func at(hours float64) *time.Time {
+	t := start.Add(time.Duration(hours * float64(time.Hour)))
+	return &t
+}
+
+func TestSubmit(t *testing.T) {
+
+	t.Parallel()
+
+	inside := common.Fish_spot{Id: "lake", Marker: common.Marker{Coordinates: common.Coordinates{Latitude: 52.5, Longitude: 13.5}}}
+	outside := common.Fish_spot{Id: "sea", Marker: common.Marker{Coordinates: common.Coordinates{Latitude: 54, Longitude: 13.5}}}
+	pike := common.Catch{Id: "c1", Fish: "Pike", SpeciesId: "northern-pike", Number: 1, Size: 31.5, SizeUnit: common.UnitInch, CaughtAt: at(2)}
+
+	submission, errs := Submit(competition, "anna", inside, pike, *at(3))
+	assert.Empty(t, errs)
+	assert.Equal(t, common.Submission{
+		Id: "cup/c1", CompetitionId: "cup", UserId: "anna", SpotId: "lake", CatchId: "c1", Species: "northern-pike", Fish: "Pike",
+		Number: 1, Size: 80, Coordinates: inside.Marker.Coordinates, CaughtAt: *at(2), SubmittedAt: *at(3),
+	}, submission)
+
+	zander := common.Catch{Id: "c2", Fish: "zander", Number: 1, Size: 50, CaughtAt: at(7)}
+	_, errs = Submit(competition, "anna", inside, zander, *at(30))
+	assert.Empty(t, errs)
+
+	type test struct {
+		spot     common.Fish_spot
+		catch    common.Catch
+		now      time.Time
+		expected []string
+	}
+
+	cases := map[string]test{
+		"outside of the area": {spot: outside, catch: pike, now: *at(3), expected: []string{"spotId:outside_boundary"}},
+		"before the start":    {spot: inside, catch: common.Catch{SpeciesId: "northern-pike", CaughtAt: at(-1)}, now: *at(3), expected: []string{"caughtAt:outside_window"}},
+		"without time":        {spot: inside, catch: common.Catch{SpeciesId: "northern-pike"}, now: *at(3), expected: []string{"caughtAt:required"}},
+		"excluded species":    {spot: inside, catch: common.Catch{SpeciesId: "european-perch", CaughtAt: at(1)}, now: *at(3), expected: []string{"species:species_excluded"}},
+		"closed":              {spot: inside, catch: pike, now: *at(40), expected: []string{"competitionId:closed"}},
+	}
+
+	for name, tc := range cases {
+
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var result []string
+			_, errs := Submit(competition, "anna", tc.spot, tc.catch, tc.now)
+			for _, e := range errs {
+				result = append(result, e.Field+":"+e.Code)
+			}
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestVisible(t *testing.T) {
+
+	t.Parallel()
+
+	club := common.Competition{Id: "cup", OrganizerId: "olga", GroupId: "club"}
+	member := &common.Membership{GroupId: "club", UserId: "mia", Role: common.GroupMember, Status: common.MembershipActive}
+	invited := &common.Membership{GroupId: "club", UserId: "ida", Role: common.GroupMember, Status: common.MembershipInvited}
+
+	assert.True(t, Visible(club, "olga", nil))
+	assert.True(t, Visible(club, "mia", member))
+	assert.False(t, Visible(club, "ida", invited))
+	assert.False(t, Visible(club, "nina", nil))
+
+	club.Public = true
+	assert.True(t, Visible(club, "nina", nil))
+}
+
+func TestRank(t *testing.T) {
+
+	t.Parallel()
+
+	submissions := []common.Submission{
+		{UserId: "anna", CatchId: "a1", Number: 1, Size: 60, CaughtAt: *at(1)},
+		{UserId: "anna", CatchId: "a2", Number: 2, Size: 40, CaughtAt: *at(5)},
+		{UserId: "ben", CatchId: "b1", Number: 1, Size: 72.5, CaughtAt: *at(6)},
+		{UserId: "carl", CatchId: "c1", Number: 1, Size: 60, CaughtAt: *at(1)},
+		{UserId: "dora", CatchId: "d1", Number: 5, Size: 30, CaughtAt: *at(4)},
+	}
+
+	type test struct {
+		scoring  string
+		expected []common.Standing
+	}
+
+	cases := map[string]test{
+		"longest": {
+			scoring: common.ScoringLongest,
+			expected: []common.Standing{
+				{Rank: 1, UserId: "ben", Score: 72.5, Unit: "cm", Catches: 1, ReachedAt: *at(6), BestCatchId: "b1"},
+				{Rank: 2, UserId: "anna", Score: 60, Unit: "cm", Catches: 2, ReachedAt: *at(1), BestCatchId: "a1"},
+				{Rank: 2, UserId: "carl", Score: 60, Unit: "cm", Catches: 1, ReachedAt: *at(1), BestCatchId: "c1"},
+				{Rank: 4, UserId: "dora", Score: 30, Unit: "cm", Catches: 1, ReachedAt: *at(4), BestCatchId: "d1"},
+			},
+		},
+		"total length": {
+			scoring: common.ScoringTotalLength,
+			expected: []common.Standing{
+				{Rank: 1, UserId: "dora", Score: 150, Unit: "cm", Catches: 1, ReachedAt: *at(4)},
+				{Rank: 2, UserId: "anna", Score: 140, Unit: "cm", Catches: 2, ReachedAt: *at(5)},
+				{Rank: 3, UserId: "ben", Score: 72.5, Unit: "cm", Catches: 1, ReachedAt: *at(6)},
+				{Rank: 4, UserId: "carl", Score: 60, Unit: "cm", Catches: 1, ReachedAt: *at(1)},
+			},
+		},
+		"count": {
+			scoring: common.ScoringCount,
+			expected: []common.Standing{
+				{Rank: 1, UserId: "dora", Score: 5, Catches: 1, ReachedAt: *at(4)},
+				{Rank: 2, UserId: "anna", Score: 3, Catches: 2, ReachedAt: *at(5)},
+				{Rank: 3, UserId: "carl", Score: 1, Catches: 1, ReachedAt: *at(1)},
+				{Rank: 4, UserId: "ben", Score: 1, Catches: 1, ReachedAt: *at(6)},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			c := competition
+			c.Scoring = tc.scoring
+			leaderboard := Rank(c, submissions, *at(10))
+			assert.False(t, leaderboard.Final)
+			assert.Equal(t, tc.expected, leaderboard.Standings)
+		})
+	}
+
+	assert.True(t, Rank(competition, nil, *at(33)).Final)
+	assert.Empty(t, Rank(competition, nil, *at(33)).Standings)
+}
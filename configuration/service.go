@@ -2,21 +2,45 @@ package configuration
 
 import (
 	"fishfishes_backend/common/mongo"
+	"strings"
 )
 
 type ServiceConfiguration struct {
-	DB            mongo.Config
-	BackendAPIKey string
-	PathServerPem string
-	PathServerKey string
+	DB             mongo.Config
+	BackendAPIKey  string
+	AdminAPIKey    string
+	PathServerPem  string
+	PathServerKey  string
+	WeatherFile    string
+	RegulationsDir string
+	TimeZoneFile   string   // detailed time zone boundaries, the embedded coarse ones if empty
+	RejectedWords  []string // comments containing one of them are rejected
+	FlaggedWords   []string // comments containing one of them are held back for the spot owner
 }
 
-func NewServiceConfiguration(uri, database, apiKey string) *ServiceConfiguration {
+// NewServiceConfiguration creates the configuration. The word lists are comma separated.
+func NewServiceConfiguration(uri, database, apiKey, adminAPIKey, weatherFile, regulationsDir, timeZoneFile, rejectedWords, flaggedWords string) *ServiceConfiguration {
 	return &ServiceConfiguration{
 		DB: mongo.Config{
 			URI:      uri,
 			Database: database,
 		},
-		BackendAPIKey: apiKey,
+		BackendAPIKey:  apiKey,
+		AdminAPIKey:    adminAPIKey,
+		WeatherFile:    weatherFile,
+		RegulationsDir: regulationsDir,
+		TimeZoneFile:   timeZoneFile,
+		RejectedWords:  wordList(rejectedWords),
+		FlaggedWords:   wordList(flaggedWords),
 	}
 }
+
+func wordList(value string) []string {
+	var words []string
+	for _, word := range strings.Split(value, ",") {
+		if word = strings.TrimSpace(word); len(word) > 0 {
+			words = append(words, word)
+		}
+	}
+	return words
+}
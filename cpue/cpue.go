@@ -0,0 +1,267 @@
+// Package cpue computes the catch per unit effort of anglers: how many fish they catch per hour on their
+// trips and how often a trip is successful at all.
+package cpue
+
+import (
+	"fishfishes_backend/astronomy"
+	"fishfishes_backend/common"
+	"fishfishes_backend/common/utils"
+	"fishfishes_backend/records"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// Confidence is the level of the confidence intervals.
+	Confidence float64 = 0.95
+
+	// z is the quantile of the standard normal distribution for the confidence level.
+	z float64 = 1.959964
+)
+
+var dayParts = []string{common.TimeMorning, common.TimeDay, common.TimeAfternoon, common.TimeNight}
+
+type groups map[string]*common.CPUEGroup
+
+// add accounts a trip to the group with the given key: hours of effort and the catches made in the group.
+func (g groups) add(key string, name string, hours float64, catches []common.EffortCatch) {
+	group, ok := g[key]
+	if !ok {
+		group = &common.CPUEGroup{Key: key, Name: name}
+		g[key] = group
+	}
+	group.Trips++
+	group.Hours += hours
+	for _, catch := range catches {
+		group.Catches++
+		group.Fish += catch.Number
+	}
+	if len(catches) > 0 {
+		group.SuccessfulTrips++
+	}
+}
+
+// Compute returns the catch per unit effort of the trips. Trips going on count until now, at most for
+// common.MaxTripDuration.
+//
+// The hours of a trip are split evenly among the spots it visited and among the baits fished, which are the
+// baits of the trip and of its catches. Every trip is effort for every species, as anglers can not choose
+// what bites. By part of the day the hours are split by the sun at the first spot of the trip; trips without
+// spots and catches without a part of the day are left out there. Months are those of the start of the trips.
+func Compute(trips []common.TripEffort, locate records.Locator, now time.Time) common.CPUEAnalytics {
+
+	totals := groups{}
+	bySpot, bySpecies, byMonth, byTimeOfDay, byBait := groups{}, groups{}, groups{}, groups{}, groups{}
+
+	for _, trip := range trips {
+		hours := tripHours(trip, now)
+		totals.add("", "", hours, trip.Catches)
+
+		for _, spot := range trip.Spots {
+			bySpot.add(spot.Id, spot.Title, hours/float64(len(trip.Spots)), filter(trip.Catches, func(c common.EffortCatch) bool {
+				return c.SpotId == spot.Id
+			}))
+		}
+
+		caught := map[string][]common.EffortCatch{}
+		var species []string
+		for _, catch := range trip.Catches {
+			key := catch.SpeciesId
+			if len(key) == 0 {
+				key = catch.Fish
+			}
+			if _, ok := caught[key]; !ok {
+				species = append(species, key)
+			}
+			caught[key] = append(caught[key], catch)
+		}
+		for _, key := range species {
+			// the effort of the species is the effort of all trips, set below
+			bySpecies.add(key, caught[key][0].Fish, 0, caught[key])
+		}
+
+		location := time.UTC
+		if len(trip.Spots) > 0 {
+			location = locate(trip.Spots[0].Coordinates)
+		}
+		start := trip.Start.In(location)
+		byMonth.add(start.Format("01"), start.Month().String(), hours, trip.Catches)
+
+		if len(trip.Spots) > 0 {
+			partHours := dayPartHours(trip, trip.Spots[0].Coordinates, location, now)
+			for _, part := range dayParts {
+				if partHours[part] > 0 {
+					byTimeOfDay.add(part, "", partHours[part], filter(trip.Catches, func(c common.EffortCatch) bool {
+						return c.Time == part
+					}))
+				}
+			}
+		}
+
+		baits := tripBaits(trip)
+		for _, bait := range baits {
+			key := strings.ToLower(bait)
+			byBait.add(key, bait, hours/float64(len(baits)), filter(trip.Catches, func(c common.EffortCatch) bool {
+				return strings.ToLower(strings.TrimSpace(c.Bait)) == key
+			}))
+		}
+	}
+
+	total := common.CPUEGroup{}
+	if t, ok := totals[""]; ok {
+		total = *t
+	}
+	for _, group := range bySpecies {
+		group.Trips = total.Trips
+		group.Hours = total.Hours
+	}
+
+	return common.CPUEAnalytics{
+		Confidence:  Confidence,
+		Totals:      finish(total),
+		BySpot:      byRate(bySpot),
+		BySpecies:   byRate(bySpecies),
+		ByMonth:     byKey(byMonth, nil),
+		ByTimeOfDay: byKey(byTimeOfDay, dayParts),
+		ByBait:      byRate(byBait),
+	}
+}
+
+func tripHours(trip common.TripEffort, now time.Time) float64 {
+	return common.Trip{Start: trip.Start, End: trip.End}.Hours(now)
+}
+
+func filter(catches []common.EffortCatch, keep func(c common.EffortCatch) bool) []common.EffortCatch {
+	var result []common.EffortCatch
+	for _, catch := range catches {
+		if keep(catch) {
+			result = append(result, catch)
+		}
+	}
+	return result
+}
+
+// dayPartHours splits the duration of the trip into the parts of the day at the coordinates, day by day.
+func dayPartHours(trip common.TripEffort, coordinates common.Coordinates, location *time.Location, now time.Time) map[string]float64 {
+
+	end := common.Trip{Start: trip.Start, End: trip.End}.EndAt(now)
+
+	result := map[string]float64{}
+	for from := trip.Start; from.Before(end); {
+		spans := astronomy.DayParts(coordinates, from.In(location))
+		for _, span := range spans {
+			start, stop := span.Start, span.End
+			if start.Before(from) {
+				start = from
+			}
+			if stop.After(end) {
+				stop = end
+			}
+			if stop.After(start) {
+				result[span.Part] += stop.Sub(start).Hours()
+			}
+		}
+		from = spans[len(spans)-1].End
+	}
+	return result
+}
+
+// tripBaits returns the baits fished on the trip, each once whatever its case, in the order they were named.
+func tripBaits(trip common.TripEffort) []string {
+
+	var result []string
+	seen := map[string]bool{}
+	add := func(bait string) {
+		bait = strings.TrimSpace(bait)
+		if len(bait) > 0 && !seen[strings.ToLower(bait)] {
+			seen[strings.ToLower(bait)] = true
+			result = append(result, bait)
+		}
+	}
+	for _, bait := range trip.Baits {
+		add(bait)
+	}
+	for _, catch := range trip.Catches {
+		add(catch.Bait)
+	}
+	return result
+}
+
+// finish computes the rates and intervals of the group and rounds its values.
+func finish(group common.CPUEGroup) common.CPUEGroup {
+
+	if group.Hours > 0 {
+		group.FishPerHour = utils.ScaleHalfUp(float64(group.Fish)/group.Hours, 3)
+		lower, upper := PoissonInterval(group.Fish)
+		group.FishPerHourCI = common.Interval{Lower: utils.ScaleHalfUp(lower/group.Hours, 3), Upper: utils.ScaleHalfUp(upper/group.Hours, 3)}
+	}
+	if group.Trips > 0 {
+		group.SuccessRate = utils.ScaleHalfUp(float64(group.SuccessfulTrips)/float64(group.Trips), 3)
+		lower, upper := WilsonInterval(group.SuccessfulTrips, group.Trips)
+		group.SuccessRateCI = common.Interval{Lower: utils.ScaleHalfUp(lower, 3), Upper: utils.ScaleHalfUp(upper, 3)}
+	}
+	group.Hours = utils.ScaleHalfUp(group.Hours, 2)
+	return group
+}
+
+// byRate returns the groups with the most fish per hour first.
+func byRate(g groups) []common.CPUEGroup {
+	result := []common.CPUEGroup{}
+	for _, group := range g {
+		result = append(result, finish(*group))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].FishPerHour != result[j].FishPerHour {
+			return result[i].FishPerHour > result[j].FishPerHour
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
+
+// byKey returns the groups in the given order of keys or, without one, ordered by key.
+func byKey(g groups, order []string) []common.CPUEGroup {
+	if order == nil {
+		for key := range g {
+			order = append(order, key)
+		}
+		sort.Strings(order)
+	}
+	result := []common.CPUEGroup{}
+	for _, key := range order {
+		if group, ok := g[key]; ok {
+			result = append(result, finish(*group))
+		}
+	}
+	return result
+}
+
+// PoissonInterval returns the confidence interval of the expected count of a Poisson distributed count by
+// Byar's approximation, which holds up for small counts.
+func PoissonInterval(count int) (float64, float64) {
+
+	k := float64(count)
+	lower := 0.0
+	if count > 0 {
+		lower = k * math.Pow(1-1/(9*k)-z/(3*math.Sqrt(k)), 3)
+	}
+	upper := (k + 1) * math.Pow(1-1/(9*(k+1))+z/(3*math.Sqrt(k+1)), 3)
+	return lower, upper
+}
+
+// WilsonInterval returns the Wilson score interval of a proportion of successes among trials, which unlike
+// the normal approximation stays within 0 and 1 for small samples.
+func WilsonInterval(successes int, trials int) (float64, float64) {
+	if trials == 0 {
+		return 0, 0
+	}
+
+	n := float64(trials)
+	p := float64(successes) / n
+	denominator := 1 + z*z/n
+	center := (p + z*z/(2*n)) / denominator
+	half := z / denominator * math.Sqrt(p*(1-p)/n+z*z/(4*n*n))
+	return math.Max(0, center-half), math.Min(1, center+half)
+}
@@ -0,0 +1,168 @@
+package cpue
+
+import (
+	"fishfishes_backend/common"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func utc(common.Coordinates) *time.Location {
+	return time.UTC
+}
+
+var (
+	lake  = common.EffortSpot{Id: "lake", Title: "Lake", Coordinates: common.Coordinates{Latitude: 52.5, Longitude: 13.4}}
+	river = common.EffortSpot{Id: "river", Title: "River", Coordinates: common.Coordinates{Latitude: 52.5, Longitude: 13.4}}
+)
+
+func trip(id string, start time.Time, hours float64, spots []common.EffortSpot, baits []string, catches ...common.EffortCatch) common.TripEffort {
+	end := start.Add(time.Duration(hours * float64(time.Hour)))
+	return common.TripEffort{TripId: id, Start: start, End: &end, Baits: baits, Spots: spots, Catches: catches}
+}
+
+func TestCompute(t *testing.T) {
+
+	t.Parallel()
+
+	// around noon in June the sun is up all the time at the spots, which is the day part of the day
+	june := time.Date(2023, 6, 3, 9, 0, 0, 0, time.UTC)
+	july := time.Date(2023, 7, 8, 9, 0, 0, 0, time.UTC)
+
+	trips := []common.TripEffort{
+		trip("t1", june, 2, []common.EffortSpot{lake}, []string{"Worm"},
+			common.EffortCatch{SpotId: "lake", SpeciesId: "european-perch", Fish: "Perch", Number: 3, Time: common.TimeDay, Bait: "worm"},
+			common.EffortCatch{SpotId: "lake", SpeciesId: "northern-pike", Fish: "Pike", Number: 1, Time: common.TimeDay, Bait: "Spinner"},
+		),
+		trip("t2", july, 2, []common.EffortSpot{lake, river}, []string{"Worm"},
+			common.EffortCatch{SpotId: "river", SpeciesId: "european-perch", Fish: "Perch", Number: 2, Time: common.TimeDay, Bait: "Worm"},
+		),
+		// a blank
+		trip("t3", july.AddDate(0, 0, 1), 4, []common.EffortSpot{river}, []string{"Spinner"}),
+	}
+
+	analytics := Compute(trips, utc, time.Now())
+
+	assert.Equal(t, Confidence, analytics.Confidence)
+	assert.Equal(t, 3, analytics.Totals.Trips)
+	assert.Equal(t, 2, analytics.Totals.SuccessfulTrips)
+	assert.Equal(t, 8.0, analytics.Totals.Hours)
+	assert.Equal(t, 6, analytics.Totals.Fish)
+	assert.Equal(t, 0.75, analytics.Totals.FishPerHour)
+	assert.Equal(t, 0.667, analytics.Totals.SuccessRate)
+
+	type summary struct {
+		Key     string
+		Trips   int
+		Success int
+		Hours   float64
+		Fish    int
+		Rate    float64
+	}
+	summarize := func(groups []common.CPUEGroup) []summary {
+		var result []summary
+		for _, g := range groups {
+			result = append(result, summary{g.Key, g.Trips, g.SuccessfulTrips, g.Hours, g.Fish, g.FishPerHour})
+		}
+		return result
+	}
+
+	// the second trip splits its two hours between lake and river
+	assert.Equal(t, []summary{
+		{"lake", 2, 1, 3, 4, 1.333},
+		{"river", 2, 1, 5, 2, 0.4},
+	}, summarize(analytics.BySpot))
+
+	assert.Equal(t, []summary{
+		{"european-perch", 3, 2, 8, 5, 0.625},
+		{"northern-pike", 3, 1, 8, 1, 0.125},
+	}, summarize(analytics.BySpecies))
+	assert.Equal(t, "Perch", analytics.BySpecies[0].Name)
+
+	assert.Equal(t, []summary{
+		{"06", 1, 1, 2, 4, 2},
+		{"07", 2, 1, 6, 2, 0.333},
+	}, summarize(analytics.ByMonth))
+	assert.Equal(t, "June", analytics.ByMonth[0].Name)
+
+	assert.Equal(t, []summary{
+		{common.TimeDay, 3, 2, 8, 6, 0.75},
+	}, summarize(analytics.ByTimeOfDay))
+
+	// the first trip splits its two hours between the worm and the spinner it caught with
+	assert.Equal(t, []summary{
+		{"worm", 2, 2, 3, 5, 1.667},
+		{"spinner", 2, 1, 5, 1, 0.2},
+	}, summarize(analytics.ByBait))
+	assert.Equal(t, "Worm", analytics.ByBait[0].Name)
+}
+
+func TestComputeDayParts(t *testing.T) {
+
+	t.Parallel()
+
+	// in Berlin in June the sun rises around 2:45 UTC, the morning lasts until about 8:20 UTC
+	start := time.Date(2023, 6, 3, 1, 0, 0, 0, time.UTC)
+	analytics := Compute([]common.TripEffort{trip("t", start, 4, []common.EffortSpot{lake}, nil,
+		common.EffortCatch{SpotId: "lake", Fish: "Tench", Number: 1, Time: common.TimeMorning},
+	)}, utc, time.Now())
+
+	assert.Len(t, analytics.ByTimeOfDay, 2)
+	assert.Equal(t, common.TimeMorning, analytics.ByTimeOfDay[0].Key)
+	assert.Equal(t, 1, analytics.ByTimeOfDay[0].Fish)
+	assert.Equal(t, common.TimeNight, analytics.ByTimeOfDay[1].Key)
+	assert.Equal(t, 0, analytics.ByTimeOfDay[1].Fish)
+	assert.Equal(t, 4.0, analytics.ByTimeOfDay[0].Hours+analytics.ByTimeOfDay[1].Hours)
+}
+
+func TestComputeOngoingTrip(t *testing.T) {
+
+	t.Parallel()
+
+	start := time.Date(2023, 6, 3, 10, 0, 0, 0, time.UTC)
+	analytics := Compute([]common.TripEffort{{TripId: "t", Start: start}}, utc, start.Add(90*time.Minute))
+
+	assert.Equal(t, 1.5, analytics.Totals.Hours)
+	assert.Empty(t, analytics.BySpot)
+	assert.Empty(t, analytics.ByTimeOfDay)
+	assert.Equal(t, 0.0, analytics.Totals.SuccessRate)
+	assert.Equal(t, common.Interval{Lower: 0, Upper: 0.793}, analytics.Totals.SuccessRateCI)
+}
+
+func TestComputeLongTrip(t *testing.T) {
+
+	t.Parallel()
+
+	// a trip never ended counts for at most 30 days, which are split into the parts of each day
+	start := time.Date(2023, 6, 3, 10, 0, 0, 0, time.UTC)
+	analytics := Compute([]common.TripEffort{{TripId: "t", Start: start, Spots: []common.EffortSpot{lake}}}, utc, start.AddDate(1, 0, 0))
+
+	assert.Equal(t, 720.0, analytics.Totals.Hours)
+	assert.Len(t, analytics.ByTimeOfDay, 4)
+	hours := 0.0
+	for _, part := range analytics.ByTimeOfDay {
+		hours += part.Hours
+	}
+	assert.InDelta(t, 720.0, hours, 0.05)
+}
+
+func TestIntervals(t *testing.T) {
+
+	t.Parallel()
+
+	lower, upper := PoissonInterval(0)
+	assert.Equal(t, 0.0, lower)
+	assert.InDelta(t, 3.689, upper, 0.05)
+
+	lower, upper = PoissonInterval(10)
+	assert.InDelta(t, 4.795, lower, 0.01)
+	assert.InDelta(t, 18.39, upper, 0.01)
+
+	lower, upper = WilsonInterval(5, 10)
+	assert.InDelta(t, 0.2366, lower, 0.0001)
+	assert.InDelta(t, 0.7634, upper, 0.0001)
+
+	lower, upper = WilsonInterval(0, 0)
+	assert.Equal(t, 0.0, lower)
+	assert.Equal(t, 0.0, upper)
+}
@@ -0,0 +1,183 @@
+package export
+
+import (
+	"encoding/csv"
+	"fishfishes_backend/common"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FormatCSV  string = "csv"
+	FormatXLSX string = "xlsx"
+)
+
+var catchFormats = []Format{
+	{Name: FormatCSV, ContentType: "text/csv", Extension: "csv"},
+	{Name: FormatXLSX, ContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", Extension: "xlsx"},
+}
+
+// CatchFormat returns the catch log export format with the given name, nil if there is none.
+func CatchFormat(name string) *Format {
+	return findFormat(catchFormats, func(f Format) bool { return strings.EqualFold(f.Name, name) })
+}
+
+// CatchFormatByContentType returns the first catch log format accepted by an Accept header, nil if there is none.
+func CatchFormatByContentType(accept string) *Format {
+	return formatByContentType(catchFormats, accept)
+}
+
+// CatchFilter restricts the catches of the log. Catches without a timestamp are left out if a date range is set.
+type CatchFilter struct {
+	From    *time.Time
+	To      *time.Time
+	Species []string
+}
+
+func (f CatchFilter) matches(catch common.Catch) bool {
+	if f.From != nil || f.To != nil {
+		if catch.CaughtAt == nil ||
+			(f.From != nil && catch.CaughtAt.Before(*f.From)) ||
+			(f.To != nil && catch.CaughtAt.After(*f.To)) {
+			return false
+		}
+	}
+	if len(f.Species) > 0 {
+		for _, fish := range f.Species {
+			if strings.EqualFold(fish, catch.Fish) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// CatchRow is a catch together with the spot it was made at.
+type CatchRow struct {
+	Spot  common.Fish_spot
+	Catch common.Catch
+}
+
+// FlattenCatches returns one row per catch of all spots which passes the filter.
+func FlattenCatches(spots []common.Fish_spot, filter CatchFilter) []CatchRow {
+	var rows []CatchRow
+	for _, spot := range spots {
+		for _, catch := range spot.Catches {
+			if filter.matches(catch) {
+				rows = append(rows, CatchRow{Spot: spot, Catch: catch})
+			}
+		}
+	}
+	return rows
+}
+
+// cell is a value of the catch log, either a string or a number.
+type cell struct {
+	text     string
+	number   float64
+	isNumber bool
+}
+
+func text(value string) cell {
+	return cell{text: value}
+}
+
+func number(value float64) cell {
+	return cell{number: value, isNumber: true, text: strconv.FormatFloat(value, 'f', -1, 64)}
+}
+
+// catchLogHeader returns the column titles; sizes and depths are labeled with the given units.
+func catchLogHeader(sizeUnit, depthUnit string) []string {
+	return []string{
+		"Spot", "Latitude", "Longitude", "Species", "Number",
+		fmt.Sprintf("Size (%s)", sizeUnit), fmt.Sprintf("Depth (%s)", depthUnit),
+		"Caught At", "Time", "Bait", "Leader", "Equipment",
+	}
+}
+
+func catchLogCells(row CatchRow) []cell {
+	caughtAt := ""
+	if row.Catch.CaughtAt != nil {
+		caughtAt = row.Catch.CaughtAt.Format(time.RFC3339)
+	}
+	return []cell{
+		text(row.Spot.Marker.Title),
+		number(row.Spot.Marker.Coordinates.Latitude),
+		number(row.Spot.Marker.Coordinates.Longitude),
+		text(row.Catch.Fish),
+		number(float64(row.Catch.Number)),
+		number(float64(row.Catch.Size)),
+		number(row.Catch.Deep),
+		text(caughtAt),
+		text(row.Catch.Time),
+		text(row.Catch.Equipment.Bait),
+		text(row.Catch.Equipment.Leader),
+		text(row.Catch.Equipment.Name),
+	}
+}
+
+// WriteCatches writes the catch log in the given format. Sizes and depths must be in the given units.
+func WriteCatches(w io.Writer, format string, rows []CatchRow, sizeUnit, depthUnit string) error {
+	switch format {
+	case FormatCSV:
+		return WriteCatchesCSV(w, rows, sizeUnit, depthUnit)
+	case FormatXLSX:
+		return WriteCatchesXLSX(w, rows, sizeUnit, depthUnit)
+	}
+	return fmt.Errorf("unknown format '%s'", format)
+}
+
+// WriteCatchesCSV writes the catch log as CSV with a header line.
+func WriteCatchesCSV(w io.Writer, rows []CatchRow, sizeUnit, depthUnit string) error {
+
+	writer := csv.NewWriter(w)
+	err := writer.Write(catchLogHeader(sizeUnit, depthUnit))
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		cells := catchLogCells(row)
+		record := make([]string, 0, len(cells))
+		for _, c := range cells {
+			record = append(record, c.text)
+		}
+		err = writer.Write(record)
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteCatchesXLSX writes the catch log as a single sheet Excel workbook.
+func WriteCatchesXLSX(w io.Writer, rows []CatchRow, sizeUnit, depthUnit string) error {
+
+	header := catchLogHeader(sizeUnit, depthUnit)
+	headerCells := make([]cell, 0, len(header))
+	for _, title := range header {
+		headerCells = append(headerCells, text(title))
+	}
+
+	workbook, err := newXLSXWriter(w, "Catches")
+	if err != nil {
+		return err
+	}
+	err = workbook.writeRow(headerCells)
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		err = workbook.writeRow(catchLogCells(row))
+		if err != nil {
+			return err
+		}
+	}
+	return workbook.close()
+}
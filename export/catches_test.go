@@ -0,0 +1,105 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fishfishes_backend/common"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"testing"
+	"time"
+)
+
+func catchLog() []common.Fish_spot {
+	morning := time.Date(2023, 5, 1, 6, 30, 0, 0, time.UTC)
+	evening := time.Date(2023, 6, 1, 20, 0, 0, 0, time.UTC)
+	return []common.Fish_spot{
+		{
+			Marker: common.Marker{Title: "Lake, \"North\"", Coordinates: common.Coordinates{Latitude: 52.5, Longitude: 13.4}},
+			Catches: []common.Catch{
+				{Fish: "Tench", Number: 1, Size: 35, Deep: 2.5, CaughtAt: &morning, Time: common.TimeMorning,
+					Equipment: common.Equipment{Name: "Feeder", Bait: "Maggot", Leader: "Mono"}},
+				{Fish: "Perch", Number: 3, Size: 20},
+			},
+		},
+		{
+			Marker:  common.Marker{Title: "River & Sea", Coordinates: common.Coordinates{Latitude: -33.9, Longitude: 151.2}},
+			Catches: []common.Catch{{Fish: "Tench", Number: 2, Size: 41.5, CaughtAt: &evening}},
+		},
+	}
+}
+
+func TestFlattenCatches(t *testing.T) {
+	assert.Len(t, FlattenCatches(catchLog(), CatchFilter{}), 3)
+
+	rows := FlattenCatches(catchLog(), CatchFilter{Species: []string{"tench"}})
+	assert.Len(t, rows, 2)
+	assert.Equal(t, "River & Sea", rows[1].Spot.Marker.Title)
+
+	from := time.Date(2023, 5, 15, 0, 0, 0, 0, time.UTC)
+	rows = FlattenCatches(catchLog(), CatchFilter{From: &from})
+	assert.Len(t, rows, 1)
+	assert.Equal(t, 41.5, float64(rows[0].Catch.Size))
+}
+
+func TestWriteCatchesCSV(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteCatchesCSV(&buf, FlattenCatches(catchLog(), CatchFilter{}), common.UnitCentimeter, common.UnitMeter)
+	assert.NoError(t, err)
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, records, 4)
+	assert.Equal(t, "Size (cm)", records[0][5])
+	assert.Equal(t, []string{"Lake, \"North\"", "52.5", "13.4", "Tench", "1", "35", "2.5",
+		"2023-05-01T06:30:00Z", "Morning", "Maggot", "Mono", "Feeder"}, records[1])
+	assert.Equal(t, "", records[2][7])
+}
+
+func TestWriteCatchesXLSX(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteCatchesXLSX(&buf, FlattenCatches(catchLog(), CatchFilter{}), common.UnitInch, common.UnitFoot)
+	assert.NoError(t, err)
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+
+	parts := map[string]string{}
+	for _, f := range archive.File {
+		r, err := f.Open()
+		assert.NoError(t, err)
+		content, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		parts[f.Name] = string(content)
+	}
+	assert.Contains(t, parts, "[Content_Types].xml")
+	assert.Contains(t, parts, "xl/workbook.xml")
+
+	var sheet struct {
+		Rows []struct {
+			Cells []struct {
+				Ref    string `xml:"r,attr"`
+				Type   string `xml:"t,attr"`
+				Value  string `xml:"v"`
+				Inline string `xml:"is>t"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	assert.NoError(t, xml.Unmarshal([]byte(parts["xl/worksheets/sheet1.xml"]), &sheet))
+	assert.Len(t, sheet.Rows, 4)
+	assert.Equal(t, "Size (in)", sheet.Rows[0].Cells[5].Inline)
+	assert.Equal(t, "River & Sea", sheet.Rows[3].Cells[0].Inline)
+	assert.Equal(t, "B4", sheet.Rows[3].Cells[1].Ref)
+	assert.Equal(t, "", sheet.Rows[3].Cells[1].Type)
+	assert.Equal(t, "-33.9", sheet.Rows[3].Cells[1].Value)
+}
+
+func TestColumnName(t *testing.T) {
+	assert.Equal(t, "A", columnName(0))
+	assert.Equal(t, "Z", columnName(25))
+	assert.Equal(t, "AA", columnName(26))
+	assert.Equal(t, "AZ", columnName(51))
+	assert.Equal(t, "BA", columnName(52))
+}
@@ -0,0 +1,104 @@
+// Package export writes spots and catches into file formats of other applications.
+package export
+
+import (
+	"fishfishes_backend/common"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	FormatGeoJSON string = "geojson"
+	FormatGPX     string = "gpx"
+	FormatKML     string = "kml"
+)
+
+// Format describes an export format.
+type Format struct {
+	Name        string
+	ContentType string
+	Extension   string
+}
+
+var spotFormats = []Format{
+	{Name: FormatGeoJSON, ContentType: "application/geo+json", Extension: "geojson"},
+	{Name: FormatGPX, ContentType: "application/gpx+xml", Extension: "gpx"},
+	{Name: FormatKML, ContentType: "application/vnd.google-earth.kml+xml", Extension: "kml"},
+}
+
+// SpotFormat returns the spot export format with the given name, nil if there is none.
+func SpotFormat(name string) *Format {
+	return findFormat(spotFormats, func(f Format) bool { return strings.EqualFold(f.Name, name) })
+}
+
+// SpotFormatByContentType returns the first spot export format accepted by an Accept header, nil if there is none.
+func SpotFormatByContentType(accept string) *Format {
+	return formatByContentType(spotFormats, accept)
+}
+
+func formatByContentType(formats []Format, accept string) *Format {
+	for _, part := range strings.Split(accept, ",") {
+		mediaType := strings.TrimSpace(strings.Split(part, ";")[0])
+		format := findFormat(formats, func(f Format) bool { return strings.EqualFold(f.ContentType, mediaType) })
+		if format != nil {
+			return format
+		}
+	}
+	return nil
+}
+
+func findFormat(formats []Format, match func(f Format) bool) *Format {
+	for _, format := range formats {
+		if match(format) {
+			f := format
+			return &f
+		}
+	}
+	return nil
+}
+
+// CatchSummary is the total number and maximum size of the catches of one species at a spot.
+type CatchSummary struct {
+	Fish     string  `json:"fish"`
+	Number   int     `json:"number"`
+	MaxSize  float32 `json:"maxSize"`
+	SizeUnit string  `json:"sizeUnit"`
+}
+
+// Summarize sums up the catches of a spot per species, ordered by the number of fish.
+func Summarize(catches []common.Catch) []CatchSummary {
+
+	bySpecies := map[string]*CatchSummary{}
+	var summaries []*CatchSummary
+	for _, catch := range catches {
+		summary, ok := bySpecies[catch.Fish]
+		if !ok {
+			summary = &CatchSummary{Fish: catch.Fish, SizeUnit: catch.SizeUnit}
+			bySpecies[catch.Fish] = summary
+			summaries = append(summaries, summary)
+		}
+		summary.Number += catch.Number
+		if catch.Size > summary.MaxSize {
+			summary.MaxSize = catch.Size
+		}
+	}
+
+	result := make([]CatchSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		result = append(result, *summary)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Number > result[j].Number
+	})
+	return result
+}
+
+// describe returns a human-readable summary of the catches, one species per line.
+func describe(catches []common.Catch) string {
+	var lines []string
+	for _, summary := range Summarize(catches) {
+		lines = append(lines, fmt.Sprintf("%s: %d, max. %g %s", summary.Fish, summary.Number, summary.MaxSize, summary.SizeUnit))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,185 @@
+package export
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fishfishes_backend/common"
+	"fmt"
+	"io"
+)
+
+// WriteSpots writes the spots in the given format. The spots are written one by one, so the output can be
+// streamed to the client.
+func WriteSpots(w io.Writer, format string, spots []common.Fish_spot) error {
+	switch format {
+	case FormatGeoJSON:
+		return WriteGeoJSON(w, spots)
+	case FormatGPX:
+		return WriteGPX(w, spots)
+	case FormatKML:
+		return WriteKML(w, spots)
+	}
+	return fmt.Errorf("unknown format '%s'", format)
+}
+
+type geoJSONFeature struct {
+	Type       string            `json:"type"`
+	Id         string            `json:"id,omitempty"`
+	Geometry   geoJSONPoint      `json:"geometry"`
+	Properties geoJSONProperties `json:"properties"`
+}
+
+type geoJSONPoint struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+type geoJSONProperties struct {
+	Title   string         `json:"title"`
+	Catches []CatchSummary `json:"catches"`
+}
+
+// WriteGeoJSON writes the spots as GeoJSON FeatureCollection of points.
+func WriteGeoJSON(w io.Writer, spots []common.Fish_spot) error {
+
+	_, err := io.WriteString(w, `{"type":"FeatureCollection","features":[`)
+	if err != nil {
+		return err
+	}
+
+	for i, spot := range spots {
+		if i > 0 {
+			if _, err = io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		feature, err := json.Marshal(geoJSONFeature{
+			Type: "Feature",
+			Id:   spot.Id,
+			Geometry: geoJSONPoint{
+				Type:        "Point",
+				Coordinates: [2]float64{spot.Marker.Coordinates.Longitude, spot.Marker.Coordinates.Latitude},
+			},
+			Properties: geoJSONProperties{
+				Title:   spot.Marker.Title,
+				Catches: Summarize(spot.Catches),
+			},
+		})
+		if err != nil {
+			return err
+		}
+		if _, err = w.Write(feature); err != nil {
+			return err
+		}
+	}
+
+	_, err = io.WriteString(w, "]}\n")
+	return err
+}
+
+type gpxWaypoint struct {
+	XMLName     xml.Name `xml:"wpt"`
+	Latitude    float64  `xml:"lat,attr"`
+	Longitude   float64  `xml:"lon,attr"`
+	Name        string   `xml:"name"`
+	Description string   `xml:"desc,omitempty"`
+	Type        string   `xml:"type"`
+}
+
+// WriteGPX writes the spots as GPX 1.1 waypoints.
+func WriteGPX(w io.Writer, spots []common.Fish_spot) error {
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	gpx := xml.StartElement{
+		Name: xml.Name{Local: "gpx"},
+		Attr: []xml.Attr{
+			{Name: xml.Name{Local: "xmlns"}, Value: "http://www.topografix.com/GPX/1/1"},
+			{Name: xml.Name{Local: "version"}, Value: "1.1"},
+			{Name: xml.Name{Local: "creator"}, Value: "fishfishes"},
+		},
+	}
+
+	return writeXML(w, encoder, gpx, func() error {
+		for _, spot := range spots {
+			err := encoder.Encode(gpxWaypoint{
+				Latitude:    spot.Marker.Coordinates.Latitude,
+				Longitude:   spot.Marker.Coordinates.Longitude,
+				Name:        spot.Marker.Title,
+				Description: describe(spot.Catches),
+				Type:        "Fishing Spot",
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+type kmlPlacemark struct {
+	XMLName     xml.Name `xml:"Placemark"`
+	Id          string   `xml:"id,attr,omitempty"`
+	Name        string   `xml:"name"`
+	Description string   `xml:"description,omitempty"`
+	Coordinates string   `xml:"Point>coordinates"`
+}
+
+// WriteKML writes the spots as KML placemarks.
+func WriteKML(w io.Writer, spots []common.Fish_spot) error {
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	kml := xml.StartElement{
+		Name: xml.Name{Local: "kml"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: "http://www.opengis.net/kml/2.2"}},
+	}
+	document := xml.StartElement{Name: xml.Name{Local: "Document"}}
+
+	return writeXML(w, encoder, kml, func() error {
+		err := encoder.EncodeToken(document)
+		if err != nil {
+			return err
+		}
+		err = encoder.EncodeElement("Fishing Spots", xml.StartElement{Name: xml.Name{Local: "name"}})
+		if err != nil {
+			return err
+		}
+		for _, spot := range spots {
+			err := encoder.Encode(kmlPlacemark{
+				Id:          spot.Id,
+				Name:        spot.Marker.Title,
+				Description: describe(spot.Catches),
+				Coordinates: fmt.Sprintf("%g,%g", spot.Marker.Coordinates.Longitude, spot.Marker.Coordinates.Latitude),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return encoder.EncodeToken(document.End())
+	})
+}
+
+// writeXML writes the XML header and the root element around the content written by body.
+func writeXML(w io.Writer, encoder *xml.Encoder, root xml.StartElement, body func() error) error {
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+	err = encoder.EncodeToken(root)
+	if err != nil {
+		return err
+	}
+	err = body()
+	if err != nil {
+		return err
+	}
+	err = encoder.EncodeToken(root.End())
+	if err != nil {
+		return err
+	}
+	return encoder.Flush()
+}
@@ -0,0 +1,93 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fishfishes_backend/common"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var spots = []common.Fish_spot{
+	{
+		Id:     "s1",
+		Marker: common.Marker{Title: "Lake <North>", Coordinates: common.Coordinates{Latitude: 52.5, Longitude: 13.4}},
+		Catches: []common.Catch{
+			{Fish: "Tench", Number: 1, Size: 35, SizeUnit: common.UnitCentimeter},
+			{Fish: "Common Carp", Number: 2, Size: 60, SizeUnit: common.UnitCentimeter},
+			{Fish: "Tench", Number: 2, Size: 41.5, SizeUnit: common.UnitCentimeter},
+		},
+	},
+	{
+		Id:     "s2",
+		Marker: common.Marker{Title: "River", Coordinates: common.Coordinates{Latitude: -33.9, Longitude: 151.2}},
+	},
+}
+
+func TestSummarize(t *testing.T) {
+	summaries := Summarize(spots[0].Catches)
+	assert.Equal(t, []CatchSummary{
+		{Fish: "Tench", Number: 3, MaxSize: 41.5, SizeUnit: common.UnitCentimeter},
+		{Fish: "Common Carp", Number: 2, MaxSize: 60, SizeUnit: common.UnitCentimeter},
+	}, summaries)
+}
+
+func TestWriteGeoJSON(t *testing.T) {
+
+	var buffer bytes.Buffer
+	err := WriteSpots(&buffer, FormatGeoJSON, spots)
+	assert.NoError(t, err)
+
+	var collection struct {
+		Type     string           `json:"type"`
+		Features []geoJSONFeature `json:"features"`
+	}
+	assert.NoError(t, json.Unmarshal(buffer.Bytes(), &collection))
+	assert.Equal(t, "FeatureCollection", collection.Type)
+	if assert.Len(t, collection.Features, 2) {
+		assert.Equal(t, [2]float64{13.4, 52.5}, collection.Features[0].Geometry.Coordinates)
+		assert.Equal(t, "Lake <North>", collection.Features[0].Properties.Title)
+		assert.Len(t, collection.Features[0].Properties.Catches, 2)
+	}
+}
+
+func TestWriteGPX(t *testing.T) {
+
+	var buffer bytes.Buffer
+	err := WriteSpots(&buffer, FormatGPX, spots)
+	assert.NoError(t, err)
+
+	var gpx struct {
+		Version   string        `xml:"version,attr"`
+		Waypoints []gpxWaypoint `xml:"wpt"`
+	}
+	assert.NoError(t, xml.Unmarshal(buffer.Bytes(), &gpx))
+	assert.Equal(t, "1.1", gpx.Version)
+	if assert.Len(t, gpx.Waypoints, 2) {
+		assert.Equal(t, -33.9, gpx.Waypoints[1].Latitude)
+		assert.Equal(t, "Lake <North>", gpx.Waypoints[0].Name)
+		assert.Equal(t, "Tench: 3, max. 41.5 cm\nCommon Carp: 2, max. 60 cm", gpx.Waypoints[0].Description)
+	}
+}
+
+func TestWriteKML(t *testing.T) {
+
+	var buffer bytes.Buffer
+	err := WriteSpots(&buffer, FormatKML, spots)
+	assert.NoError(t, err)
+
+	var kml struct {
+		Placemarks []kmlPlacemark `xml:"Document>Placemark"`
+	}
+	assert.NoError(t, xml.Unmarshal(buffer.Bytes(), &kml))
+	if assert.Len(t, kml.Placemarks, 2) {
+		assert.Equal(t, "13.4,52.5", kml.Placemarks[0].Coordinates)
+		assert.Equal(t, "River", kml.Placemarks[1].Name)
+	}
+}
+
+func TestSpotFormatByContentType(t *testing.T) {
+	assert.Equal(t, FormatKML, SpotFormatByContentType("text/html, application/vnd.google-earth.kml+xml;q=0.9").Name)
+	assert.Nil(t, SpotFormatByContentType("application/json"))
+}
@@ -0,0 +1,94 @@
+// Package feed turns the activities of users into the items of the feeds of their friends.
+package feed
+
+import (
+	"fishfishes_backend/common"
+	"fishfishes_backend/common/geo"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ObfuscationDegrees is the edge length of the area a hidden spot location is shown as, about 2 km.
+const ObfuscationDegrees float64 = 0.02
+
+// Activities returns the activities of saving the spot: one for the spot, if it is new, and one for each of
+// the given catches.
+func Activities(userId string, spot common.Fish_spot, newSpot bool, catches []common.Catch, at time.Time, newId func() string) []common.Activity {
+
+	var result []common.Activity
+	if newSpot {
+		result = append(result, common.Activity{Id: newId(), UserId: userId, Type: common.ActivitySpot, SpotId: spot.Id, CreatedAt: at})
+	}
+	for _, catch := range catches {
+		result = append(result, common.Activity{Id: newId(), UserId: userId, Type: common.ActivityCatch, SpotId: spot.Id, CatchId: catch.Id, CreatedAt: at})
+	}
+	return result
+}
+
+// Item returns the feed item of the entry as shown to other users: with the coordinates obfuscated, if the
+// owner hides the location of the spot, and without the references to the tackle box of the owner.
+func Item(entry common.FeedEntry, userName string) common.FeedItem {
+
+	spot := common.FeedSpot{Id: entry.Spot.Id, Title: entry.Spot.Marker.Title, Coordinates: entry.Spot.Marker.Coordinates}
+	if entry.Spot.HideLocation {
+		spot.Coordinates = geo.Obfuscate(spot.Coordinates, ObfuscationDegrees)
+		spot.LocationHidden = true
+	}
+
+	item := common.FeedItem{
+		Id:        entry.Id,
+		UserId:    entry.UserId,
+		UserName:  userName,
+		Type:      entry.Type,
+		CreatedAt: entry.CreatedAt,
+		Spot:      spot,
+	}
+	if entry.Catch != nil {
+		catch := *entry.Catch
+		catch.Equipment.ItemIds = nil
+		item.Catch = &catch
+	}
+	item.Text = describe(item)
+	return item
+}
+
+// describe returns a sentence about the item like "Anna caught a 72 cm Pike at Lake X".
+func describe(item common.FeedItem) string {
+
+	if item.Catch == nil {
+		return fmt.Sprintf("%s shared the spot %s", item.UserName, item.Spot.Title)
+	}
+
+	catch := *item.Catch
+	if catch.Number > 1 {
+		return fmt.Sprintf("%s caught %d %s at %s", item.UserName, catch.Number, catch.Fish, item.Spot.Title)
+	}
+
+	size := strconv.FormatFloat(float64(catch.Size), 'f', -1, 32)
+	unit := catch.SizeUnit
+	if len(unit) == 0 {
+		unit = common.UnitCentimeter
+	}
+	return fmt.Sprintf("%s caught a %s %s %s at %s", item.UserName, size, unit, catch.Fish, item.Spot.Title)
+}
+
+// Page returns the entries of a page of at most limit entries and the cursor of the next page, which is
+// empty if there are no more entries. entries may contain one entry more than the page to tell.
+func Page(entries []common.FeedEntry, limit int) ([]common.FeedEntry, string) {
+	if len(entries) <= limit {
+		return entries, ""
+	}
+	entries = entries[:limit]
+	last := entries[len(entries)-1]
+	return entries, EncodeCursor(common.FeedCursor{CreatedAt: last.CreatedAt, Id: last.Id})
+}
+
+func EncodeCursor(cursor common.FeedCursor) string {
+	return cursor.Encode()
+}
+
+// DecodeCursor reads a cursor of EncodeCursor. The empty cursor is the start of the feed and returns nil.
+func DecodeCursor(value string) (*common.FeedCursor, error) {
+	return common.DecodeCursor(value)
+}
@@ -0,0 +1,94 @@
+package feed
+
+import (
+	"fishfishes_backend/common"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+var at = time.Date(2023, 6, 3, 5, 0, 0, 0, time.UTC)
+
+func TestActivities(t *testing.T) {
+
+	t.Parallel()
+
+	next := 0
+	newId := func() string {
+		next++
+		return fmt.Sprintf("a%d", next)
+	}
+
+	spot := common.Fish_spot{Id: "lake"}
+	catches := []common.Catch{{Id: "c1"}, {Id: "c2"}}
+
+	assert.Equal(t, []common.Activity{
+		{Id: "a1", UserId: "anna", Type: common.ActivitySpot, SpotId: "lake", CreatedAt: at},
+		{Id: "a2", UserId: "anna", Type: common.ActivityCatch, SpotId: "lake", CatchId: "c1", CreatedAt: at},
+		{Id: "a3", UserId: "anna", Type: common.ActivityCatch, SpotId: "lake", CatchId: "c2", CreatedAt: at},
+	}, Activities("anna", spot, true, catches, at, newId))
+
+	assert.Len(t, Activities("anna", spot, false, catches[:1], at, newId), 1)
+}
+
+func TestItem(t *testing.T) {
+
+	t.Parallel()
+
+	spot := common.Fish_spot{Id: "lake", Marker: common.Marker{Title: "Lake X", Coordinates: common.Coordinates{Latitude: 52.5163, Longitude: 13.3777}}}
+	catch := common.Catch{Id: "c1", Fish: "Pike", Number: 1, Size: 72, SizeUnit: common.UnitCentimeter, Equipment: common.Equipment{Bait: "Spinner", ItemIds: []string{"lure"}}}
+
+	item := Item(common.FeedEntry{Activity: common.Activity{Id: "a1", UserId: "anna", Type: common.ActivityCatch, CreatedAt: at}, Spot: spot, Catch: &catch}, "Anna")
+	assert.Equal(t, "Anna caught a 72 cm Pike at Lake X", item.Text)
+	assert.Equal(t, spot.Marker.Coordinates, item.Spot.Coordinates)
+	assert.False(t, item.Spot.LocationHidden)
+	assert.Nil(t, item.Catch.Equipment.ItemIds)
+	assert.Equal(t, []string{"lure"}, catch.Equipment.ItemIds)
+
+	spot.HideLocation = true
+	catch.Number = 3
+	item = Item(common.FeedEntry{Activity: common.Activity{Type: common.ActivityCatch}, Spot: spot, Catch: &catch}, "Anna")
+	assert.Equal(t, "Anna caught 3 Pike at Lake X", item.Text)
+	assert.Equal(t, common.Coordinates{Latitude: 52.51, Longitude: 13.37}, item.Spot.Coordinates)
+	assert.True(t, item.Spot.LocationHidden)
+
+	item = Item(common.FeedEntry{Activity: common.Activity{Type: common.ActivitySpot}, Spot: spot}, "Anna")
+	assert.Equal(t, "Anna shared the spot Lake X", item.Text)
+	assert.Nil(t, item.Catch)
+}
+
+func TestPage(t *testing.T) {
+
+	t.Parallel()
+
+	entries := []common.FeedEntry{
+		{Activity: common.Activity{Id: "a3", CreatedAt: at.Add(2 * time.Minute)}},
+		{Activity: common.Activity{Id: "a2", CreatedAt: at.Add(time.Minute)}},
+		{Activity: common.Activity{Id: "a1", CreatedAt: at}},
+	}
+
+	page, next := Page(entries, 3)
+	assert.Len(t, page, 3)
+	assert.Empty(t, next)
+
+	page, next = Page(entries, 2)
+	assert.Len(t, page, 2)
+	cursor, err := DecodeCursor(next)
+	assert.NoError(t, err)
+	assert.Equal(t, &common.FeedCursor{CreatedAt: at.Add(time.Minute), Id: "a2"}, cursor)
+}
+
+func TestDecodeCursor(t *testing.T) {
+
+	t.Parallel()
+
+	cursor, err := DecodeCursor("")
+	assert.NoError(t, err)
+	assert.Nil(t, cursor)
+
+	for _, invalid := range []string{"%%%", EncodeCursor(common.FeedCursor{Id: "a"})[:8], "bm8tc2VwYXJhdG9y"} {
+		_, err = DecodeCursor(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,121 @@
+package importer
+
+import (
+	"encoding/csv"
+	"fishfishes_backend/common"
+	"fmt"
+	"github.com/pkg/errors"
+	"io"
+	"strings"
+)
+
+// CSVMapping names the columns of a CSV file holding the spot fields. Empty names are looked up by their
+// usual header names, for example lat or lng.
+type CSVMapping struct {
+	Latitude  string
+	Longitude string
+	Title     string
+	Delimiter rune
+}
+
+var columnAliases = map[string][]string{
+	"latitude":  {"latitude", "lat", "breite", "y"},
+	"longitude": {"longitude", "lon", "lng", "long", "länge", "x"},
+	"title":     {"title", "name", "titel", "label"},
+}
+
+// ReadCSV reads spots from a CSV file with a header line.
+func ReadCSV(r io.Reader, mapping CSVMapping) ([]Row, error) {
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if mapping.Delimiter != 0 {
+		reader.Comma = mapping.Delimiter
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read the CSV header")
+	}
+
+	latitude, err := column(header, mapping.Latitude, "latitude")
+	if err != nil {
+		return nil, err
+	}
+	longitude, err := column(header, mapping.Longitude, "longitude")
+	if err != nil {
+		return nil, err
+	}
+	title, err := column(header, mapping.Title, "title")
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []Row
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		line, _ := reader.FieldPos(0)
+		row := Row{Number: line}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, err
+			}
+			row.Number = parseErr.Line
+			row.Error = parseErr.Err.Error()
+			rows = append(rows, row)
+			continue
+		}
+
+		lat, latErr := parseCoordinate(field(record, latitude))
+		lon, lonErr := parseCoordinate(field(record, longitude))
+		switch {
+		case latErr != nil:
+			row.Error = fmt.Sprintf("invalid latitude '%s'", field(record, latitude))
+		case lonErr != nil:
+			row.Error = fmt.Sprintf("invalid longitude '%s'", field(record, longitude))
+		default:
+			row.Marker = common.Marker{
+				Title:       strings.TrimSpace(field(record, title)),
+				Coordinates: common.Coordinates{Latitude: lat, Longitude: lon},
+			}
+		}
+		rows = append(rows, row)
+	}
+}
+
+// column returns the index of the mapped column or, without a mapping, of the first column named like one of
+// the aliases of the field.
+func column(header []string, mapped string, field string) (int, error) {
+
+	names := columnAliases[field]
+	if len(mapped) > 0 {
+		names = []string{mapped}
+	}
+
+	for _, name := range names {
+		for i, h := range header {
+			if strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")), name) {
+				return i, nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("found no %s column, expected one of %s", field, strings.Join(names, ", "))
+}
+
+func field(record []string, index int) string {
+	if index >= len(record) {
+		return ""
+	}
+	return record[index]
+}
+
+// parseCoordinate parses a decimal degree with a decimal point or comma.
+func parseCoordinate(value string) (float64, error) {
+	return parseDegree(strings.Replace(value, ",", ".", 1))
+}
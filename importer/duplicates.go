@@ -0,0 +1,31 @@
+package importer
+
+import (
+	"fishfishes_backend/common"
+	"fishfishes_backend/common/geo"
+	"strings"
+)
+
+const (
+	SameSpotDistance  float64 = 25  // meters within which two spots are the same, whatever their titles
+	SameTitleDistance float64 = 500 // meters within which two spots with the same title are the same
+)
+
+// FindDuplicate returns the index of the first spot the marker duplicates or -1, if there is none.
+func FindDuplicate(marker common.Marker, spots []common.Fish_spot) int {
+	for i, spot := range spots {
+		if IsDuplicate(marker, spot.Marker) {
+			return i
+		}
+	}
+	return -1
+}
+
+// IsDuplicate returns true if both markers are at nearly the same place, or near each other with the same title.
+func IsDuplicate(a, b common.Marker) bool {
+	distance := geo.Distance(a.Coordinates, b.Coordinates)
+	if distance <= SameSpotDistance {
+		return true
+	}
+	return distance <= SameTitleDistance && strings.EqualFold(strings.TrimSpace(a.Title), strings.TrimSpace(b.Title))
+}
@@ -0,0 +1,64 @@
+package importer
+
+import (
+	"encoding/json"
+	"fishfishes_backend/common"
+	"fmt"
+	"github.com/pkg/errors"
+	"io"
+)
+
+type geoJSONFeature struct {
+	Geometry *struct {
+		Type        string          `json:"type"`
+		Coordinates json.RawMessage `json:"coordinates"`
+	} `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// ReadGeoJSON reads the Point features of a GeoJSON FeatureCollection. The title is taken from the title or
+// name property.
+func ReadGeoJSON(r io.Reader) ([]Row, error) {
+
+	var collection struct {
+		Type     string           `json:"type"`
+		Features []geoJSONFeature `json:"features"`
+	}
+	err := json.NewDecoder(r).Decode(&collection)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse GeoJSON")
+	}
+	if collection.Type != "FeatureCollection" {
+		return nil, fmt.Errorf("expected a GeoJSON FeatureCollection, got '%s'", collection.Type)
+	}
+
+	rows := make([]Row, 0, len(collection.Features))
+	for i, feature := range collection.Features {
+		row := Row{Number: i + 1}
+
+		var position []float64
+		if feature.Geometry == nil || feature.Geometry.Type != "Point" {
+			row.Error = "geometry must be a Point"
+		} else if err := json.Unmarshal(feature.Geometry.Coordinates, &position); err != nil || len(position) < 2 {
+			row.Error = "invalid Point coordinates"
+		} else {
+			row.Marker = common.Marker{
+				Title:       firstString(feature.Properties, "title", "name", "Name"),
+				Coordinates: common.Coordinates{Latitude: position[1], Longitude: position[0]},
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func firstString(properties map[string]interface{}, names ...string) string {
+	for _, name := range names {
+		if value, ok := properties[name].(string); ok && len(value) > 0 {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,53 @@
+// Package importer reads spots from the file formats of other applications.
+package importer
+
+import (
+	"fishfishes_backend/common"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	FormatGeoJSON string = "geojson"
+	FormatGPX     string = "gpx"
+	FormatKML     string = "kml"
+	FormatCSV     string = "csv"
+)
+
+// Row is a spot read from a file. Number is the position of the spot in the file, for CSV the line number.
+// If the spot could not be read, Marker is empty and Error tells why.
+type Row struct {
+	Number int
+	Marker common.Marker
+	Error  string
+}
+
+// Read reads the spots of a file in the given format. The mapping is only used for CSV.
+func Read(r io.Reader, format string, mapping CSVMapping) ([]Row, error) {
+	switch strings.ToLower(format) {
+	case FormatGeoJSON:
+		return ReadGeoJSON(r)
+	case FormatGPX:
+		return ReadGPX(r)
+	case FormatKML:
+		return ReadKML(r)
+	case FormatCSV:
+		return ReadCSV(r, mapping)
+	}
+	return nil, fmt.Errorf("unknown format '%s'", format)
+}
+
+// parseDegree parses a decimal degree. strconv accepts NaN and Inf, which are no coordinates.
+func parseDegree(value string) (float64, error) {
+	degree, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(degree) || math.IsInf(degree, 0) {
+		return 0, fmt.Errorf("invalid degree '%s'", value)
+	}
+	return degree, nil
+}
@@ -0,0 +1,153 @@
+package importer
+
+import (
+	"fishfishes_backend/common"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestRead(t *testing.T) {
+
+	t.Parallel()
+
+	type test struct {
+		format   string
+		mapping  CSVMapping
+		input    string
+		expected []Row
+		hasError bool
+	}
+
+	lake := common.Marker{Title: "Lake", Coordinates: common.Coordinates{Latitude: 52.5, Longitude: 13.4}}
+
+	cases := map[string]test{
+		"geojson": {
+			format: FormatGeoJSON,
+			input: `{"type":"FeatureCollection","features":[
+				{"type":"Feature","geometry":{"type":"Point","coordinates":[13.4,52.5]},"properties":{"name":"Lake"}},
+				{"type":"Feature","geometry":{"type":"LineString","coordinates":[[13.4,52.5],[13.5,52.6]]},"properties":{}}
+			]}`,
+			expected: []Row{
+				{Number: 1, Marker: lake},
+				{Number: 2, Error: "geometry must be a Point"},
+			},
+		},
+		"geojson without collection": {
+			format:   FormatGeoJSON,
+			input:    `{"type":"Feature"}`,
+			hasError: true,
+		},
+		"gpx": {
+			format: FormatGPX,
+			input: `<?xml version="1.0"?>
+				<gpx version="1.1" xmlns="http://www.topografix.com/GPX/1/1">
+					<wpt lat="52.5" lon="13.4"><name>Lake</name></wpt>
+					<wpt lat="north" lon="13.4"><name>Broken</name></wpt>
+					<wpt lat="NaN" lon="nan"><name>Nowhere</name></wpt>
+					<trk><name>Drive</name></trk>
+				</gpx>`,
+			expected: []Row{
+				{Number: 1, Marker: lake},
+				{Number: 2, Error: "invalid lat or lon attribute"},
+				{Number: 3, Error: "invalid lat or lon attribute"},
+			},
+		},
+		"kml with folders": {
+			format: FormatKML,
+			input: `<kml xmlns="http://www.opengis.net/kml/2.2"><Document><Folder>
+					<Placemark><name>Lake</name><Point><coordinates> 13.4,52.5,0 </coordinates></Point></Placemark>
+					<Placemark><name>Route</name><LineString><coordinates>13.4,52.5 13.5,52.6</coordinates></LineString></Placemark>
+				</Folder></Document></kml>`,
+			expected: []Row{
+				{Number: 1, Marker: lake},
+				{Number: 2, Error: "placemark has no Point coordinates"},
+			},
+		},
+		"csv with aliases": {
+			format: FormatCSV,
+			input:  "Name,Lat,Lng\nLake,52.5,13.4\nBroken,x,13.4\nNowhere,NaN,NaN\nFar,52.5,+Inf\n",
+			expected: []Row{
+				{Number: 2, Marker: lake},
+				{Number: 3, Error: "invalid latitude 'x'"},
+				{Number: 4, Error: "invalid latitude 'NaN'"},
+				{Number: 5, Error: "invalid longitude '+Inf'"},
+			},
+		},
+		"csv with mapping and decimal commas": {
+			format:  FormatCSV,
+			mapping: CSVMapping{Latitude: "N", Longitude: "E", Title: "Spot", Delimiter: ';'},
+			input:   "Spot;N;E\nLake;52,5;13,4\n",
+			expected: []Row{
+				{Number: 2, Marker: lake},
+			},
+		},
+		"csv without coordinates": {
+			format:   FormatCSV,
+			input:    "Name,Depth\nLake,3\n",
+			hasError: true,
+		},
+	}
+
+	for name, tc := range cases {
+
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			rows, err := Read(strings.NewReader(tc.input), tc.format, tc.mapping)
+			if tc.hasError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, rows)
+		})
+	}
+}
+
+func TestFindDuplicate(t *testing.T) {
+
+	t.Parallel()
+
+	spots := []common.Fish_spot{
+		{Marker: common.Marker{Title: "Harbour", Coordinates: common.Coordinates{Latitude: 54.0, Longitude: 10.0}}},
+		{Marker: common.Marker{Title: "Old Bridge", Coordinates: common.Coordinates{Latitude: 52.0, Longitude: 13.0}}},
+	}
+
+	type test struct {
+		marker   common.Marker
+		expected int
+	}
+
+	cases := map[string]test{
+		"same place, other title": {
+			marker:   common.Marker{Title: "Pier", Coordinates: common.Coordinates{Latitude: 54.0001, Longitude: 10.0}},
+			expected: 0,
+		},
+		"same title nearby": {
+			marker:   common.Marker{Title: " old bridge", Coordinates: common.Coordinates{Latitude: 52.003, Longitude: 13.0}},
+			expected: 1,
+		},
+		"same title far away": {
+			marker:   common.Marker{Title: "Old Bridge", Coordinates: common.Coordinates{Latitude: 52.1, Longitude: 13.0}},
+			expected: -1,
+		},
+		"other title nearby": {
+			marker:   common.Marker{Title: "New Bridge", Coordinates: common.Coordinates{Latitude: 52.003, Longitude: 13.0}},
+			expected: -1,
+		},
+	}
+
+	for name, tc := range cases {
+
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, FindDuplicate(tc.marker, spots))
+		})
+	}
+}
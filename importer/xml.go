@@ -0,0 +1,103 @@
+package importer
+
+import (
+	"encoding/xml"
+	"fishfishes_backend/common"
+	"github.com/pkg/errors"
+	"io"
+	"strings"
+)
+
+type gpxWaypoint struct {
+	Latitude  string `xml:"lat,attr"`
+	Longitude string `xml:"lon,attr"`
+	Name      string `xml:"name"`
+}
+
+type kmlPlacemark struct {
+	Name        string `xml:"name"`
+	Coordinates string `xml:"Point>coordinates"`
+}
+
+// ReadGPX reads the waypoints of a GPX file. Tracks and routes are ignored.
+func ReadGPX(r io.Reader) ([]Row, error) {
+	return readElements(r, "wpt", func(d *xml.Decoder, start xml.StartElement, row *Row) error {
+		var waypoint gpxWaypoint
+		err := d.DecodeElement(&waypoint, &start)
+		if err != nil {
+			return err
+		}
+
+		latitude, latErr := parseDegree(waypoint.Latitude)
+		longitude, lonErr := parseDegree(waypoint.Longitude)
+		if latErr != nil || lonErr != nil {
+			row.Error = "invalid lat or lon attribute"
+			return nil
+		}
+
+		row.Marker = common.Marker{
+			Title:       strings.TrimSpace(waypoint.Name),
+			Coordinates: common.Coordinates{Latitude: latitude, Longitude: longitude},
+		}
+		return nil
+	})
+}
+
+// ReadKML reads the point placemarks of a KML file, also from nested folders.
+func ReadKML(r io.Reader) ([]Row, error) {
+	return readElements(r, "Placemark", func(d *xml.Decoder, start xml.StartElement, row *Row) error {
+		var placemark kmlPlacemark
+		err := d.DecodeElement(&placemark, &start)
+		if err != nil {
+			return err
+		}
+
+		// coordinates are longitude,latitude[,altitude]
+		parts := strings.Split(strings.TrimSpace(placemark.Coordinates), ",")
+		if len(parts) < 2 {
+			row.Error = "placemark has no Point coordinates"
+			return nil
+		}
+		longitude, lonErr := parseDegree(parts[0])
+		latitude, latErr := parseDegree(parts[1])
+		if latErr != nil || lonErr != nil {
+			row.Error = "invalid Point coordinates"
+			return nil
+		}
+
+		row.Marker = common.Marker{
+			Title:       strings.TrimSpace(placemark.Name),
+			Coordinates: common.Coordinates{Latitude: latitude, Longitude: longitude},
+		}
+		return nil
+	})
+}
+
+// readElements streams through an XML document and calls read for every element with the given local name.
+func readElements(r io.Reader, name string, read func(d *xml.Decoder, start xml.StartElement, row *Row) error) ([]Row, error) {
+
+	decoder := xml.NewDecoder(r)
+	var rows []Row
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "could not parse XML")
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != name {
+			continue
+		}
+
+		row := Row{Number: len(rows) + 1}
+		err = read(decoder, start, &row)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not parse %s %d", name, row.Number)
+		}
+		rows = append(rows, row)
+	}
+}
@@ -4,9 +4,13 @@ import (
 	"context"
 	"fishfishes_backend/common/mongo"
 	"fishfishes_backend/configuration"
+	"fishfishes_backend/moderation"
+	"fishfishes_backend/regulations"
 	repo "fishfishes_backend/repository"
 	"fishfishes_backend/security"
 	"fishfishes_backend/service"
+	"fishfishes_backend/timezone"
+	"fishfishes_backend/weather"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -38,7 +42,7 @@ func main() {
 	defer logger.Sync()
 	sugar := logger.Sugar()
 
-	config := configuration.NewServiceConfiguration(os.Getenv("MONGOURI"), os.Getenv("MONGODATABASE"), os.Getenv("BACKENDAPIKEY"))
+	config := configuration.NewServiceConfiguration(os.Getenv("MONGOURI"), os.Getenv("MONGODATABASE"), os.Getenv("BACKENDAPIKEY"), os.Getenv("ADMINAPIKEY"), os.Getenv("WEATHERFILE"), os.Getenv("REGULATIONSDIR"), os.Getenv("TIMEZONEFILE"), os.Getenv("REJECTEDWORDS"), os.Getenv("FLAGGEDWORDS"))
 
 	//Create MongoDB Client
 	dbClient, err := mongo.NewMongoDatabase(&config.DB, sugar)
@@ -61,8 +65,117 @@ func main() {
 		return
 	}
 
-	service := service.NewService(repository)
-	sec := security.NewSecurity(config.BackendAPIKey) // Add e.g. MongoDB client
+	seeded, err := repository.SeedSpecies(ctx)
+	if err != nil {
+		logger.Error(fmt.Sprintf("error seeding species error:%s", err.Error()))
+		os.Exit(1)
+		return
+	}
+	sugar.Infof("Seeded %d species", seeded)
+
+	var weatherProvider weather.Provider
+	if len(config.WeatherFile) > 0 {
+		fileProvider, err := weather.NewFileProvider(config.WeatherFile)
+		if err != nil {
+			logger.Error(fmt.Sprintf("error loading weather file error:%s", err.Error()))
+			os.Exit(1)
+			return
+		}
+		weatherProvider = fileProvider
+	}
+
+	var zones *timezone.Resolver
+	if len(config.TimeZoneFile) > 0 {
+		zones, err = timezone.LoadFile(config.TimeZoneFile)
+	} else {
+		zones, err = timezone.NewResolver()
+	}
+	if err != nil {
+		logger.Error(fmt.Sprintf("error loading time zones error:%s", err.Error()))
+		os.Exit(1)
+		return
+	}
+
+	var book *regulations.Book
+	if len(config.RegulationsDir) > 0 {
+		book, err = regulations.LoadDir(config.RegulationsDir)
+		if err != nil {
+			logger.Error(fmt.Sprintf("error loading regulations error:%s", err.Error()))
+			os.Exit(1)
+			return
+		}
+		sugar.Infof("Loaded the regulations of %d regions", len(book.Regions))
+	}
+
+	service := service.NewService(repository, weatherProvider, zones, book)
+	if len(config.RejectedWords) > 0 || len(config.FlaggedWords) > 0 {
+		service.Moderator = moderation.NewWordList(config.RejectedWords, config.FlaggedWords)
+	}
+
+	_, err = service.Migrate(ctx, "catchTimes", func(ctx context.Context) error {
+		migrated, unparsed, err := service.MigrateCatchTimes(ctx)
+		if err == nil {
+			sugar.Infof("Migrated %d catch times, %d could not be parsed", migrated, unparsed)
+		}
+		return err
+	})
+	if err != nil {
+		logger.Error(fmt.Sprintf("error migrating catch times error:%s", err.Error()))
+		os.Exit(1)
+		return
+	}
+
+	_, err = service.Migrate(ctx, "catchIds", func(ctx context.Context) error {
+		catchIds, err := service.MigrateCatchIds(ctx)
+		if err == nil {
+			sugar.Infof("Assigned ids to %d catches", catchIds)
+		}
+		return err
+	})
+	if err != nil {
+		logger.Error(fmt.Sprintf("error migrating catch ids error:%s", err.Error()))
+		os.Exit(1)
+		return
+	}
+
+	// later runs, e.g. after aliases for unmatched names were added, are started with /admin/migrateSpecies
+	_, err = service.Migrate(ctx, "species", func(ctx context.Context) error {
+		speciesMigration, err := service.MigrateSpecies(ctx)
+		if err != nil {
+			return err
+		}
+		sugar.Infof("Linked %d catches to the species catalog, %d species names did not match", speciesMigration.Linked, len(speciesMigration.Unmatched))
+		for _, unmatched := range speciesMigration.Unmatched {
+			sugar.Infof("Unmatched species name '%s' in %d catches", unmatched.Fish, unmatched.Catches)
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Error(fmt.Sprintf("error migrating catch species error:%s", err.Error()))
+		os.Exit(1)
+		return
+	}
+
+	// the records are computed from the catches as the migrations before left them
+	_, err = service.Migrate(ctx, "records", func(ctx context.Context) error {
+		users, err := service.MigrateRecords(ctx)
+		if err == nil {
+			sugar.Infof("Computed the personal records of %d users", users)
+		}
+		return err
+	})
+	if err != nil {
+		logger.Error(fmt.Sprintf("error migrating personal records error:%s", err.Error()))
+		os.Exit(1)
+		return
+	}
+
+	// the backfill starts after the migrations, which rewrite the catches it adds the weather to
+	if weatherProvider != nil {
+		go weather.NewBackfillWorker(repository, weatherProvider, sugar).Run(ctx)
+	}
+
+	sec := security.NewSecurity(config.BackendAPIKey, config.AdminAPIKey) // Add e.g. MongoDB client
 
 	router := gin.Default()
 	router.UseH2C = true
@@ -77,7 +190,81 @@ func main() {
 	router.GET("/getMarkers", sec.ValidateAPIKey(), service.GetAllSpotCoordinates)
 	router.GET("/getFishlistSalt", sec.ValidateAPIKey(), service.GetFishListSalt)
 	router.GET("/getFishlistFresh", sec.ValidateAPIKey(), service.GetFishListFresh)
+	router.GET("/getSpecies", sec.ValidateAPIKey(), service.GetSpecies)
+	router.GET("/getSpeciesByID", sec.ValidateAPIKey(), service.GetSpeciesByID)
+	router.GET("/autocompleteSpecies", sec.ValidateAPIKey(), service.AutocompleteSpecies)
+	router.GET("/getStatistics", sec.ValidateAPIKey(), service.GetCatchStatistics)
+	router.GET("/getRecommendations", sec.ValidateAPIKey(), service.GetRecommendations)
+	router.GET("/getForecast", sec.ValidateAPIKey(), service.GetForecast)
+	router.GET("/exportSpots", sec.ValidateAPIKey(), service.ExportSpots)
+	router.GET("/exportCatches", sec.ValidateAPIKey(), service.ExportCatches)
+	router.GET("/searchSpots", sec.ValidateAPIKey(), service.SearchSpots)
+	router.POST("/importSpots", sec.ValidateAPIKey(), service.ImportSpots)
 	router.PUT("/saveSpot", sec.ValidateAPIKey(), service.SaveSpot)
+	router.POST("/addCatch", sec.ValidateAPIKey(), service.AddCatch)
+	router.PUT("/updateCatch", sec.ValidateAPIKey(), service.UpdateCatch)
+	router.DELETE("/deleteCatch", sec.ValidateAPIKey(), service.DeleteCatch)
+	router.GET("/getRecords", sec.ValidateAPIKey(), service.GetRecords)
+	router.GET("/getTackle", sec.ValidateAPIKey(), service.GetTackle)
+	router.POST("/createTackle", sec.ValidateAPIKey(), service.CreateTackle)
+	router.PUT("/updateTackle", sec.ValidateAPIKey(), service.UpdateTackle)
+	router.DELETE("/deleteTackle", sec.ValidateAPIKey(), service.DeleteTackle)
+	router.GET("/getTrips", sec.ValidateAPIKey(), service.GetTrips)
+	router.GET("/getTripByID", sec.ValidateAPIKey(), service.GetTripByID)
+	router.GET("/getTripSummary", sec.ValidateAPIKey(), service.GetTripSummary)
+	router.POST("/createTrip", sec.ValidateAPIKey(), service.CreateTrip)
+	router.PUT("/updateTrip", sec.ValidateAPIKey(), service.UpdateTrip)
+	router.DELETE("/deleteTrip", sec.ValidateAPIKey(), service.DeleteTrip)
+	router.GET("/getCPUE", sec.ValidateAPIKey(), service.GetCPUE)
+	router.GET("/getFriends", sec.ValidateAPIKey(), service.GetFriends)
+	router.GET("/getFriendRequests", sec.ValidateAPIKey(), service.GetFriendRequests)
+	router.GET("/getBlockedUsers", sec.ValidateAPIKey(), service.GetBlockedUsers)
+	router.POST("/sendFriendRequest", sec.ValidateAPIKey(), service.SendFriendRequest)
+	router.POST("/acceptFriendRequest", sec.ValidateAPIKey(), service.AcceptFriendRequest)
+	router.POST("/declineFriendRequest", sec.ValidateAPIKey(), service.DeclineFriendRequest)
+	router.POST("/cancelFriendRequest", sec.ValidateAPIKey(), service.CancelFriendRequest)
+	router.POST("/unfollow", sec.ValidateAPIKey(), service.Unfollow)
+	router.POST("/blockUser", sec.ValidateAPIKey(), service.BlockUser)
+	router.POST("/unblockUser", sec.ValidateAPIKey(), service.UnblockUser)
+	router.GET("/getFeed", sec.ValidateAPIKey(), service.GetFeed)
+	router.PUT("/setSpotVisibility", sec.ValidateAPIKey(), service.SetSpotVisibility)
+	router.GET("/getComments", sec.ValidateAPIKey(), service.GetComments)
+	router.POST("/addComment", sec.ValidateAPIKey(), service.AddComment)
+	router.PUT("/editComment", sec.ValidateAPIKey(), service.EditComment)
+	router.DELETE("/deleteComment", sec.ValidateAPIKey(), service.DeleteComment)
+	router.PUT("/react", sec.ValidateAPIKey(), service.React)
+	router.DELETE("/unreact", sec.ValidateAPIKey(), service.Unreact)
+	router.GET("/getGroups", sec.ValidateAPIKey(), service.GetGroups)
+	router.POST("/createGroup", sec.ValidateAPIKey(), service.CreateGroup)
+	router.PUT("/updateGroup", sec.ValidateAPIKey(), service.UpdateGroup)
+	router.DELETE("/deleteGroup", sec.ValidateAPIKey(), service.DeleteGroup)
+	router.GET("/getGroupMembers", sec.ValidateAPIKey(), service.GetGroupMembers)
+	router.POST("/inviteToGroup", sec.ValidateAPIKey(), service.InviteToGroup)
+	router.POST("/acceptGroupInvitation", sec.ValidateAPIKey(), service.AcceptGroupInvitation)
+	router.POST("/declineGroupInvitation", sec.ValidateAPIKey(), service.DeclineGroupInvitation)
+	router.POST("/leaveGroup", sec.ValidateAPIKey(), service.LeaveGroup)
+	router.DELETE("/removeGroupMember", sec.ValidateAPIKey(), service.RemoveGroupMember)
+	router.PUT("/setGroupRole", sec.ValidateAPIKey(), service.SetGroupRole)
+	router.GET("/getGroupSpots", sec.ValidateAPIKey(), service.GetGroupSpots)
+	router.PUT("/saveGroupSpot", sec.ValidateAPIKey(), service.SaveGroupSpot)
+	router.DELETE("/deleteGroupSpot", sec.ValidateAPIKey(), service.DeleteGroupSpot)
+	router.GET("/getCompetitions", sec.ValidateAPIKey(), service.GetCompetitions)
+	router.GET("/getCompetitionByID", sec.ValidateAPIKey(), service.GetCompetitionByID)
+	router.POST("/createCompetition", sec.ValidateAPIKey(), service.CreateCompetition)
+	router.PUT("/updateCompetition", sec.ValidateAPIKey(), service.UpdateCompetition)
+	router.DELETE("/deleteCompetition", sec.ValidateAPIKey(), service.DeleteCompetition)
+	router.POST("/submitCatch", sec.ValidateAPIKey(), service.SubmitCatch)
+	router.DELETE("/withdrawSubmission", sec.ValidateAPIKey(), service.WithdrawSubmission)
+	router.GET("/getLeaderboard", sec.ValidateAPIKey(), service.GetLeaderboard)
+	router.GET("/getRules", sec.ValidateAPIKey(), service.GetRules)
+	router.GET("/getPreferences", sec.ValidateAPIKey(), service.GetPreferences)
+	router.PUT("/savePreferences", sec.ValidateAPIKey(), service.SavePreferences)
+
+	admin := router.Group("/admin", sec.ValidateAPIKey(), sec.ValidateAdminKey())
+	admin.POST("/createSpecies", service.CreateSpecies)
+	admin.PUT("/updateSpecies", service.UpdateSpecies)
+	admin.DELETE("/deleteSpecies", service.DeleteSpecies)
+	admin.POST("/migrateSpecies", service.RunSpeciesMigration)
 
 	//Example POST
 	router.POST("/login", sec.ValidateAPIKey(), service.CheckLogin)
@@ -0,0 +1,75 @@
+// Package moderation decides whether comments of users are published, held back for review or rejected.
+package moderation
+
+import (
+	"context"
+	"fishfishes_backend/common"
+	"strings"
+)
+
+// The verdicts on a comment.
+const (
+	Allow  string = "allow"  // the comment is published
+	Flag   string = "flag"   // the comment is stored, but only shown to its author and the owner of the target
+	Reject string = "reject" // the comment is not stored
+)
+
+// Decision is the verdict of a moderator on a comment with the reason for it.
+type Decision struct {
+	Verdict string `json:"verdict"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// Moderator is called with every comment before it is created or edited.
+type Moderator interface {
+	Moderate(ctx context.Context, comment common.Comment) (Decision, error)
+}
+
+// AllowAll publishes every comment.
+type AllowAll struct{}
+
+func (AllowAll) Moderate(context.Context, common.Comment) (Decision, error) {
+	return Decision{Verdict: Allow}, nil
+}
+
+// WordList rejects comments containing one of the rejected words and flags those containing one of the
+// flagged words. Words are matched whole, regardless of case and diacritics; entries of several words match
+// the same words in a row.
+type WordList struct {
+	rejected []string
+	flagged  []string
+}
+
+func NewWordList(rejected []string, flagged []string) WordList {
+	return WordList{rejected: normalize(rejected), flagged: normalize(flagged)}
+}
+
+func (w WordList) Moderate(_ context.Context, comment common.Comment) (Decision, error) {
+	text := " " + common.NormalizeName(comment.Text) + " "
+	if word, ok := contains(text, w.rejected); ok {
+		return Decision{Verdict: Reject, Reason: "contains '" + word + "'"}, nil
+	}
+	if word, ok := contains(text, w.flagged); ok {
+		return Decision{Verdict: Flag, Reason: "contains '" + word + "'"}, nil
+	}
+	return Decision{Verdict: Allow}, nil
+}
+
+func normalize(words []string) []string {
+	var result []string
+	for _, word := range words {
+		if normalized := common.NormalizeName(word); len(normalized) > 0 {
+			result = append(result, normalized)
+		}
+	}
+	return result
+}
+
+func contains(text string, words []string) (string, bool) {
+	for _, word := range words {
+		if strings.Contains(text, " "+word+" ") {
+			return word, true
+		}
+	}
+	return "", false
+}
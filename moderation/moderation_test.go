@@ -0,0 +1,42 @@
+package moderation
+
+import (
+	"context"
+	"fishfishes_backend/common"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestWordList(t *testing.T) {
+
+	t.Parallel()
+
+	moderator := NewWordList([]string{"Idiot", "dynamite fishing"}, []string{"poaching", " "})
+
+	type test struct {
+		text     string
+		expected Decision
+	}
+
+	cases := map[string]test{
+		"clean":                  {"Great pike, congrats!", Decision{Verdict: Allow}},
+		"rejected":               {"What an IDIOT.", Decision{Verdict: Reject, Reason: "contains 'idiot'"}},
+		"rejected phrase":        {"Try dynamite-fishing there", Decision{Verdict: Reject, Reason: "contains 'dynamite fishing'"}},
+		"flagged":                {"Isn't that poaching?", Decision{Verdict: Flag, Reason: "contains 'poaching'"}},
+		"part of a word":         {"Idiotic weather today", Decision{Verdict: Allow}},
+		"rejected beats flagged": {"poaching idiot", Decision{Verdict: Reject, Reason: "contains 'idiot'"}},
+	}
+
+	for name, tc := range cases {
+
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			decision, err := moderator.Moderate(context.Background(), common.Comment{Text: tc.text})
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, decision)
+		})
+	}
+}
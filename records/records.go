@@ -0,0 +1,155 @@
+// Package records determines the personal records of anglers from their catches.
+package records
+
+import (
+	"fishfishes_backend/common"
+	"sort"
+	"time"
+)
+
+// Locator returns the time zone of coordinates, in which the days of the sessions are taken.
+type Locator func(coordinates common.Coordinates) *time.Location
+
+// SpeciesKey returns the key of the records of the catch: its species id or, if the species is not in the
+// catalog, its name.
+func SpeciesKey(catch common.Catch) string {
+	if len(catch.SpeciesId) > 0 {
+		return catch.SpeciesId
+	}
+	return catch.Fish
+}
+
+// Compute returns the records of the species among the catches of the spots, in the order largest, heaviest,
+// most fish. Kinds without any catch are left out. Of equal values the one found first is kept.
+func Compute(spots []common.Fish_spot, species string, locate Locator) []common.Record {
+
+	var largest, heaviest, mostFish *common.Record
+
+	type session struct {
+		spot string
+		day  string
+	}
+	sessions := map[session]*common.Record{}
+	var order []session
+
+	for _, spot := range spots {
+		for _, catch := range spot.Catches {
+			if SpeciesKey(catch) != species {
+				continue
+			}
+			record := func(kind string, value float64, unit string) *common.Record {
+				return &common.Record{Kind: kind, Value: value, Unit: unit, CatchId: catch.Id, SpotId: spot.Id, SpotTitle: spot.Marker.Title, CaughtAt: catch.CaughtAt}
+			}
+
+			if size := float64(catch.Size); largest == nil || size > largest.Value {
+				largest = record(common.RecordLargest, size, common.UnitCentimeter)
+			}
+			if catch.Weight != nil && (heaviest == nil || *catch.Weight > heaviest.Value) {
+				heaviest = record(common.RecordHeaviest, *catch.Weight, common.UnitKilogram)
+			}
+
+			// catches without timestamp can not be assigned to a session
+			if catch.CaughtAt == nil {
+				continue
+			}
+			key := session{spot: spot.Id, day: catch.CaughtAt.In(locate(spot.Marker.Coordinates)).Format("2006-01-02")}
+			if s, ok := sessions[key]; ok {
+				s.Value += float64(catch.Number)
+				if catch.CaughtAt.Before(*s.CaughtAt) {
+					s.CatchId, s.CaughtAt = catch.Id, catch.CaughtAt
+				}
+				continue
+			}
+			sessions[key] = record(common.RecordMostFish, float64(catch.Number), "")
+			order = append(order, key)
+		}
+	}
+
+	for _, key := range order {
+		if s := sessions[key]; mostFish == nil || s.Value > mostFish.Value {
+			mostFish = s
+		}
+	}
+
+	var result []common.Record
+	for _, r := range []*common.Record{largest, heaviest, mostFish} {
+		if r != nil {
+			result = append(result, *r)
+		}
+	}
+	return result
+}
+
+// Changes returns the history entries for the changes from the previous to the current records. A record
+// changes if its value or the catch it was set with changes; a record without any catch left is dropped
+// without an entry.
+func Changes(previous []common.Record, current []common.Record, at time.Time) []common.RecordChange {
+
+	before := map[string]common.Record{}
+	for _, r := range previous {
+		before[r.Kind] = r
+	}
+
+	var changes []common.RecordChange
+	for _, r := range current {
+		old, ok := before[r.Kind]
+		if !ok {
+			changes = append(changes, common.RecordChange{Record: r, RecordedAt: at})
+			continue
+		}
+		if old.Value != r.Value || old.CatchId != r.CatchId || old.SpotId != r.SpotId {
+			value := old.Value
+			changes = append(changes, common.RecordChange{Record: r, Previous: &value, RecordedAt: at})
+		}
+	}
+	return changes
+}
+
+// Update recomputes the records of the given species from the spots and appends the changes to their
+// history. It returns the updated records of the species which changed, ordered by species, and all changes.
+func Update(stored []common.PersonalRecords, spots []common.Fish_spot, species []string, locate Locator, at time.Time) ([]common.PersonalRecords, []common.RecordChange) {
+
+	byKey := map[string]common.PersonalRecords{}
+	for _, r := range stored {
+		byKey[r.Species] = r
+	}
+
+	var updated []common.PersonalRecords
+	var allChanges []common.RecordChange
+	done := map[string]bool{}
+	for _, key := range species {
+		if done[key] || len(key) == 0 {
+			continue
+		}
+		done[key] = true
+
+		current := Compute(spots, key, locate)
+		records := byKey[key]
+		changes := Changes(records.Records, current, at)
+		if len(changes) == 0 && len(current) == len(records.Records) {
+			continue
+		}
+
+		records.Species = key
+		records.Fish = fishName(spots, key, records.Fish)
+		records.Records = current
+		records.History = append(records.History, changes...)
+		updated = append(updated, records)
+		allChanges = append(allChanges, changes...)
+	}
+
+	sort.Slice(updated, func(i, j int) bool { return updated[i].Species < updated[j].Species })
+	return updated, allChanges
+}
+
+// fishName returns the name of the catches of the species, or the fallback, if there are none.
+func fishName(spots []common.Fish_spot, species string, fallback string) string {
+	for _, spot := range spots {
+		for _, catch := range spot.Catches {
+			if SpeciesKey(catch) == species {
+				return catch.Fish
+			}
+		}
+	}
+	return fallback
+}
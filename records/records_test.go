@@ -0,0 +1,113 @@
+package records
+
+import (
+	"fishfishes_backend/common"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func utc(common.Coordinates) *time.Location {
+	return time.UTC
+}
+
+func at(value string) *time.Time {
+	t, _ := time.Parse(time.RFC3339, value)
+	return &t
+}
+
+func weight(kg float64) *float64 {
+	return &kg
+}
+
+func logbook() []common.Fish_spot {
+	return []common.Fish_spot{
+		{
+			Id:     "lake",
+			Marker: common.Marker{Title: "Lake"},
+			Catches: []common.Catch{
+				{Id: "c1", SpeciesId: "northern-pike", Fish: "The Northern Pike", Number: 1, Size: 70, Weight: weight(2.5), CaughtAt: at("2023-05-01T06:00:00Z")},
+				{Id: "c2", SpeciesId: "northern-pike", Fish: "The Northern Pike", Number: 2, Size: 55, CaughtAt: at("2023-05-01T09:00:00Z")},
+				{Id: "c3", SpeciesId: "tench", Fish: "Tench", Number: 5, Size: 30},
+			},
+		},
+		{
+			Id:     "river",
+			Marker: common.Marker{Title: "River"},
+			Catches: []common.Catch{
+				{Id: "c4", SpeciesId: "northern-pike", Fish: "The Northern Pike", Number: 1, Size: 82, Weight: weight(2.1), CaughtAt: at("2023-06-01T06:00:00Z")},
+				{Id: "c5", SpeciesId: "northern-pike", Fish: "The Northern Pike", Number: 2, Size: 40, CaughtAt: at("2023-06-02T06:00:00Z")},
+				{Id: "c6", Fish: "Nothern Pike", Number: 1, Size: 99},
+			},
+		},
+	}
+}
+
+func TestCompute(t *testing.T) {
+	records := Compute(logbook(), "northern-pike", utc)
+	assert.Len(t, records, 3)
+
+	assert.Equal(t, common.Record{Kind: common.RecordLargest, Value: 82, Unit: common.UnitCentimeter, CatchId: "c4", SpotId: "river", SpotTitle: "River", CaughtAt: at("2023-06-01T06:00:00Z")}, records[0])
+	assert.Equal(t, "c1", records[1].CatchId)
+	assert.Equal(t, 2.5, records[1].Value)
+	assert.Equal(t, common.Record{Kind: common.RecordMostFish, Value: 3, CatchId: "c1", SpotId: "lake", SpotTitle: "Lake", CaughtAt: at("2023-05-01T06:00:00Z")}, records[2])
+
+	// catches without timestamp set no session record
+	records = Compute(logbook(), "tench", utc)
+	assert.Len(t, records, 1)
+	assert.Equal(t, common.RecordLargest, records[0].Kind)
+
+	assert.Equal(t, "c6", Compute(logbook(), "Nothern Pike", utc)[0].CatchId)
+	assert.Empty(t, Compute(logbook(), "perch", utc))
+}
+
+func TestComputeSessionsInLocalTime(t *testing.T) {
+	spots := []common.Fish_spot{{Id: "lake", Catches: []common.Catch{
+		{Id: "a", SpeciesId: "perch", Number: 2, Size: 20, CaughtAt: at("2023-05-01T21:30:00Z")},
+		{Id: "b", SpeciesId: "perch", Number: 2, Size: 20, CaughtAt: at("2023-05-01T22:30:00Z")},
+	}}}
+	assert.Equal(t, 4.0, Compute(spots, "perch", utc)[1].Value)
+
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	records := Compute(spots, "perch", func(common.Coordinates) *time.Location { return berlin })
+	assert.Equal(t, 2.0, records[1].Value)
+
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	records = Compute(spots, "perch", func(common.Coordinates) *time.Location { return tokyo })
+	assert.Equal(t, 4.0, records[1].Value)
+}
+
+func TestUpdate(t *testing.T) {
+	spots := logbook()
+	first := *at("2023-06-03T00:00:00Z")
+
+	updated, changes := Update(nil, spots, []string{"northern-pike", "northern-pike"}, utc, first)
+	assert.Len(t, updated, 1)
+	assert.Equal(t, "The Northern Pike", updated[0].Fish)
+	assert.Len(t, changes, 3)
+	assert.Nil(t, changes[0].Previous)
+
+	// nothing changed
+	again, changes := Update(updated, spots, []string{"northern-pike"}, utc, first)
+	assert.Empty(t, again)
+	assert.Empty(t, changes)
+
+	// deleting the largest pike revises the record to the next largest one
+	spots[1].Catches = spots[1].Catches[1:]
+	second := *at("2023-06-04T00:00:00Z")
+	updated, changes = Update(updated, spots, []string{"northern-pike"}, utc, second)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, common.RecordLargest, changes[0].Kind)
+	assert.Equal(t, 70.0, changes[0].Value)
+	assert.Equal(t, 82.0, *changes[0].Previous)
+	assert.Equal(t, second, changes[0].RecordedAt)
+	assert.Len(t, updated[0].History, 4)
+	assert.Equal(t, "c1", updated[0].Records[0].CatchId)
+	assert.Equal(t, "c1", updated[0].Records[1].CatchId)
+
+	// deleting the last catch with weight drops the record
+	spots[0].Catches[0].Weight = nil
+	updated, changes = Update(updated, spots, []string{"northern-pike"}, utc, second)
+	assert.Empty(t, changes)
+	assert.Len(t, updated[0].Records, 2)
+}
@@ -0,0 +1,228 @@
+// Package regulations checks catches against the fishing rules of the regions they were made in.
+//
+// The regions are read from GeoJSON files. Every Polygon or MultiPolygon feature is a region with the
+// properties "id", "name" and "rules", a list of rules as in common.RegulationRule, for example
+//
+//	{"id": "de-by", "name": "Bavaria", "rules": [{"speciesId": "northern-pike", "minSize": 50,
+//	  "closedSeasons": [{"from": "02-15", "to": "04-30"}]}]}
+package regulations
+
+import (
+	"encoding/json"
+	"fishfishes_backend/common"
+	"fishfishes_backend/common/geo"
+	"fmt"
+	"github.com/pkg/errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const seasonLayout string = "01-02"
+
+// Region is an area with its rules.
+type Region struct {
+	Id    string
+	Name  string
+	Area  geo.MultiPolygon
+	Rules []common.RegulationRule
+}
+
+// Book holds the rules of all regions. Regions may overlap, e.g. a country and one of its states, then the
+// rules of all of them apply.
+type Book struct {
+	Regions []Region
+}
+
+// LoadDir reads the regions of all .geojson files in the directory.
+func LoadDir(dir string) (*Book, error) {
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.geojson"))
+	if err != nil {
+		return nil, err
+	}
+
+	book := &Book{}
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not open regulations file '%s'", path)
+		}
+		regions, err := ReadRegions(f)
+		f.Close()
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not read regulations file '%s'", path)
+		}
+		book.Regions = append(book.Regions, regions...)
+	}
+
+	return book, nil
+}
+
+// ReadRegions reads the regions of a GeoJSON FeatureCollection.
+func ReadRegions(r io.Reader) ([]Region, error) {
+
+	features, err := geo.ReadAreas(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var regions []Region
+	for i, feature := range features {
+		region := Region{Id: feature.Property("id"), Name: feature.Property("name"), Area: feature.Geometry}
+		if len(region.Id) == 0 {
+			return nil, fmt.Errorf("region %d has no id", i)
+		}
+
+		// the rules are decoded as generic JSON by the GeoJSON reader
+		raw, err := json.Marshal(feature.Properties["rules"])
+		if err != nil {
+			return nil, err
+		}
+		err = json.Unmarshal(raw, &region.Rules)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid rules of region '%s'", region.Id)
+		}
+		for _, rule := range region.Rules {
+			for _, season := range rule.ClosedSeasons {
+				if !validDay(season.From) || !validDay(season.To) {
+					return nil, fmt.Errorf("invalid closed season %s to %s of region '%s', expected MM-DD", season.From, season.To, region.Id)
+				}
+			}
+		}
+
+		regions = append(regions, region)
+	}
+
+	return regions, nil
+}
+
+func validDay(day string) bool {
+	// 02-29 must be accepted, so the day is parsed in a leap year
+	_, err := time.Parse("2006-"+seasonLayout, "2000-"+day)
+	return err == nil
+}
+
+// RegionsAt returns the regions containing the coordinates.
+func (b *Book) RegionsAt(coordinates common.Coordinates) []Region {
+	var regions []Region
+	for _, region := range b.Regions {
+		if region.Area.Contains(coordinates) {
+			regions = append(regions, region)
+		}
+	}
+	return regions
+}
+
+// Check evaluates the catches of the spot at the given indexes against the rules at the spot. Catches without
+// timestamp count as made at now. For the bag limit, all catches of the species of the same day in the spot
+// and in the other spots within the region are summed up; a day over the limit is reported once, with the
+// first of the given catches of that day. Days are taken in the given location.
+func (b *Book) Check(spot common.Fish_spot, indexes []int, others []common.Fish_spot, now time.Time, location *time.Location) []common.RegulationWarning {
+
+	var warnings []common.RegulationWarning
+	for _, region := range b.RegionsAt(spot.Marker.Coordinates) {
+		overLimit := map[string]bool{}
+		for _, i := range indexes {
+			catch := spot.Catches[i]
+			for _, rule := range region.Rules {
+				if len(catch.SpeciesId) == 0 || !sameSpecies(rule.SpeciesId, catch.SpeciesId) {
+					continue
+				}
+				warn := func(code string, message string, args ...interface{}) {
+					warnings = append(warnings, common.RegulationWarning{
+						Field:     fmt.Sprintf("catches[%d]", i),
+						Code:      code,
+						RegionId:  region.Id,
+						Region:    region.Name,
+						SpeciesId: rule.SpeciesId,
+						Message:   fmt.Sprintf(message, args...),
+					})
+				}
+
+				size := float64(catch.Size)
+				if rule.MinSize != nil && size < *rule.MinSize {
+					warn(common.WarningUndersized, "%s must be at least %g cm in %s", catch.Fish, *rule.MinSize, region.Name)
+				}
+				if rule.MaxSize != nil && size > *rule.MaxSize {
+					warn(common.WarningOversized, "%s must be at most %g cm in %s", catch.Fish, *rule.MaxSize, region.Name)
+				}
+
+				day := catchTime(catch, now).In(location)
+				for _, season := range rule.ClosedSeasons {
+					if inSeason(day, season) {
+						warn(common.WarningClosedSeason, "%s is protected from %s to %s in %s", catch.Fish, season.From, season.To, region.Name)
+					}
+				}
+
+				bagDay := strings.ToLower(rule.SpeciesId) + "/" + day.Format("2006-01-02")
+				if rule.BagLimit != nil && !overLimit[bagDay] {
+					total := dayTotal(spot, catch.SpeciesId, day, now, location)
+					for _, other := range others {
+						if other.Id != spot.Id && region.Area.Contains(other.Marker.Coordinates) {
+							total += dayTotal(other, catch.SpeciesId, day, now, location)
+						}
+					}
+					if total > *rule.BagLimit {
+						overLimit[bagDay] = true
+						warn(common.WarningBagLimit, "only %d %s may be kept per day in %s, %d were caught", *rule.BagLimit, catch.Fish, region.Name, total)
+					}
+				}
+			}
+		}
+	}
+	return warnings
+}
+
+// inSeason tells whether the day is within the closed season, both ends included.
+func inSeason(day time.Time, season common.ClosedSeason) bool {
+	value := day.Format(seasonLayout)
+	if season.From <= season.To {
+		return value >= season.From && value <= season.To
+	}
+	return value >= season.From || value <= season.To
+}
+
+// dayTotal sums up the fish of a species caught in the spot on the same day as the given one.
+func dayTotal(spot common.Fish_spot, speciesId string, day time.Time, now time.Time, location *time.Location) int {
+	total := 0
+	for _, catch := range spot.Catches {
+		if sameSpecies(catch.SpeciesId, speciesId) && sameDay(catchTime(catch, now).In(location), day) {
+			total += catch.Number
+		}
+	}
+	return total
+}
+
+// sameSpecies tells whether two species ids are the same, ignoring case.
+func sameSpecies(a, b string) bool {
+	return strings.EqualFold(a, b)
+}
+
+func sameDay(a, b time.Time) bool {
+	return a.Year() == b.Year() && a.YearDay() == b.YearDay()
+}
+
+func catchTime(catch common.Catch, now time.Time) time.Time {
+	if catch.CaughtAt != nil {
+		return *catch.CaughtAt
+	}
+	return now
+}
+
+// RulesAt returns the rules of the regions containing the coordinates. A species id restricts the rules to it.
+func (b *Book) RulesAt(coordinates common.Coordinates, speciesId string) []common.RegionRules {
+	result := []common.RegionRules{}
+	for _, region := range b.RegionsAt(coordinates) {
+		rules := []common.RegulationRule{}
+		for _, rule := range region.Rules {
+			if len(speciesId) == 0 || sameSpecies(rule.SpeciesId, speciesId) {
+				rules = append(rules, rule)
+			}
+		}
+		result = append(result, common.RegionRules{RegionId: region.Id, Region: region.Name, SizeUnit: common.UnitCentimeter, Rules: rules})
+	}
+	return result
+}
@@ -0,0 +1,122 @@
+package regulations
+
+import (
+	"fishfishes_backend/common"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	munich   = common.Coordinates{Latitude: 48.14, Longitude: 11.58}
+	chiemsee = common.Coordinates{Latitude: 47.87, Longitude: 12.45}
+	berlin   = common.Coordinates{Latitude: 52.52, Longitude: 13.40}
+)
+
+func load(t *testing.T) *Book {
+	book, err := LoadDir("testdata")
+	assert.NoError(t, err)
+	assert.Len(t, book.Regions, 2)
+	return book
+}
+
+func at(value string) *time.Time {
+	t, _ := time.Parse(time.RFC3339, value)
+	return &t
+}
+
+func codes(warnings []common.RegulationWarning) []string {
+	var result []string
+	for _, w := range warnings {
+		result = append(result, w.Field+":"+w.Code+":"+w.RegionId)
+	}
+	return result
+}
+
+func TestRulesAt(t *testing.T) {
+	book := load(t)
+
+	rules := book.RulesAt(chiemsee, "")
+	assert.Len(t, rules, 2)
+	assert.Equal(t, "Bavaria", rules[0].Region)
+	assert.Len(t, rules[0].Rules, 2)
+	assert.Equal(t, "trout", rules[1].Rules[0].SpeciesId)
+
+	rules = book.RulesAt(munich, "common-carp")
+	assert.Len(t, rules, 1)
+	assert.Equal(t, 60.0, *rules[0].Rules[0].MaxSize)
+
+	assert.Empty(t, book.RulesAt(berlin, ""))
+}
+
+func TestCheck(t *testing.T) {
+	book := load(t)
+	now := *at("2023-06-10T12:00:00Z")
+
+	spot := common.Fish_spot{
+		Id:     "isar",
+		Marker: common.Marker{Coordinates: munich},
+		Catches: []common.Catch{
+			{SpeciesId: "northern-pike", Fish: "The Northern Pike", Number: 1, Size: 45, CaughtAt: at("2023-03-01T08:00:00Z")},
+			{SpeciesId: "common-carp", Fish: "Common Carp", Number: 1, Size: 72},
+			{SpeciesId: "common-carp", Fish: "Common Carp", Number: 1, Size: 40},
+			{Fish: "Something", Number: 5, Size: 1},
+		},
+	}
+	assert.Equal(t, []string{
+		"catches[0]:undersized:de-by",
+		"catches[0]:closed_season:de-by",
+		"catches[1]:oversized:de-by",
+	}, codes(book.Check(spot, []int{0, 1, 2, 3}, nil, now, time.UTC)))
+
+	spot.Marker.Coordinates = berlin
+	assert.Empty(t, book.Check(spot, []int{0, 1, 2, 3}, nil, now, time.UTC))
+}
+
+func TestCheckBagLimit(t *testing.T) {
+	book := load(t)
+	now := *at("2023-06-10T20:00:00Z")
+	pike := func(number int, caughtAt string) common.Catch {
+		return common.Catch{SpeciesId: "northern-pike", Fish: "The Northern Pike", Number: number, Size: 60, CaughtAt: at(caughtAt)}
+	}
+
+	others := []common.Fish_spot{
+		{Id: "lake", Marker: common.Marker{Coordinates: chiemsee}, Catches: []common.Catch{pike(1, "2023-06-10T06:00:00Z")}},
+		{Id: "berlin", Marker: common.Marker{Coordinates: berlin}, Catches: []common.Catch{pike(3, "2023-06-10T06:00:00Z")}},
+		{Id: "yesterday", Marker: common.Marker{Coordinates: munich}, Catches: []common.Catch{pike(3, "2023-06-09T06:00:00Z")}},
+	}
+	spot := common.Fish_spot{Id: "isar", Marker: common.Marker{Coordinates: munich}, Catches: []common.Catch{pike(1, "2023-06-10T10:00:00Z")}}
+
+	assert.Empty(t, book.Check(spot, []int{0}, others, now, time.UTC))
+
+	// without a timestamp the catch counts as caught now
+	spot.Catches = append(spot.Catches, common.Catch{SpeciesId: "northern-pike", Fish: "The Northern Pike", Number: 1, Size: 55})
+	warnings := book.Check(spot, []int{1}, others, now, time.UTC)
+	assert.Equal(t, []string{"catches[1]:bag_limit:de-by"}, codes(warnings))
+	assert.True(t, strings.Contains(warnings[0].Message, "3 were caught"))
+
+	// the catch of the other spot is the day before in UTC, but the same day in Berlin time
+	berlinTime, _ := time.LoadLocation("Europe/Berlin")
+	others[0].Catches[0].CaughtAt = at("2023-06-09T22:30:00Z")
+	assert.Empty(t, book.Check(spot, []int{1}, others, now, time.UTC))
+	assert.Len(t, book.Check(spot, []int{1}, others, now, berlinTime), 1)
+
+	// a day over the limit is reported once, also for species ids differing in case
+	spot.Catches = append(spot.Catches, common.Catch{SpeciesId: "Northern-Pike", Fish: "The Northern Pike", Number: 2, Size: 50})
+	assert.Equal(t, []string{"catches[0]:bag_limit:de-by"}, codes(book.Check(spot, []int{0, 1, 2}, others, now, time.UTC)))
+}
+
+func TestInSeason(t *testing.T) {
+	winter := common.ClosedSeason{From: "10-01", To: "01-31"}
+	assert.True(t, inSeason(*at("2023-12-24T12:00:00Z"), winter))
+	assert.True(t, inSeason(*at("2023-01-31T12:00:00Z"), winter))
+	assert.False(t, inSeason(*at("2023-02-01T12:00:00Z"), winter))
+}
+
+func TestReadRegionsRejectsInvalidSeasons(t *testing.T) {
+	_, err := ReadRegions(strings.NewReader(`{"type": "FeatureCollection", "features": [{"type": "Feature",
+		"properties": {"id": "x", "rules": [{"speciesId": "tench", "closedSeasons": [{"from": "13-01", "to": "02-29"}]}]},
+		"geometry": {"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [1, 1], [0, 0]]]}}]}`))
+	assert.Error(t, err)
+}
@@ -3,9 +3,11 @@ package repository
 import (
 	"context"
 	"fishfishes_backend/common"
+	"fishfishes_backend/common/mongo"
 	"fmt"
 	"github.com/google/uuid"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 const User string = "user"
@@ -58,3 +60,32 @@ func (r Repo) CheckLogin(ctx context.Context, user common.User) (bool, string) {
 	}
 	return true, userEntity.UserId
 }
+
+// GetUserNames returns the names of the users with the given ids. Ids without an account are left out.
+func (r Repo) GetUserNames(ctx context.Context, userIds []string) (map[string]string, error) {
+
+	result := map[string]string{}
+	if len(userIds) == 0 {
+		return result, nil
+	}
+
+	filter := bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: userIds}}}}
+	cur, err := r.db.Database.Collection(User).Find(ctx, filter, options.Find().SetProjection(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	defer mongo.CloseCursor(cur, ctx)
+
+	var users []UserEntity
+	err = cur.All(ctx, &users)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, user := range users {
+		result[user.UserId] = user.Name
+	}
+
+	return result, nil
+}
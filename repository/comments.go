@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"fishfishes_backend/common"
+	"fishfishes_backend/common/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+	mongoClient "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+const (
+	Comments  string = "comments"
+	Reactions string = "reactions"
+)
+
+func targetFilter(target common.Target) bson.D {
+	return bson.D{
+		{Key: "ownerId", Value: target.OwnerId},
+		{Key: "spotId", Value: target.SpotId},
+		{Key: "catchId", Value: target.CatchId},
+	}
+}
+
+// GetComments returns the comments on the target after the cursor which are no replies, the oldest first.
+// It returns one comment more than the limit, if there are more.
+func (r Repo) GetComments(ctx context.Context, target common.Target, cursor *common.Cursor, limit int) ([]common.Comment, error) {
+
+	filter := append(targetFilter(target), bson.E{Key: "parentId", Value: ""})
+	if cursor != nil {
+		filter = append(filter, bson.E{Key: "$or", Value: bson.A{
+			bson.D{{Key: "createdAt", Value: bson.D{{Key: "$gt", Value: cursor.CreatedAt}}}},
+			bson.D{{Key: "createdAt", Value: cursor.CreatedAt}, {Key: "_id", Value: bson.D{{Key: "$gt", Value: cursor.Id}}}},
+		}})
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}}).SetLimit(int64(limit + 1))
+	return r.findComments(ctx, filter, opts)
+}
+
+// GetReplies returns the replies to the given comments, the oldest first.
+func (r Repo) GetReplies(ctx context.Context, commentIds []string) ([]common.Comment, error) {
+	if len(commentIds) == 0 {
+		return []common.Comment{}, nil
+	}
+
+	filter := bson.D{{Key: "parentId", Value: bson.D{{Key: "$in", Value: commentIds}}}}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}})
+	return r.findComments(ctx, filter, opts)
+}
+
+func (r Repo) findComments(ctx context.Context, filter bson.D, opts *options.FindOptions) ([]common.Comment, error) {
+
+	cur, err := r.db.Database.Collection(Comments).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer mongo.CloseCursor(cur, ctx)
+
+	result := []common.Comment{}
+	err = cur.All(ctx, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetComment returns the comment with the given id or common.ErrNotFound.
+func (r Repo) GetComment(ctx context.Context, commentId string) (*common.Comment, error) {
+
+	var comment common.Comment
+	err := r.db.Database.Collection(Comments).FindOne(ctx, bson.D{{Key: "_id", Value: commentId}}).Decode(&comment)
+	if err != nil {
+		if err == mongoClient.ErrNoDocuments {
+			return nil, common.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &comment, nil
+}
+
+func (r Repo) CreateComment(ctx context.Context, comment common.Comment) error {
+
+	_, err := r.db.Database.Collection(Comments).InsertOne(ctx, comment)
+	return err
+}
+
+// UpdateComment changes the text of a comment and whether it is flagged or returns common.ErrNotFound.
+func (r Repo) UpdateComment(ctx context.Context, commentId string, text string, flagged bool, editedAt time.Time) error {
+
+	result, err := r.db.Database.Collection(Comments).UpdateOne(ctx,
+		bson.D{{Key: "_id", Value: commentId}},
+		bson.D{{Key: "$set", Value: bson.D{
+			{Key: "text", Value: text},
+			{Key: "flagged", Value: flagged},
+			{Key: "editedAt", Value: editedAt},
+		}}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return common.ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteComment removes a comment with its replies.
+func (r Repo) DeleteComment(ctx context.Context, commentId string) error {
+
+	_, err := r.db.Database.Collection(Comments).DeleteMany(ctx, bson.D{{Key: "$or", Value: bson.A{
+		bson.D{{Key: "_id", Value: commentId}},
+		bson.D{{Key: "parentId", Value: commentId}},
+	}}})
+	return err
+}
+
+// DeleteTarget removes all comments and reactions on the target, when the target itself is removed.
+func (r Repo) DeleteTarget(ctx context.Context, target common.Target) error {
+
+	_, err := r.db.Database.Collection(Comments).DeleteMany(ctx, targetFilter(target))
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Database.Collection(Reactions).DeleteMany(ctx, targetFilter(target))
+	return err
+}
+
+// SaveReaction stores the reaction of a user, replacing an earlier reaction of the user to the same target.
+func (r Repo) SaveReaction(ctx context.Context, reaction common.Reaction) error {
+
+	_, err := r.db.Database.Collection(Reactions).ReplaceOne(ctx, bson.D{{Key: "_id", Value: reaction.Id}}, reaction, options.Replace().SetUpsert(true))
+	return err
+}
+
+// DeleteReaction removes the reaction of a user to the target or returns common.ErrNotFound.
+func (r Repo) DeleteReaction(ctx context.Context, target common.Target, userId string) error {
+
+	result, err := r.db.Database.Collection(Reactions).DeleteOne(ctx, bson.D{{Key: "_id", Value: common.ReactionId(target, userId)}})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return common.ErrNotFound
+	}
+
+	return nil
+}
+
+// GetReactionCounts returns the number of reactions by type to the spots of the owner and their catches.
+func (r Repo) GetReactionCounts(ctx context.Context, ownerId string) ([]common.ReactionCount, error) {
+
+	pipeline := mongoClient.Pipeline{
+		{{Key: "$match", Value: bson.D{{Key: "ownerId", Value: ownerId}}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: bson.D{{Key: "spotId", Value: "$spotId"}, {Key: "catchId", Value: "$catchId"}, {Key: "type", Value: "$type"}}},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}},
+		{{Key: "$project", Value: bson.D{
+			{Key: "_id", Value: 0},
+			{Key: "spotId", Value: "$_id.spotId"},
+			{Key: "catchId", Value: "$_id.catchId"},
+			{Key: "type", Value: "$_id.type"},
+			{Key: "count", Value: 1},
+		}}},
+	}
+
+	cur, err := r.db.Database.Collection(Reactions).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	defer mongo.CloseCursor(cur, ctx)
+
+	result := []common.ReactionCount{}
+	err = cur.All(ctx, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"fishfishes_backend/common"
+	"fishfishes_backend/common/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+	mongoClient "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+const (
+	Competitions string = "competitions"
+	Submissions  string = "competitionSubmissions"
+)
+
+// GetCompetitions returns the competitions the user organizes, those of the given groups and the public ones,
+// the earliest first. from and to optionally restrict them to those taking place at some time in between.
+func (r Repo) GetCompetitions(ctx context.Context, userId string, groupIds []string, from *time.Time, to *time.Time) ([]common.Competition, error) {
+
+	filter := bson.D{{Key: "$or", Value: bson.A{
+		bson.D{{Key: "organizerId", Value: userId}},
+		bson.D{{Key: "groupId", Value: bson.D{{Key: "$in", Value: nonNil(groupIds)}}}},
+		bson.D{{Key: "public", Value: true}},
+	}}}
+	if from != nil {
+		filter = append(filter, bson.E{Key: "end", Value: bson.D{{Key: "$gte", Value: *from}}})
+	}
+	if to != nil {
+		filter = append(filter, bson.E{Key: "start", Value: bson.D{{Key: "$lte", Value: *to}}})
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "start", Value: 1}})
+	cur, err := r.db.Database.Collection(Competitions).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer mongo.CloseCursor(cur, ctx)
+
+	result := []common.Competition{}
+	err = cur.All(ctx, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetCompetition returns the competition with the given id or common.ErrNotFound.
+func (r Repo) GetCompetition(ctx context.Context, competitionId string) (*common.Competition, error) {
+
+	var competition common.Competition
+	err := r.db.Database.Collection(Competitions).FindOne(ctx, bson.D{{Key: "_id", Value: competitionId}}).Decode(&competition)
+	if err != nil {
+		if err == mongoClient.ErrNoDocuments {
+			return nil, common.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &competition, nil
+}
+
+func (r Repo) CreateCompetition(ctx context.Context, competition common.Competition) error {
+
+	_, err := r.db.Database.Collection(Competitions).InsertOne(ctx, competition)
+	return err
+}
+
+// UpdateCompetition replaces a competition of the organizer or returns common.ErrNotFound.
+func (r Repo) UpdateCompetition(ctx context.Context, competition common.Competition) error {
+
+	filter := bson.D{{Key: "_id", Value: competition.Id}, {Key: "organizerId", Value: competition.OrganizerId}}
+	result, err := r.db.Database.Collection(Competitions).ReplaceOne(ctx, filter, competition)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return common.ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteCompetition removes a competition of the organizer with its submissions or returns common.ErrNotFound.
+func (r Repo) DeleteCompetition(ctx context.Context, organizerId string, competitionId string) error {
+
+	result, err := r.db.Database.Collection(Competitions).DeleteOne(ctx, bson.D{{Key: "_id", Value: competitionId}, {Key: "organizerId", Value: organizerId}})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return common.ErrNotFound
+	}
+
+	_, err = r.db.Database.Collection(Submissions).DeleteMany(ctx, bson.D{{Key: "competitionId", Value: competitionId}})
+	return err
+}
+
+// GetSubmissions returns the submissions to the competition in the order they were made.
+func (r Repo) GetSubmissions(ctx context.Context, competitionId string) ([]common.Submission, error) {
+
+	opts := options.Find().SetSort(bson.D{{Key: "caughtAt", Value: 1}})
+	cur, err := r.db.Database.Collection(Submissions).Find(ctx, bson.D{{Key: "competitionId", Value: competitionId}}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer mongo.CloseCursor(cur, ctx)
+
+	result := []common.Submission{}
+	err = cur.All(ctx, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// CreateSubmission stores a submission or returns common.ErrConflict, if the catch was already submitted.
+func (r Repo) CreateSubmission(ctx context.Context, submission common.Submission) error {
+
+	_, err := r.db.Database.Collection(Submissions).InsertOne(ctx, submission)
+	if mongoClient.IsDuplicateKeyError(err) {
+		return common.ErrConflict
+	}
+	return err
+}
+
+// DeleteSubmission removes a submission of the user or returns common.ErrNotFound.
+func (r Repo) DeleteSubmission(ctx context.Context, userId string, submissionId string) error {
+
+	result, err := r.db.Database.Collection(Submissions).DeleteOne(ctx, bson.D{{Key: "_id", Value: submissionId}, {Key: "userId", Value: userId}})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return common.ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"fishfishes_backend/common"
+	"fishfishes_backend/common/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+	mongoClient "go.mongodb.org/mongo-driver/mongo"
+	"time"
+)
+
+// GetTripEfforts returns the trips of the user with the spots they visited and the catches they made.
+// from and to optionally restrict the start of the trips. Spots and catches deleted since are left out.
+func (r Repo) GetTripEfforts(ctx context.Context, userId string, from *time.Time, to *time.Time) ([]common.TripEffort, error) {
+
+	match := bson.D{{Key: "userId", Value: userId}}
+	start := bson.D{}
+	if from != nil {
+		start = append(start, bson.E{Key: "$gte", Value: *from})
+	}
+	if to != nil {
+		start = append(start, bson.E{Key: "$lte", Value: *to})
+	}
+	if len(start) > 0 {
+		match = append(match, bson.E{Key: "start", Value: start})
+	}
+
+	userSpots := bson.D{{Key: "$match", Value: bson.D{{Key: "$expr", Value: bson.D{
+		{Key: "$eq", Value: bson.A{"$userId", "$$userId"}},
+	}}}}}
+
+	pipeline := mongoClient.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$sort", Value: bson.D{{Key: "start", Value: 1}}}},
+		{{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: Spot},
+			{Key: "let", Value: bson.D{{Key: "userId", Value: "$userId"}, {Key: "spotIds", Value: "$spotIds"}}},
+			{Key: "pipeline", Value: bson.A{
+				userSpots,
+				bson.D{{Key: "$match", Value: bson.D{{Key: "$expr", Value: bson.D{
+					{Key: "$in", Value: bson.A{"$spot.id", "$$spotIds"}},
+				}}}}},
+				bson.D{{Key: "$project", Value: bson.D{
+					{Key: "_id", Value: 0},
+					{Key: "id", Value: "$spot.id"},
+					{Key: "title", Value: "$spot.marker.title"},
+					{Key: "coordinates", Value: "$spot.marker.coordinates"},
+				}}},
+			}},
+			{Key: "as", Value: "spots"},
+		}}},
+		{{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: Spot},
+			{Key: "let", Value: bson.D{{Key: "userId", Value: "$userId"}, {Key: "catchIds", Value: "$catchIds"}}},
+			{Key: "pipeline", Value: bson.A{
+				userSpots,
+				bson.D{{Key: "$unwind", Value: "$spot.catches"}},
+				bson.D{{Key: "$match", Value: bson.D{{Key: "$expr", Value: bson.D{
+					{Key: "$in", Value: bson.A{"$spot.catches.id", "$$catchIds"}},
+				}}}}},
+				bson.D{{Key: "$project", Value: bson.D{
+					{Key: "_id", Value: 0},
+					{Key: "spotId", Value: "$spot.id"},
+					{Key: "speciesId", Value: "$spot.catches.speciesid"},
+					{Key: "fish", Value: "$spot.catches.fish"},
+					{Key: "number", Value: "$spot.catches.number"},
+					{Key: "time", Value: "$spot.catches.time"},
+					{Key: "bait", Value: "$spot.catches.equipment.bait"},
+				}}},
+			}},
+			{Key: "as", Value: "catches"},
+		}}},
+		{{Key: "$project", Value: bson.D{
+			{Key: "start", Value: 1},
+			{Key: "end", Value: 1},
+			{Key: "baits", Value: 1},
+			{Key: "spots", Value: 1},
+			{Key: "catches", Value: 1},
+		}}},
+	}
+
+	cur, err := r.db.Database.Collection(Trips).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	defer mongo.CloseCursor(cur, ctx)
+
+	result := []common.TripEffort{}
+	err = cur.All(ctx, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
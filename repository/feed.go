@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"fishfishes_backend/common"
+	"fishfishes_backend/common/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+	mongoClient "go.mongodb.org/mongo-driver/mongo"
+)
+
+const Activities string = "activities"
+
+func (r Repo) AddActivities(ctx context.Context, activities []common.Activity) error {
+	if len(activities) == 0 {
+		return nil
+	}
+
+	documents := make([]interface{}, 0, len(activities))
+	for _, activity := range activities {
+		documents = append(documents, activity)
+	}
+
+	_, err := r.db.Database.Collection(Activities).InsertMany(ctx, documents)
+	return err
+}
+
+// GetFeed returns the activities of the given users after the cursor, the latest first, with their spots and
+// catches. The activities are read from the activities of the users when the feed is read, so that only
+// those of spots shared with friends and of catches not deleted since are returned. It returns one entry more
+// than the limit, if there are more.
+func (r Repo) GetFeed(ctx context.Context, userIds []string, cursor *common.FeedCursor, limit int) ([]common.FeedEntry, error) {
+
+	match := bson.D{{Key: "userId", Value: bson.D{{Key: "$in", Value: userIds}}}}
+	if cursor != nil {
+		match = append(match, bson.E{Key: "$or", Value: bson.A{
+			bson.D{{Key: "createdAt", Value: bson.D{{Key: "$lt", Value: cursor.CreatedAt}}}},
+			bson.D{{Key: "createdAt", Value: cursor.CreatedAt}, {Key: "_id", Value: bson.D{{Key: "$lt", Value: cursor.Id}}}},
+		}})
+	}
+
+	pipeline := mongoClient.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$sort", Value: bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}}},
+		{{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: Spot},
+			{Key: "let", Value: bson.D{{Key: "userId", Value: "$userId"}, {Key: "spotId", Value: "$spotId"}}},
+			{Key: "pipeline", Value: bson.A{
+				bson.D{{Key: "$match", Value: bson.D{{Key: "$expr", Value: bson.D{{Key: "$and", Value: bson.A{
+					bson.D{{Key: "$eq", Value: bson.A{"$userId", "$$userId"}}},
+					bson.D{{Key: "$eq", Value: bson.A{"$spot.id", "$$spotId"}}},
+					bson.D{{Key: "$in", Value: bson.A{"$spot.visibility", bson.A{common.VisibilityFriends, common.VisibilityPublic}}}},
+				}}}}}}},
+				bson.D{{Key: "$limit", Value: 1}},
+				bson.D{{Key: "$project", Value: bson.D{{Key: "_id", Value: 0}, {Key: "spot", Value: 1}}}},
+			}},
+			{Key: "as", Value: "spots"},
+		}}},
+		{{Key: "$unwind", Value: "$spots"}},
+		{{Key: "$addFields", Value: bson.D{
+			{Key: "spot", Value: "$spots.spot"},
+			{Key: "catch", Value: bson.D{{Key: "$arrayElemAt", Value: bson.A{
+				bson.D{{Key: "$filter", Value: bson.D{
+					{Key: "input", Value: "$spots.spot.catches"},
+					{Key: "cond", Value: bson.D{{Key: "$eq", Value: bson.A{"$$this.id", "$catchId"}}}},
+				}}},
+				0,
+			}}}},
+		}}},
+		{{Key: "$match", Value: bson.D{{Key: "$or", Value: bson.A{
+			bson.D{{Key: "type", Value: common.ActivitySpot}},
+			bson.D{{Key: "catch", Value: bson.D{{Key: "$exists", Value: true}}}},
+		}}}}},
+		{{Key: "$limit", Value: limit + 1}},
+		{{Key: "$project", Value: bson.D{{Key: "spots", Value: 0}}}},
+	}
+
+	cur, err := r.db.Database.Collection(Activities).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	defer mongo.CloseCursor(cur, ctx)
+
+	result := []common.FeedEntry{}
+	err = cur.All(ctx, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// SetSpotVisibility changes who can see a spot of the user or returns common.ErrNotFound.
+func (r Repo) SetSpotVisibility(ctx context.Context, userId string, spotId string, visibility string, hideLocation bool) error {
+
+	result, err := r.db.Database.Collection(Spot).UpdateMany(ctx,
+		bson.D{{Key: "userId", Value: userId}, {Key: "spot.id", Value: spotId}},
+		bson.D{{Key: "$set", Value: bson.D{
+			{Key: "spot.visibility", Value: visibility},
+			{Key: "spot.hidelocation", Value: hideLocation},
+		}}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return common.ErrNotFound
+	}
+
+	return nil
+}
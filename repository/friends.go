@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"fishfishes_backend/common"
+	"fishfishes_backend/common/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+	mongoClient "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const Friendships string = "friendships"
+
+// GetFriendship returns the relationship between two users or nil, if they have none.
+func (r Repo) GetFriendship(ctx context.Context, userId string, otherId string) (*common.Friendship, error) {
+
+	var friendship common.Friendship
+	err := r.db.Database.Collection(Friendships).FindOne(ctx, bson.D{{Key: "_id", Value: common.FriendshipId(userId, otherId)}}).Decode(&friendship)
+	if err != nil {
+		if err == mongoClient.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &friendship, nil
+}
+
+func (r Repo) SaveFriendship(ctx context.Context, friendship common.Friendship) error {
+
+	_, err := r.db.Database.Collection(Friendships).ReplaceOne(ctx, bson.D{{Key: "_id", Value: friendship.Id}}, friendship, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (r Repo) DeleteFriendship(ctx context.Context, id string) error {
+
+	_, err := r.db.Database.Collection(Friendships).DeleteOne(ctx, bson.D{{Key: "_id", Value: id}})
+	return err
+}
+
+// GetFriendships returns the relationships of the user with the given status in both directions, the latest
+// changed first.
+func (r Repo) GetFriendships(ctx context.Context, userId string, status string) ([]common.Friendship, error) {
+
+	filter := bson.D{{Key: "$or", Value: bson.A{
+		bson.D{{Key: "requesterId", Value: userId}, {Key: "status", Value: status}},
+		bson.D{{Key: "addresseeId", Value: userId}, {Key: "status", Value: status}},
+	}}}
+
+	opts := options.Find().SetSort(bson.D{{Key: "updatedAt", Value: -1}})
+	cur, err := r.db.Database.Collection(Friendships).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer mongo.CloseCursor(cur, ctx)
+
+	result := []common.Friendship{}
+	err = cur.All(ctx, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,242 @@
+package repository
+
+import (
+	"context"
+	"fishfishes_backend/common"
+	"fishfishes_backend/common/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+	mongoClient "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	Groups       string = "groups"
+	GroupMembers string = "groupMembers"
+	GroupSpots   string = "groupSpots"
+)
+
+// CreateGroup stores a new group with its owner.
+func (r Repo) CreateGroup(ctx context.Context, group common.Group, owner common.Membership) error {
+
+	_, err := r.db.Database.Collection(Groups).InsertOne(ctx, group)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Database.Collection(GroupMembers).InsertOne(ctx, owner)
+	return err
+}
+
+// GetGroups returns the groups with the given ids.
+func (r Repo) GetGroups(ctx context.Context, groupIds []string) ([]common.Group, error) {
+
+	result := []common.Group{}
+	if len(groupIds) == 0 {
+		return result, nil
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cur, err := r.db.Database.Collection(Groups).Find(ctx, bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: groupIds}}}}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer mongo.CloseCursor(cur, ctx)
+
+	err = cur.All(ctx, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UpdateGroup replaces a group or returns common.ErrNotFound.
+func (r Repo) UpdateGroup(ctx context.Context, group common.Group) error {
+
+	result, err := r.db.Database.Collection(Groups).UpdateOne(ctx,
+		bson.D{{Key: "_id", Value: group.Id}},
+		bson.D{{Key: "$set", Value: bson.D{
+			{Key: "name", Value: group.Name},
+			{Key: "description", Value: group.Description},
+		}}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return common.ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteGroup removes a group with its memberships and spots.
+func (r Repo) DeleteGroup(ctx context.Context, groupId string) error {
+
+	_, err := r.db.Database.Collection(GroupSpots).DeleteMany(ctx, bson.D{{Key: "groupId", Value: groupId}})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Database.Collection(GroupMembers).DeleteMany(ctx, bson.D{{Key: "groupId", Value: groupId}})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Database.Collection(Groups).DeleteOne(ctx, bson.D{{Key: "_id", Value: groupId}})
+	return err
+}
+
+// GetMembership returns the membership of the user in the group or nil, if the user is neither a member nor
+// invited.
+func (r Repo) GetMembership(ctx context.Context, groupId string, userId string) (*common.Membership, error) {
+
+	var membership common.Membership
+	err := r.db.Database.Collection(GroupMembers).FindOne(ctx, bson.D{{Key: "_id", Value: common.MembershipId(groupId, userId)}}).Decode(&membership)
+	if err != nil {
+		if err == mongoClient.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &membership, nil
+}
+
+func (r Repo) SaveMembership(ctx context.Context, membership common.Membership) error {
+
+	_, err := r.db.Database.Collection(GroupMembers).ReplaceOne(ctx, bson.D{{Key: "_id", Value: membership.Id}}, membership, options.Replace().SetUpsert(true))
+	return err
+}
+
+// HandOverGroup makes the member the owner of the group and the owner an admin. Both change in one
+// transaction, which needs a replica set, so that the group never has two owners or none. It returns
+// common.ErrConflict, if the owner is no longer the owner or the member no longer active.
+func (r Repo) HandOverGroup(ctx context.Context, groupId string, ownerId string, memberId string) error {
+
+	session, err := r.db.Client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongoClient.SessionContext) (interface{}, error) {
+		members := r.db.Database.Collection(GroupMembers)
+		setRole := func(userId string, from string, to string) error {
+			filter := bson.D{{Key: "_id", Value: common.MembershipId(groupId, userId)}, {Key: "status", Value: common.MembershipActive}}
+			if len(from) > 0 {
+				filter = append(filter, bson.E{Key: "role", Value: from})
+			}
+			result, err := members.UpdateOne(sc, filter, bson.D{{Key: "$set", Value: bson.D{{Key: "role", Value: to}}}})
+			if err != nil {
+				return err
+			}
+			if result.MatchedCount == 0 {
+				return common.ErrConflict
+			}
+			return nil
+		}
+
+		if err := setRole(ownerId, common.GroupOwner, common.GroupAdmin); err != nil {
+			return nil, err
+		}
+		return nil, setRole(memberId, "", common.GroupOwner)
+	})
+	return err
+}
+
+func (r Repo) DeleteMembership(ctx context.Context, groupId string, userId string) error {
+
+	_, err := r.db.Database.Collection(GroupMembers).DeleteOne(ctx, bson.D{{Key: "_id", Value: common.MembershipId(groupId, userId)}})
+	return err
+}
+
+// GetMemberships returns the memberships and invitations of the user.
+func (r Repo) GetMemberships(ctx context.Context, userId string) ([]common.Membership, error) {
+	return r.findMemberships(ctx, bson.D{{Key: "userId", Value: userId}})
+}
+
+// GetGroupMembers returns the members and invited users of the group in the order they joined.
+func (r Repo) GetGroupMembers(ctx context.Context, groupId string) ([]common.Membership, error) {
+	return r.findMemberships(ctx, bson.D{{Key: "groupId", Value: groupId}})
+}
+
+func (r Repo) findMemberships(ctx context.Context, filter bson.D) ([]common.Membership, error) {
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	cur, err := r.db.Database.Collection(GroupMembers).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer mongo.CloseCursor(cur, ctx)
+
+	result := []common.Membership{}
+	err = cur.All(ctx, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetGroupSpots returns the spots of the given groups.
+func (r Repo) GetGroupSpots(ctx context.Context, groupIds []string) ([]common.GroupSpot, error) {
+
+	result := []common.GroupSpot{}
+	if len(groupIds) == 0 {
+		return result, nil
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "marker.title", Value: 1}})
+	cur, err := r.db.Database.Collection(GroupSpots).Find(ctx, bson.D{{Key: "groupId", Value: bson.D{{Key: "$in", Value: groupIds}}}}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer mongo.CloseCursor(cur, ctx)
+
+	err = cur.All(ctx, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// SaveGroupSpot stores a spot of a group, updating the spot of the group with the same id. The creator is
+// only set when the spot is added.
+func (r Repo) SaveGroupSpot(ctx context.Context, spot common.GroupSpot) error {
+
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "marker", Value: spot.Marker},
+			{Key: "notes", Value: spot.Notes},
+			{Key: "updatedBy", Value: spot.UpdatedBy},
+			{Key: "updatedAt", Value: spot.UpdatedAt},
+		}},
+		{Key: "$setOnInsert", Value: bson.D{{Key: "createdBy", Value: spot.CreatedBy}}},
+	}
+	_, err := r.db.Database.Collection(GroupSpots).UpdateOne(ctx,
+		bson.D{{Key: "_id", Value: spot.Id}, {Key: "groupId", Value: spot.GroupId}},
+		update, options.Update().SetUpsert(true))
+	if mongoClient.IsDuplicateKeyError(err) {
+		// the id is taken by a spot of another group
+		return common.ErrConflict
+	}
+	return err
+}
+
+// DeleteGroupSpot removes a spot of a group or returns common.ErrNotFound.
+func (r Repo) DeleteGroupSpot(ctx context.Context, groupId string, spotId string) error {
+
+	result, err := r.db.Database.Collection(GroupSpots).DeleteOne(ctx, bson.D{{Key: "_id", Value: spotId}, {Key: "groupId", Value: groupId}})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return common.ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"go.mongodb.org/mongo-driver/bson"
+	mongoClient "go.mongodb.org/mongo-driver/mongo"
+	"time"
+)
+
+const Migrations string = "migrations"
+
+// MigrationEntity marks a data migration as done.
+type MigrationEntity struct {
+	Name      string    `bson:"_id"`
+	AppliedAt time.Time `bson:"appliedAt"`
+}
+
+// IsMigrated tells whether the migration of the given name was marked as done.
+func (r Repo) IsMigrated(ctx context.Context, name string) (bool, error) {
+
+	err := r.db.Database.Collection(Migrations).FindOne(ctx, bson.D{{Key: "_id", Value: name}}).Err()
+	if err != nil {
+		if err == mongoClient.ErrNoDocuments {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// SetMigrated marks the migration of the given name as done.
+func (r Repo) SetMigrated(ctx context.Context, name string) error {
+
+	_, err := r.db.Database.Collection(Migrations).InsertOne(ctx, MigrationEntity{Name: name, AppliedAt: time.Now().UTC()})
+	if mongoClient.IsDuplicateKeyError(err) {
+		return nil
+	}
+	return err
+}
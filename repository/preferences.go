@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"fishfishes_backend/common"
+	"go.mongodb.org/mongo-driver/bson"
+	mongoClient "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const Preferences string = "preferences"
+
+type PreferencesEntity struct {
+	UserId      string                 `bson:"_id"`
+	Preferences common.UserPreferences `bson:"preferences"`
+}
+
+// GetPreferences returns the preferences of the user or the default preferences, if the user has none.
+func (r Repo) GetPreferences(ctx context.Context, userId string) (*common.UserPreferences, error) {
+
+	result := r.db.Database.Collection(Preferences).FindOne(ctx, bson.D{{Key: "_id", Value: userId}})
+	err := result.Err()
+	if err != nil {
+		if err == mongoClient.ErrNoDocuments {
+			preferences := common.DefaultPreferences
+			return &preferences, nil
+		}
+		return nil, err
+	}
+
+	var entity PreferencesEntity
+	err = result.Decode(&entity)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.Preferences, nil
+}
+
+func (r Repo) SavePreferences(ctx context.Context, userId string, preferences common.UserPreferences) error {
+
+	entity := PreferencesEntity{
+		UserId:      userId,
+		Preferences: preferences,
+	}
+
+	_, err := r.db.Database.Collection(Preferences).ReplaceOne(ctx, bson.D{{Key: "_id", Value: userId}}, entity, options.Replace().SetUpsert(true))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
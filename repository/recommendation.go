@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"fishfishes_backend/common"
+	"fishfishes_backend/common/mongo"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	mongoClient "go.mongodb.org/mongo-driver/mongo"
+)
+
+// nearbyDegrees is the half edge length of the box around a spot in which public catches count as nearby.
+const nearbyDegrees float64 = 0.1
+
+// GetEquipmentStats groups the catches of a species by equipment. With public=false only the catches of the
+// given user are used, otherwise the catches of the public spots of all other users, which are returned
+// without any user reference. Private spots and those shared with friends only never count for other users.
+func (r Repo) GetEquipmentStats(ctx context.Context, userId string, query common.RecommendationQuery, public bool) ([]common.EquipmentStats, error) {
+
+	match := bson.D{{Key: "userId", Value: userId}}
+	if public {
+		match = bson.D{
+			{Key: "userId", Value: bson.D{{Key: "$ne", Value: userId}}},
+			{Key: "spot.visibility", Value: common.VisibilityPublic},
+		}
+		if query.Coordinates != nil {
+			// moving the box around would reveal where spots with a hidden location are
+			match = append(match, bson.E{Key: "spot.hidelocation", Value: bson.D{{Key: "$ne", Value: true}}})
+			match = append(match, nearbyMatch(*query.Coordinates)...)
+		}
+	} else if len(query.SpotId) > 0 {
+		match = append(match, bson.E{Key: "spot.id", Value: query.SpotId})
+	}
+
+	timeMatch := bson.D{{Key: "$eq", Value: bson.A{"$spot.catches.time", query.Time}}}
+	depthMatch := bson.D{{Key: "$literal", Value: false}}
+	if query.Deep != nil {
+		depthMatch = bson.D{{Key: "$eq", Value: bson.A{depthBucket("$spot.catches.deep"), depthLabel(*query.Deep)}}}
+	}
+
+	pipeline := mongoClient.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$unwind", Value: "$spot.catches"}},
+		{{Key: "$match", Value: bson.D{{Key: "spot.catches.fish", Value: query.Fish}}}},
+		{{Key: "$group", Value: bson.D{
+			// grouped by the text only, the items referenced in the tackle boxes are private to their owners
+			{Key: "_id", Value: bson.D{
+				{Key: "name", Value: "$spot.catches.equipment.name"},
+				{Key: "bait", Value: "$spot.catches.equipment.bait"},
+				{Key: "leader", Value: "$spot.catches.equipment.leader"},
+			}},
+			{Key: "catches", Value: bson.D{{Key: "$sum", Value: 1}}},
+			{Key: "fish", Value: bson.D{{Key: "$sum", Value: "$spot.catches.number"}}},
+			{Key: "fishAtTime", Value: bson.D{{Key: "$sum", Value: bson.D{
+				{Key: "$cond", Value: bson.A{timeMatch, "$spot.catches.number", 0}},
+			}}}},
+			{Key: "fishAtDepth", Value: bson.D{{Key: "$sum", Value: bson.D{
+				{Key: "$cond", Value: bson.A{depthMatch, "$spot.catches.number", 0}},
+			}}}},
+		}}},
+	}
+
+	cur, err := r.db.Database.Collection(Spot).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	defer mongo.CloseCursor(cur, ctx)
+
+	var stats []common.EquipmentStats
+	err = cur.All(ctx, &stats)
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// nearbyMatch returns a filter for spots inside a box around the given coordinates.
+func nearbyMatch(coordinates common.Coordinates) bson.D {
+	return bson.D{
+		{Key: "spot.marker.coordinates.latitude", Value: bson.D{
+			{Key: "$gte", Value: coordinates.Latitude - nearbyDegrees},
+			{Key: "$lte", Value: coordinates.Latitude + nearbyDegrees},
+		}},
+		{Key: "spot.marker.coordinates.longitude", Value: bson.D{
+			{Key: "$gte", Value: coordinates.Longitude - nearbyDegrees},
+			{Key: "$lte", Value: coordinates.Longitude + nearbyDegrees},
+		}},
+	}
+}
+
+// depthLabel returns the label of the depth bucket the given depth belongs to, matching depthBucket.
+func depthLabel(deep float64) string {
+	lower := 0
+	for _, upper := range depthRanges {
+		if deep < float64(upper) {
+			return fmt.Sprintf("%d-%d", lower, upper)
+		}
+		lower = upper
+	}
+	return fmt.Sprintf("%d+", lower)
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"fishfishes_backend/common"
+	"fishfishes_backend/common/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const Records string = "records"
+
+type RecordsEntity struct {
+	Id      string                 `bson:"_id"`
+	UserId  string                 `bson:"userId"`
+	Records common.PersonalRecords `bson:"records"`
+}
+
+// GetRecords returns the personal records of the user for all species, ordered by species.
+func (r Repo) GetRecords(ctx context.Context, userId string) ([]common.PersonalRecords, error) {
+
+	opts := options.Find().SetSort(bson.D{{Key: "records.species", Value: 1}})
+	cur, err := r.db.Database.Collection(Records).Find(ctx, bson.D{{Key: "userId", Value: userId}}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer mongo.CloseCursor(cur, ctx)
+
+	var entities []RecordsEntity
+	err = cur.All(ctx, &entities)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []common.PersonalRecords{}
+	for _, entity := range entities {
+		result = append(result, entity.Records)
+	}
+
+	return result, nil
+}
+
+// SaveRecords stores the personal records of the user, replacing the stored records of the same species.
+func (r Repo) SaveRecords(ctx context.Context, userId string, records []common.PersonalRecords) error {
+
+	for _, record := range records {
+		entity := RecordsEntity{
+			Id:      userId + "/" + record.Species,
+			UserId:  userId,
+			Records: record,
+		}
+
+		_, err := r.db.Database.Collection(Records).ReplaceOne(ctx, bson.D{{Key: "_id", Value: entity.Id}}, entity, options.Replace().SetUpsert(true))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
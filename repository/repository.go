@@ -8,29 +8,21 @@ import (
 	"github.com/google/uuid"
 	"go.mongodb.org/mongo-driver/bson"
 	mongoClient "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"reflect"
+	"sort"
+	"time"
 )
 
 type SpotEntity struct {
-	Id     string           `bson:"_id"`
-	UserId string           `bson:"userId"`
-	Spot   common.Fish_spot `bson:"spot"`
+	Id        string           `bson:"_id"`
+	UserId    string           `bson:"userId"`
+	CreatedAt time.Time        `bson:"createdAt"`
+	Spot      common.Fish_spot `bson:"spot"`
 }
 
 const Spot string = "spot"
 
-// albums slice to seed record album data.
-var fishListSaltwWater = []string{
-	"Sword Fish", "Atlantic Cod", "Mackerel",
-	"Trout", "Atlantic Salmon", "Tuna", "Shark",
-	"Red Mullet", "Barramundi", "Mahi-Mahi", "Anchovy", "Haddock", "Red Seabream Fish", "Gold Line Fish",
-	"Pollack", "Ocean Sunfish", "Northern Red Snapper", "Bonito", "Bluefish",
-}
-
-var fishListFreshWater = []string{
-	"Common Carp", "Oscar Fish", "Wels Catfish", "Sauger Fish", "The Northern Pike", "Tench", "European Eel", "Cisco Fish", "Black Crappie",
-	"Brown Bullhead Catfish", "Golden Shiner", "Largemouth Bass", "Fathead Minnow", "Walleye Fish", "Common Dace",
-	"European Chub", "", "", "", "", ""}
-
 type Repo struct {
 	db *mongo.Database
 }
@@ -50,6 +42,155 @@ func (r Repo) InstallIndexes() error {
 	//	return err
 	//}
 
+	err := r.db.InstallIndex(Spot, "spot_user_created_idx", bson.D{
+		{Key: "userId", Value: 1},
+		{Key: "createdAt", Value: 1},
+	})
+	if err != nil {
+		return err
+	}
+
+	err = r.db.InstallIndex(SpeciesCollection, "species_name_idx", bson.D{
+		{Key: "commonName", Value: 1},
+	}, func(opts *options.IndexOptions) {
+		opts.SetUnique(true)
+	})
+	if err != nil {
+		return err
+	}
+
+	err = r.db.InstallIndex(Records, "records_user_idx", bson.D{
+		{Key: "userId", Value: 1},
+	})
+	if err != nil {
+		return err
+	}
+
+	err = r.db.InstallIndex(Tackle, "tackle_user_idx", bson.D{
+		{Key: "userId", Value: 1},
+		{Key: "type", Value: 1},
+	})
+	if err != nil {
+		return err
+	}
+
+	err = r.db.InstallIndex(Trips, "trips_user_start_idx", bson.D{
+		{Key: "userId", Value: 1},
+		{Key: "start", Value: -1},
+	})
+	if err != nil {
+		return err
+	}
+
+	// relationships are looked up from both sides, by the user who requested and the one addressed
+	err = r.db.InstallIndex(Friendships, "friendships_requester_idx", bson.D{
+		{Key: "requesterId", Value: 1},
+		{Key: "status", Value: 1},
+	})
+	if err != nil {
+		return err
+	}
+
+	err = r.db.InstallIndex(Friendships, "friendships_addressee_idx", bson.D{
+		{Key: "addresseeId", Value: 1},
+		{Key: "status", Value: 1},
+	})
+	if err != nil {
+		return err
+	}
+
+	err = r.db.InstallIndex(Activities, "activities_user_created_idx", bson.D{
+		{Key: "userId", Value: 1},
+		{Key: "createdAt", Value: -1},
+	})
+	if err != nil {
+		return err
+	}
+
+	err = r.db.InstallIndex(Comments, "comments_target_idx", bson.D{
+		{Key: "ownerId", Value: 1},
+		{Key: "spotId", Value: 1},
+		{Key: "catchId", Value: 1},
+		{Key: "parentId", Value: 1},
+		{Key: "createdAt", Value: 1},
+	})
+	if err != nil {
+		return err
+	}
+
+	err = r.db.InstallIndex(Comments, "comments_parent_idx", bson.D{
+		{Key: "parentId", Value: 1},
+	})
+	if err != nil {
+		return err
+	}
+
+	err = r.db.InstallIndex(Reactions, "reactions_owner_idx", bson.D{
+		{Key: "ownerId", Value: 1},
+	})
+	if err != nil {
+		return err
+	}
+
+	err = r.db.InstallIndex(GroupMembers, "group_members_user_idx", bson.D{
+		{Key: "userId", Value: 1},
+	})
+	if err != nil {
+		return err
+	}
+
+	err = r.db.InstallIndex(GroupMembers, "group_members_group_idx", bson.D{
+		{Key: "groupId", Value: 1},
+		{Key: "createdAt", Value: 1},
+	})
+	if err != nil {
+		return err
+	}
+
+	err = r.db.InstallIndex(GroupSpots, "group_spots_group_idx", bson.D{
+		{Key: "groupId", Value: 1},
+	})
+	if err != nil {
+		return err
+	}
+
+	err = r.db.InstallIndex(Competitions, "competitions_time_idx", bson.D{
+		{Key: "start", Value: 1},
+		{Key: "end", Value: 1},
+	})
+	if err != nil {
+		return err
+	}
+
+	err = r.db.InstallIndex(Competitions, "competitions_group_idx", bson.D{
+		{Key: "groupId", Value: 1},
+	})
+	if err != nil {
+		return err
+	}
+
+	err = r.db.InstallIndex(Submissions, "submissions_competition_idx", bson.D{
+		{Key: "competitionId", Value: 1},
+		{Key: "caughtAt", Value: 1},
+	})
+	if err != nil {
+		return err
+	}
+
+	err = r.db.InstallIndex(Spot, searchIndex, searchFields, func(opts *options.IndexOptions) {
+		opts.SetWeights(searchWeights)
+	})
+	if err != nil {
+		return err
+	}
+
+	err = r.db.InstallIndex(GroupSpots, groupSearchIndex, groupSearchFields, func(opts *options.IndexOptions) {
+		opts.SetWeights(groupSearchWeights)
+	})
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -92,9 +233,10 @@ func (r Repo) GetAllSpots(ctx context.Context, id string) (*[]common.Fish_spot,
 func (r Repo) SaveSpot(ctx context.Context, userId string, spot common.Fish_spot) error {
 
 	spotEntity := SpotEntity{
-		Id:     uuid.New().String(),
-		UserId: userId,
-		Spot:   spot,
+		Id:        uuid.New().String(),
+		UserId:    userId,
+		CreatedAt: time.Now().UTC(),
+		Spot:      spot,
 	}
 
 	_, err := r.db.Database.Collection(Spot).InsertOne(ctx, spotEntity)
@@ -105,10 +247,226 @@ func (r Repo) SaveSpot(ctx context.Context, userId string, spot common.Fish_spot
 	return nil
 }
 
-func (r Repo) GetFishListSalt() []string {
-	return fishListSaltwWater
+// GetSpotUserIds returns the ids of all users having spots.
+func (r Repo) GetSpotUserIds(ctx context.Context) ([]string, error) {
+
+	values, err := r.db.Database.Collection(Spot).Distinct(ctx, "userId", bson.D{})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []string
+	for _, value := range values {
+		if id, ok := value.(string); ok {
+			result = append(result, id)
+		}
+	}
+
+	return result, nil
 }
 
-func (r Repo) GetFishListFresh() []string {
-	return fishListFreshWater
+// UpdateSpots calls update for every stored spot and saves the spots for which it returns true. Only the
+// fields which changed are written, so that concurrent writes to other fields, like the weather of a catch,
+// are kept. A spot whose changed catches moved in the meantime is skipped. It returns the number of saved spots.
+func (r Repo) UpdateSpots(ctx context.Context, update func(spot *common.Fish_spot, createdAt time.Time) bool) (int, error) {
+
+	cur, err := r.db.Database.Collection(Spot).Find(ctx, bson.D{})
+	if err != nil {
+		return 0, err
+	}
+
+	defer mongo.CloseCursor(cur, ctx)
+
+	updated := 0
+	for cur.Next(ctx) {
+		var entity SpotEntity
+		err := cur.Decode(&entity)
+		if err != nil {
+			return updated, err
+		}
+
+		before, err := toDocument(entity.Spot)
+		if err != nil {
+			return updated, err
+		}
+		catchIds := make([]string, 0, len(entity.Spot.Catches))
+		for _, catch := range entity.Spot.Catches {
+			catchIds = append(catchIds, catch.Id)
+		}
+		if !update(&entity.Spot, entity.CreatedAt) {
+			continue
+		}
+		after, err := toDocument(entity.Spot)
+		if err != nil {
+			return updated, err
+		}
+
+		var set, unset bson.D
+		diffDocuments("spot", before, after, &set, &unset)
+		if len(set) == 0 && len(unset) == 0 {
+			continue
+		}
+
+		changes := bson.D{}
+		if len(set) > 0 {
+			changes = append(changes, bson.E{Key: "$set", Value: set})
+		}
+		if len(unset) > 0 {
+			changes = append(changes, bson.E{Key: "$unset", Value: unset})
+		}
+
+		result, err := r.db.Database.Collection(Spot).UpdateOne(ctx, catchGuard(entity.Id, catchIds, set, unset), changes)
+		if err != nil {
+			return updated, err
+		}
+		updated += int(result.ModifiedCount)
+	}
+
+	return updated, cur.Err()
+}
+
+func toDocument(value interface{}) (bson.M, error) {
+	raw, err := bson.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var document bson.M
+	err = bson.Unmarshal(raw, &document)
+	return document, err
+}
+
+// diffDocuments collects the paths of the fields which differ between the documents. Documents and arrays of
+// the same length are compared field by field, everything else is replaced as a whole.
+func diffDocuments(path string, before bson.M, after bson.M, set *bson.D, unset *bson.D) {
+
+	keys := make([]string, 0, len(after))
+	for key := range after {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		diffValues(path+"."+key, before[key], after[key], set, unset)
+	}
+
+	removed := make([]string, 0)
+	for key := range before {
+		if _, ok := after[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	sort.Strings(removed)
+	for _, key := range removed {
+		*unset = append(*unset, bson.E{Key: path + "." + key, Value: ""})
+	}
+}
+
+func diffValues(path string, before interface{}, after interface{}, set *bson.D, unset *bson.D) {
+
+	switch a := after.(type) {
+	case bson.M:
+		if b, ok := before.(bson.M); ok {
+			diffDocuments(path, b, a, set, unset)
+			return
+		}
+	case bson.A:
+		if b, ok := before.(bson.A); ok && len(a) == len(b) {
+			for i := range a {
+				diffValues(fmt.Sprintf("%s.%d", path, i), b[i], a[i], set, unset)
+			}
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(before, after) {
+		*set = append(*set, bson.E{Key: path, Value: after})
+	}
+}
+
+// catchGuard returns the filter of the spot which only matches while the catches changed by position are still
+// at their positions.
+func catchGuard(spotId string, catchIds []string, set bson.D, unset bson.D) bson.D {
+
+	filter := bson.D{{Key: "_id", Value: spotId}}
+	guarded := map[int]bool{}
+	for _, change := range append(append(bson.D{}, set...), unset...) {
+		var i int
+		if _, err := fmt.Sscanf(change.Key, "spot.catches.%d", &i); err != nil || guarded[i] || i >= len(catchIds) {
+			continue
+		}
+		guarded[i] = true
+		if id := catchIds[i]; len(id) > 0 {
+			filter = append(filter, bson.E{Key: fmt.Sprintf("spot.catches.%d.id", i), Value: id})
+		}
+	}
+	return filter
+}
+
+// SaveSpots stores several spots of a user at once.
+func (r Repo) SaveSpots(ctx context.Context, userId string, spots []common.Fish_spot) error {
+
+	createdAt := time.Now().UTC()
+	documents := make([]interface{}, 0, len(spots))
+	for _, spot := range spots {
+		documents = append(documents, SpotEntity{
+			Id:        uuid.New().String(),
+			UserId:    userId,
+			CreatedAt: createdAt,
+			Spot:      spot,
+		})
+	}
+
+	_, err := r.db.Database.Collection(Spot).InsertMany(ctx, documents)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AddCatch appends a catch to a spot of the user or returns common.ErrNotFound, if the user has no such spot.
+func (r Repo) AddCatch(ctx context.Context, userId string, spotId string, catch common.Catch) error {
+
+	result, err := r.db.Database.Collection(Spot).UpdateOne(ctx,
+		bson.D{{Key: "userId", Value: userId}, {Key: "spot.id", Value: spotId}},
+		bson.D{{Key: "$push", Value: bson.D{{Key: "spot.catches", Value: catch}}}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return common.ErrNotFound
+	}
+
+	return nil
+}
+
+// UpdateCatch replaces a catch of a spot of the user or returns common.ErrNotFound, if there is no such catch.
+func (r Repo) UpdateCatch(ctx context.Context, userId string, spotId string, catch common.Catch) error {
+
+	result, err := r.db.Database.Collection(Spot).UpdateOne(ctx,
+		bson.D{{Key: "userId", Value: userId}, {Key: "spot.id", Value: spotId}, {Key: "spot.catches.id", Value: catch.Id}},
+		bson.D{{Key: "$set", Value: bson.D{{Key: "spot.catches.$", Value: catch}}}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return common.ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteCatch removes a catch from a spot of the user or returns common.ErrNotFound, if there is no such catch.
+func (r Repo) DeleteCatch(ctx context.Context, userId string, spotId string, catchId string) error {
+
+	result, err := r.db.Database.Collection(Spot).UpdateOne(ctx,
+		bson.D{{Key: "userId", Value: userId}, {Key: "spot.id", Value: spotId}, {Key: "spot.catches.id", Value: catchId}},
+		bson.D{{Key: "$pull", Value: bson.D{{Key: "spot.catches", Value: bson.D{{Key: "id", Value: catchId}}}}}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return common.ErrNotFound
+	}
+
+	return nil
 }
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"fishfishes_backend/common"
+	"fishfishes_backend/common/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// searchIndex is the text index of the spots. A collection has at most one text index, so it already covers
+// the notes of spots and catches.
+const searchIndex string = "spot_text_idx"
+
+var searchFields = bson.D{
+	{Key: "spot.marker.title", Value: "text"},
+	{Key: "spot.catches.fish", Value: "text"},
+	{Key: "spot.catches.equipment.name", Value: "text"},
+	{Key: "spot.catches.equipment.bait", Value: "text"},
+	{Key: "spot.catches.equipment.leader", Value: "text"},
+	{Key: "spot.notes", Value: "text"},
+	{Key: "spot.catches.notes", Value: "text"},
+}
+
+// searchWeights ranks a match in the title above a match in a catch.
+var searchWeights = bson.D{
+	{Key: "spot.marker.title", Value: 5},
+	{Key: "spot.catches.fish", Value: 3},
+}
+
+// groupSearchIndex is the text index of the spots of groups.
+const groupSearchIndex string = "group_spot_text_idx"
+
+var groupSearchFields = bson.D{
+	{Key: "marker.title", Value: "text"},
+	{Key: "notes", Value: "text"},
+}
+
+var groupSearchWeights = bson.D{
+	{Key: "marker.title", Value: 5},
+}
+
+// SearchSpots runs a full-text search over the spots in the scope and returns at most limit spots, the most
+// relevant first.
+func (r Repo) SearchSpots(ctx context.Context, scope common.SearchScope, query string, limit int) ([]common.SearchHit, error) {
+
+	shared := bson.A{common.VisibilityFriends, common.VisibilityPublic}
+	filter := bson.D{
+		{Key: "$text", Value: bson.D{{Key: "$search", Value: query}}},
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "userId", Value: scope.UserId}},
+			bson.D{{Key: "userId", Value: bson.D{{Key: "$in", Value: nonNil(scope.FriendIds)}}}, {Key: "spot.visibility", Value: bson.D{{Key: "$in", Value: shared}}}},
+			bson.D{{Key: "userId", Value: bson.D{{Key: "$nin", Value: nonNil(scope.BlockedIds)}}}, {Key: "spot.visibility", Value: common.VisibilityPublic}},
+		}},
+	}
+	score := bson.D{{Key: "score", Value: bson.D{{Key: "$meta", Value: "textScore"}}}}
+	opts := options.Find().SetProjection(bson.D{{Key: "userId", Value: 1}, {Key: "spot", Value: 1}, score[0]}).SetSort(score).SetLimit(int64(limit))
+
+	cur, err := r.db.Database.Collection(Spot).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer mongo.CloseCursor(cur, ctx)
+
+	var hits []common.SearchHit
+	err = cur.All(ctx, &hits)
+	if err != nil {
+		return nil, err
+	}
+
+	return hits, nil
+}
+
+// SearchGroupSpots runs a full-text search over the spots of the given groups and returns at most limit
+// spots, the most relevant first.
+func (r Repo) SearchGroupSpots(ctx context.Context, groupIds []string, query string, limit int) ([]common.GroupSearchHit, error) {
+
+	if len(groupIds) == 0 {
+		return nil, nil
+	}
+
+	filter := bson.D{
+		{Key: "$text", Value: bson.D{{Key: "$search", Value: query}}},
+		{Key: "groupId", Value: bson.D{{Key: "$in", Value: groupIds}}},
+	}
+	score := bson.D{{Key: "score", Value: bson.D{{Key: "$meta", Value: "textScore"}}}}
+	opts := options.Find().SetProjection(score).SetSort(score).SetLimit(int64(limit))
+
+	cur, err := r.db.Database.Collection(GroupSpots).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer mongo.CloseCursor(cur, ctx)
+
+	var hits []common.GroupSearchHit
+	err = cur.All(ctx, &hits)
+	if err != nil {
+		return nil, err
+	}
+
+	return hits, nil
+}
+
+// nonNil returns an empty list for nil, which mongo rejects as operand of $in and $nin.
+func nonNil(ids []string) []string {
+	if ids == nil {
+		return []string{}
+	}
+	return ids
+}
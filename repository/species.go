@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"context"
+	"fishfishes_backend/common"
+	"fishfishes_backend/common/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+	mongoClient "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const SpeciesCollection string = "species"
+
+func species(id, commonName, scientificName, family string, waterTypes []string, aliases ...string) common.Species {
+	return common.Species{Id: id, CommonName: commonName, ScientificName: scientificName, WaterTypes: waterTypes, Family: family, Aliases: aliases}
+}
+
+var (
+	fresh         = []string{common.WaterTypeFresh}
+	salt          = []string{common.WaterTypeSalt}
+	freshBrackish = []string{common.WaterTypeFresh, common.WaterTypeBrackish}
+	saltBrackish  = []string{common.WaterTypeSalt, common.WaterTypeBrackish}
+	allWaters     = []string{common.WaterTypeFresh, common.WaterTypeSalt, common.WaterTypeBrackish}
+)
+
+// speciesSeed fills an empty catalog. The common names are the names of the former fish lists, which
+// stored catches refer to.
+var speciesSeed = []common.Species{
+	species("sword-fish", "Sword Fish", "Xiphias gladius", "Xiphiidae", salt, "Swordfish", "Broadbill"),
+	species("atlantic-cod", "Atlantic Cod", "Gadus morhua", "Gadidae", saltBrackish, "Cod"),
+	species("mackerel", "Mackerel", "Scomber scombrus", "Scombridae", salt, "Atlantic Mackerel"),
+	species("atlantic-salmon", "Atlantic Salmon", "Salmo salar", "Salmonidae", allWaters, "Salmon"),
+	species("tuna", "Tuna", "Thunnus thynnus", "Scombridae", salt, "Bluefin Tuna"),
+	species("shark", "Shark", "Selachimorpha", "", salt),
+	species("red-mullet", "Red Mullet", "Mullus barbatus", "Mullidae", salt),
+	species("barramundi", "Barramundi", "Lates calcarifer", "Latidae", allWaters, "Asian Sea Bass"),
+	species("mahi-mahi", "Mahi-Mahi", "Coryphaena hippurus", "Coryphaenidae", salt, "Dolphinfish", "Dorado"),
+	species("anchovy", "Anchovy", "Engraulis encrasicolus", "Engraulidae", salt),
+	species("haddock", "Haddock", "Melanogrammus aeglefinus", "Gadidae", salt),
+	species("red-seabream", "Red Seabream Fish", "Pagrus major", "Sparidae", salt, "Red Seabream"),
+	species("gold-line", "Gold Line Fish", "Sarpa salpa", "Sparidae", salt, "Goldline", "Salema"),
+	species("pollack", "Pollack", "Pollachius pollachius", "Gadidae", salt),
+	species("ocean-sunfish", "Ocean Sunfish", "Mola mola", "Molidae", salt),
+	species("northern-red-snapper", "Northern Red Snapper", "Lutjanus campechanus", "Lutjanidae", salt, "Red Snapper"),
+	species("bonito", "Bonito", "Sarda sarda", "Scombridae", salt, "Atlantic Bonito"),
+	species("bluefish", "Bluefish", "Pomatomus saltatrix", "Pomatomidae", saltBrackish),
+
+	species("trout", "Trout", "Salmo trutta", "Salmonidae", freshBrackish, "Brown Trout", "Sea Trout"),
+	species("common-carp", "Common Carp", "Cyprinus carpio", "Cyprinidae", fresh, "Carp"),
+	species("oscar-fish", "Oscar Fish", "Astronotus ocellatus", "Cichlidae", fresh, "Oscar"),
+	species("wels-catfish", "Wels Catfish", "Silurus glanis", "Siluridae", freshBrackish, "Wels", "Catfish"),
+	species("sauger", "Sauger Fish", "Sander canadensis", "Percidae", fresh, "Sauger"),
+	species("northern-pike", "The Northern Pike", "Esox lucius", "Esocidae", freshBrackish, "Northern Pike", "Pike"),
+	species("tench", "Tench", "Tinca tinca", "Tincidae", fresh),
+	species("european-eel", "European Eel", "Anguilla anguilla", "Anguillidae", allWaters, "Eel"),
+	species("cisco", "Cisco Fish", "Coregonus artedi", "Salmonidae", fresh, "Cisco", "Lake Herring"),
+	species("black-crappie", "Black Crappie", "Pomoxis nigromaculatus", "Centrarchidae", fresh),
+	species("brown-bullhead", "Brown Bullhead Catfish", "Ameiurus nebulosus", "Ictaluridae", fresh, "Brown Bullhead"),
+	species("golden-shiner", "Golden Shiner", "Notemigonus crysoleucas", "Leuciscidae", fresh),
+	species("largemouth-bass", "Largemouth Bass", "Micropterus salmoides", "Centrarchidae", fresh),
+	species("fathead-minnow", "Fathead Minnow", "Pimephales promelas", "Leuciscidae", fresh),
+	species("walleye", "Walleye Fish", "Sander vitreus", "Percidae", fresh, "Walleye"),
+	species("common-dace", "Common Dace", "Leuciscus leuciscus", "Leuciscidae", fresh, "Dace"),
+	species("european-chub", "European Chub", "Squalius cephalus", "Leuciscidae", fresh, "Chub"),
+}
+
+// SeedSpecies fills the species catalog, if it is empty, and returns the number of created species. Seeded
+// species of an existing catalog without localized names get them added.
+func (r Repo) SeedSpecies(ctx context.Context) (int, error) {
+
+	collection := r.db.Database.Collection(SpeciesCollection)
+
+	count, err := collection.CountDocuments(ctx, bson.D{})
+	if err != nil {
+		return 0, err
+	}
+
+	if count > 0 {
+		for id, names := range speciesNames {
+			_, err = collection.UpdateOne(ctx,
+				bson.D{{Key: "_id", Value: id}, {Key: "localizedNames", Value: bson.D{{Key: "$exists", Value: false}}}},
+				bson.D{{Key: "$set", Value: bson.D{{Key: "localizedNames", Value: names}}}})
+			if err != nil {
+				return 0, err
+			}
+		}
+		return 0, nil
+	}
+
+	documents := make([]interface{}, 0, len(speciesSeed))
+	for _, s := range speciesSeed {
+		s.LocalizedNames = speciesNames[s.Id]
+		documents = append(documents, s)
+	}
+
+	_, err = collection.InsertMany(ctx, documents)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(documents), nil
+}
+
+// GetSpecies returns the species of the catalog ordered by common name. A water type restricts the species
+// to those living in it.
+func (r Repo) GetSpecies(ctx context.Context, waterType string) ([]common.Species, error) {
+
+	filter := bson.D{}
+	if len(waterType) > 0 {
+		filter = bson.D{{Key: "waterTypes", Value: waterType}}
+	}
+
+	cur, err := r.db.Database.Collection(SpeciesCollection).Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "commonName", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	defer mongo.CloseCursor(cur, ctx)
+
+	list := []common.Species{}
+	err = cur.All(ctx, &list)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// GetSpeciesByID returns the species with the given id or common.ErrNotFound.
+func (r Repo) GetSpeciesByID(ctx context.Context, id string) (*common.Species, error) {
+
+	var s common.Species
+	err := r.db.Database.Collection(SpeciesCollection).FindOne(ctx, bson.D{{Key: "_id", Value: id}}).Decode(&s)
+	if err != nil {
+		if err == mongoClient.ErrNoDocuments {
+			return nil, common.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// CreateSpecies adds a species to the catalog or returns common.ErrConflict, if the id or common name is taken.
+func (r Repo) CreateSpecies(ctx context.Context, s common.Species) error {
+
+	_, err := r.db.Database.Collection(SpeciesCollection).InsertOne(ctx, s)
+	if mongoClient.IsDuplicateKeyError(err) {
+		return common.ErrConflict
+	}
+	return err
+}
+
+// UpdateSpecies replaces a species of the catalog. It returns common.ErrNotFound, if there is no species with
+// the id, and common.ErrConflict, if the common name is taken by another species.
+func (r Repo) UpdateSpecies(ctx context.Context, s common.Species) error {
+
+	result, err := r.db.Database.Collection(SpeciesCollection).ReplaceOne(ctx, bson.D{{Key: "_id", Value: s.Id}}, s)
+	if err != nil {
+		if mongoClient.IsDuplicateKeyError(err) {
+			return common.ErrConflict
+		}
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return common.ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteSpecies removes a species from the catalog or returns common.ErrNotFound.
+func (r Repo) DeleteSpecies(ctx context.Context, id string) error {
+
+	result, err := r.db.Database.Collection(SpeciesCollection).DeleteOne(ctx, bson.D{{Key: "_id", Value: id}})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return common.ErrNotFound
+	}
+
+	return nil
+}
+
+type catchCount struct {
+	Fish    string `bson:"_id"`
+	Catches int    `bson:"catches"`
+}
+
+// GetCatchCounts returns the number of catches of the user per species name.
+func (r Repo) GetCatchCounts(ctx context.Context, userId string) (map[string]int, error) {
+
+	pipeline := mongoClient.Pipeline{
+		{{Key: "$match", Value: bson.D{{Key: "userId", Value: userId}}}},
+		{{Key: "$unwind", Value: "$spot.catches"}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$spot.catches.fish"},
+			{Key: "catches", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}},
+	}
+
+	cur, err := r.db.Database.Collection(Spot).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	defer mongo.CloseCursor(cur, ctx)
+
+	var counts []catchCount
+	err = cur.All(ctx, &counts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]int{}
+	for _, count := range counts {
+		result[count.Fish] = count.Catches
+	}
+
+	return result, nil
+}
@@ -0,0 +1,40 @@
+package repository
+
+// speciesNames are the localized names of the seeded species.
+var speciesNames = map[string]map[string]string{
+	"sword-fish":           {"de": "Schwertfisch", "fr": "Espadon", "es": "Pez espada"},
+	"atlantic-cod":         {"de": "Kabeljau", "fr": "Cabillaud", "es": "Bacalao"},
+	"mackerel":             {"de": "Makrele", "fr": "Maquereau", "es": "Caballa"},
+	"atlantic-salmon":      {"de": "Lachs", "fr": "Saumon atlantique", "es": "Salmón atlántico"},
+	"tuna":                 {"de": "Thunfisch", "fr": "Thon rouge", "es": "Atún rojo"},
+	"shark":                {"de": "Hai", "fr": "Requin", "es": "Tiburón"},
+	"red-mullet":           {"de": "Rote Meerbarbe", "fr": "Rouget de vase", "es": "Salmonete de fango"},
+	"barramundi":           {"de": "Barramundi", "fr": "Barramundi", "es": "Barramundi"},
+	"mahi-mahi":            {"de": "Goldmakrele", "fr": "Coryphène", "es": "Dorado"},
+	"anchovy":              {"de": "Sardelle", "fr": "Anchois", "es": "Boquerón"},
+	"haddock":              {"de": "Schellfisch", "fr": "Églefin", "es": "Eglefino"},
+	"red-seabream":         {"de": "Rote Meerbrasse", "fr": "Dorade japonaise", "es": "Pargo japonés"},
+	"gold-line":            {"de": "Goldstrieme", "fr": "Saupe", "es": "Salema"},
+	"pollack":              {"de": "Pollack", "fr": "Lieu jaune", "es": "Abadejo"},
+	"ocean-sunfish":        {"de": "Mondfisch", "fr": "Poisson-lune", "es": "Pez luna"},
+	"northern-red-snapper": {"de": "Roter Schnapper", "fr": "Vivaneau rouge", "es": "Pargo del Golfo"},
+	"bonito":               {"de": "Pelamide", "fr": "Bonite à dos rayé", "es": "Bonito"},
+	"bluefish":             {"de": "Blaubarsch", "fr": "Tassergal", "es": "Anjova"},
+	"trout":                {"de": "Forelle", "fr": "Truite", "es": "Trucha"},
+	"common-carp":          {"de": "Karpfen", "fr": "Carpe commune", "es": "Carpa común"},
+	"oscar-fish":           {"de": "Pfauenaugenbuntbarsch", "fr": "Oscar", "es": "Óscar"},
+	"wels-catfish":         {"de": "Wels", "fr": "Silure glane", "es": "Siluro"},
+	"sauger":               {"de": "Kanadischer Zander", "fr": "Doré noir", "es": "Sauger"},
+	"northern-pike":        {"de": "Hecht", "fr": "Brochet", "es": "Lucio"},
+	"tench":                {"de": "Schleie", "fr": "Tanche", "es": "Tenca"},
+	"european-eel":         {"de": "Aal", "fr": "Anguille", "es": "Anguila"},
+	"cisco":                {"de": "Amerikanische Maräne", "fr": "Cisco de lac", "es": "Cisco"},
+	"black-crappie":        {"de": "Schwarzer Crappie", "fr": "Marigane noire", "es": "Mojarra negra"},
+	"brown-bullhead":       {"de": "Brauner Zwergwels", "fr": "Barbotte brune", "es": "Bagre torito"},
+	"golden-shiner":        {"de": "Goldbrassen-Elritze", "fr": "Méné jaune", "es": "Carpita dorada"},
+	"largemouth-bass":      {"de": "Forellenbarsch", "fr": "Achigan à grande bouche", "es": "Lobina negra"},
+	"fathead-minnow":       {"de": "Fettköpfige Elritze", "fr": "Tête-de-boule", "es": "Carpita cabezona"},
+	"walleye":              {"de": "Glasaugenbarsch", "fr": "Doré jaune", "es": "Lucioperca americana"},
+	"common-dace":          {"de": "Hasel", "fr": "Vandoise", "es": "Leucisco"},
+	"european-chub":        {"de": "Döbel", "fr": "Chevesne", "es": "Cacho"},
+}
@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"fishfishes_backend/common"
+	"fishfishes_backend/common/mongo"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	mongoClient "go.mongodb.org/mongo-driver/mongo"
+)
+
+// depthRanges are the upper boundaries (exclusive) of the depth buckets in the statistics.
+var depthRanges = []int{2, 5, 10, 20, 50}
+
+type statisticsFacets struct {
+	Totals        []common.StatisticsTotals `bson:"totals"`
+	BySpecies     []common.StatisticsGroup  `bson:"bySpecies"`
+	ByTime        []common.StatisticsGroup  `bson:"byTime"`
+	ByBait        []common.StatisticsGroup  `bson:"byBait"`
+	ByDepth       []common.StatisticsGroup  `bson:"byDepth"`
+	ByMoonPhase   []common.StatisticsGroup  `bson:"byMoonPhase"`
+	PersonalBests []common.PersonalBest     `bson:"personalBests"`
+}
+
+func (r Repo) GetCatchStatistics(ctx context.Context, userId string, filter common.StatisticsFilter) (*common.CatchStatistics, error) {
+
+	pipeline := mongoClient.Pipeline{
+		{{Key: "$match", Value: spotMatch(userId, filter)}},
+		{{Key: "$unwind", Value: "$spot.catches"}},
+	}
+
+	if match := catchTimeMatch(filter); match != nil {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: match}})
+	}
+
+	species, err := r.fishListByWaterType(ctx, filter.WaterType)
+	if err != nil {
+		return nil, err
+	}
+	if species != nil {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.D{
+			{Key: "spot.catches.fish", Value: bson.D{{Key: "$in", Value: species}}},
+		}}})
+	}
+
+	pipeline = append(pipeline, bson.D{{Key: "$facet", Value: bson.D{
+		{Key: "totals", Value: bson.A{
+			bson.D{{Key: "$group", Value: catchGroup(nil, false)}},
+		}},
+		{Key: "bySpecies", Value: groupStage("$spot.catches.fish")},
+		{Key: "byTime", Value: groupStage("$spot.catches.time")},
+		{Key: "byBait", Value: groupStage("$spot.catches.equipment.bait")},
+		{Key: "byDepth", Value: groupStage(depthBucket("$spot.catches.deep"))},
+		{Key: "byMoonPhase", Value: groupStage("$spot.catches.moonphase.name")},
+		{Key: "personalBests", Value: bson.A{
+			bson.D{{Key: "$sort", Value: bson.D{{Key: "spot.catches.size", Value: -1}}}},
+			bson.D{{Key: "$group", Value: bson.D{
+				{Key: "_id", Value: "$spot.catches.fish"},
+				{Key: "size", Value: bson.D{{Key: "$first", Value: "$spot.catches.size"}}},
+				{Key: "number", Value: bson.D{{Key: "$first", Value: "$spot.catches.number"}}},
+				{Key: "spotId", Value: bson.D{{Key: "$first", Value: "$spot.id"}}},
+				{Key: "spotTitle", Value: bson.D{{Key: "$first", Value: "$spot.marker.title"}}},
+				{Key: "equipment", Value: bson.D{{Key: "$first", Value: "$spot.catches.equipment"}}},
+			}}},
+			bson.D{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+		}},
+	}}})
+
+	cur, err := r.db.Database.Collection(Spot).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	defer mongo.CloseCursor(cur, ctx)
+
+	var facets statisticsFacets
+	if cur.Next(ctx) {
+		err = cur.Decode(&facets)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	statistics := common.CatchStatistics{
+		BySpecies:     facets.BySpecies,
+		ByTime:        facets.ByTime,
+		ByBait:        facets.ByBait,
+		ByDepth:       facets.ByDepth,
+		ByMoonPhase:   facets.ByMoonPhase,
+		PersonalBests: facets.PersonalBests,
+	}
+	if len(facets.Totals) > 0 {
+		statistics.Totals = facets.Totals[0]
+	}
+
+	return &statistics, nil
+}
+
+// spotMatch builds the filter on the spot documents of a user for the spot of the given statistics filter.
+func spotMatch(userId string, filter common.StatisticsFilter) bson.D {
+
+	match := bson.D{{Key: "userId", Value: userId}}
+
+	if len(filter.SpotId) > 0 {
+		match = append(match, bson.E{Key: "spot.id", Value: filter.SpotId})
+	}
+
+	return match
+}
+
+// catchTimeMatch builds the filter on unwound catches for the date range of the statistics filter.
+// Catches without a timestamp are filtered by the time their spot was saved.
+func catchTimeMatch(filter common.StatisticsFilter) bson.D {
+
+	caughtAt := bson.D{{Key: "$ifNull", Value: bson.A{"$spot.catches.caughtat", "$createdAt"}}}
+
+	var conditions bson.A
+	if filter.From != nil {
+		conditions = append(conditions, bson.D{{Key: "$gte", Value: bson.A{caughtAt, *filter.From}}})
+	}
+	if filter.To != nil {
+		conditions = append(conditions, bson.D{{Key: "$lte", Value: bson.A{caughtAt, *filter.To}}})
+	}
+	if len(conditions) == 0 {
+		return nil
+	}
+
+	return bson.D{{Key: "$expr", Value: bson.D{{Key: "$and", Value: conditions}}}}
+}
+
+// catchGroup returns the accumulators of a statistics group keyed by the given expression.
+func catchGroup(key interface{}, withMax bool) bson.D {
+
+	group := bson.D{
+		{Key: "_id", Value: key},
+		{Key: "catches", Value: bson.D{{Key: "$sum", Value: 1}}},
+		{Key: "fish", Value: bson.D{{Key: "$sum", Value: "$spot.catches.number"}}},
+		{Key: "avgSize", Value: bson.D{{Key: "$avg", Value: "$spot.catches.size"}}},
+		{Key: "avgNumber", Value: bson.D{{Key: "$avg", Value: "$spot.catches.number"}}},
+		{Key: "avgDeep", Value: bson.D{{Key: "$avg", Value: "$spot.catches.deep"}}},
+	}
+	if withMax {
+		group = append(group, bson.E{Key: "maxSize", Value: bson.D{{Key: "$max", Value: "$spot.catches.size"}}})
+	}
+
+	return group
+}
+
+func groupStage(key interface{}) bson.A {
+	return bson.A{
+		bson.D{{Key: "$group", Value: catchGroup(key, true)}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "fish", Value: -1}, {Key: "_id", Value: 1}}}},
+	}
+}
+
+// depthBucket returns an expression mapping the depth field to a labeled range like "2-5".
+func depthBucket(field string) bson.D {
+
+	var branches bson.A
+	lower := 0
+	for _, upper := range depthRanges {
+		branches = append(branches, bson.D{
+			{Key: "case", Value: bson.D{{Key: "$lt", Value: bson.A{field, upper}}}},
+			{Key: "then", Value: fmt.Sprintf("%d-%d", lower, upper)},
+		})
+		lower = upper
+	}
+
+	return bson.D{{Key: "$switch", Value: bson.D{
+		{Key: "branches", Value: branches},
+		{Key: "default", Value: fmt.Sprintf("%d+", lower)},
+	}}}
+}
+
+// fishListByWaterType returns the names and aliases of the species of the given water type or nil, if no
+// water type is given.
+func (r Repo) fishListByWaterType(ctx context.Context, waterType string) ([]string, error) {
+	if len(waterType) == 0 {
+		return nil, nil
+	}
+
+	list, err := r.GetSpecies(ctx, waterType)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, s := range list {
+		names = append(names, s.Names()...)
+	}
+	return names, nil
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"fishfishes_backend/common"
+	"fishfishes_backend/common/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const Tackle string = "tackle"
+
+type TackleEntity struct {
+	UserId string            `bson:"userId"`
+	Item   common.TackleItem `bson:",inline"`
+}
+
+// GetTackle returns the tackle box of the user ordered by type and name, restricted to the items of the
+// given type, if one is given.
+func (r Repo) GetTackle(ctx context.Context, userId string, itemType string) ([]common.TackleItem, error) {
+
+	filter := bson.D{{Key: "userId", Value: userId}}
+	if len(itemType) > 0 {
+		filter = append(filter, bson.E{Key: "type", Value: itemType})
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "type", Value: 1}, {Key: "name", Value: 1}})
+	cur, err := r.db.Database.Collection(Tackle).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer mongo.CloseCursor(cur, ctx)
+
+	var entities []TackleEntity
+	err = cur.All(ctx, &entities)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []common.TackleItem{}
+	for _, entity := range entities {
+		result = append(result, entity.Item)
+	}
+
+	return result, nil
+}
+
+func (r Repo) CreateTackle(ctx context.Context, userId string, item common.TackleItem) error {
+
+	_, err := r.db.Database.Collection(Tackle).InsertOne(ctx, TackleEntity{UserId: userId, Item: item})
+	return err
+}
+
+// UpdateTackle replaces an item of the tackle box of the user or returns common.ErrNotFound.
+func (r Repo) UpdateTackle(ctx context.Context, userId string, item common.TackleItem) error {
+
+	filter := bson.D{{Key: "_id", Value: item.Id}, {Key: "userId", Value: userId}}
+	result, err := r.db.Database.Collection(Tackle).ReplaceOne(ctx, filter, TackleEntity{UserId: userId, Item: item})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return common.ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteTackle removes an item from the tackle box of the user or returns common.ErrNotFound. Catches
+// referencing the item keep the text it was linked with.
+func (r Repo) DeleteTackle(ctx context.Context, userId string, itemId string) error {
+
+	result, err := r.db.Database.Collection(Tackle).DeleteOne(ctx, bson.D{{Key: "_id", Value: itemId}, {Key: "userId", Value: userId}})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return common.ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"fishfishes_backend/common"
+	"fishfishes_backend/common/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+	mongoClient "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+const Trips string = "trips"
+
+type TripEntity struct {
+	UserId string      `bson:"userId"`
+	Trip   common.Trip `bson:",inline"`
+}
+
+// GetTrips returns the trips of the user, the latest first. from and to optionally restrict the start of
+// the trips.
+func (r Repo) GetTrips(ctx context.Context, userId string, from *time.Time, to *time.Time) ([]common.Trip, error) {
+
+	filter := bson.D{{Key: "userId", Value: userId}}
+	start := bson.D{}
+	if from != nil {
+		start = append(start, bson.E{Key: "$gte", Value: *from})
+	}
+	if to != nil {
+		start = append(start, bson.E{Key: "$lte", Value: *to})
+	}
+	if len(start) > 0 {
+		filter = append(filter, bson.E{Key: "start", Value: start})
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "start", Value: -1}})
+	cur, err := r.db.Database.Collection(Trips).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer mongo.CloseCursor(cur, ctx)
+
+	var entities []TripEntity
+	err = cur.All(ctx, &entities)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []common.Trip{}
+	for _, entity := range entities {
+		result = append(result, entity.Trip)
+	}
+
+	return result, nil
+}
+
+// GetTrip returns the trip of the user with the given id or common.ErrNotFound.
+func (r Repo) GetTrip(ctx context.Context, userId string, tripId string) (*common.Trip, error) {
+
+	var entity TripEntity
+	err := r.db.Database.Collection(Trips).FindOne(ctx, bson.D{{Key: "_id", Value: tripId}, {Key: "userId", Value: userId}}).Decode(&entity)
+	if err != nil {
+		if err == mongoClient.ErrNoDocuments {
+			return nil, common.ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &entity.Trip, nil
+}
+
+func (r Repo) CreateTrip(ctx context.Context, userId string, trip common.Trip) error {
+
+	_, err := r.db.Database.Collection(Trips).InsertOne(ctx, TripEntity{UserId: userId, Trip: trip})
+	return err
+}
+
+// UpdateTrip replaces a trip of the user or returns common.ErrNotFound.
+func (r Repo) UpdateTrip(ctx context.Context, userId string, trip common.Trip) error {
+
+	filter := bson.D{{Key: "_id", Value: trip.Id}, {Key: "userId", Value: userId}}
+	result, err := r.db.Database.Collection(Trips).ReplaceOne(ctx, filter, TripEntity{UserId: userId, Trip: trip})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return common.ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteTrip removes a trip of the user or returns common.ErrNotFound. The catches of the trip are kept.
+func (r Repo) DeleteTrip(ctx context.Context, userId string, tripId string) error {
+
+	result, err := r.db.Database.Collection(Trips).DeleteOne(ctx, bson.D{{Key: "_id", Value: tripId}, {Key: "userId", Value: userId}})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return common.ErrNotFound
+	}
+
+	return nil
+}
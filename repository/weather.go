@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"fishfishes_backend/common"
+	"fishfishes_backend/common/mongo"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	mongoClient "go.mongodb.org/mongo-driver/mongo"
+)
+
+// GetCatchesWithoutWeather returns references to stored catches which have no weather conditions yet.
+func (r Repo) GetCatchesWithoutWeather(ctx context.Context, limit int) ([]common.CatchReference, error) {
+
+	pipeline := mongoClient.Pipeline{
+		{{Key: "$match", Value: bson.D{{Key: "spot.catches.weather", Value: nil}}}},
+		{{Key: "$unwind", Value: bson.D{
+			{Key: "path", Value: "$spot.catches"},
+			{Key: "includeArrayIndex", Value: "index"},
+		}}},
+		{{Key: "$match", Value: bson.D{{Key: "spot.catches.weather", Value: nil}}}},
+		{{Key: "$limit", Value: limit}},
+		{{Key: "$project", Value: bson.D{
+			{Key: "index", Value: 1},
+			{Key: "catchId", Value: "$spot.catches.id"},
+			{Key: "coordinates", Value: "$spot.marker.coordinates"},
+			{Key: "at", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$spot.catches.caughtat", "$createdAt"}}}},
+		}}},
+	}
+
+	cur, err := r.db.Database.Collection(Spot).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	defer mongo.CloseCursor(cur, ctx)
+
+	var refs []common.CatchReference
+	err = cur.All(ctx, &refs)
+	if err != nil {
+		return nil, err
+	}
+
+	return refs, nil
+}
+
+// SetCatchWeather stores the weather conditions of the referenced catch, if it still has none. A catch removed
+// or updated since it was read is left as it is.
+func (r Repo) SetCatchWeather(ctx context.Context, ref common.CatchReference, weather common.Weather) error {
+
+	filter := bson.D{{Key: "_id", Value: ref.SpotEntityId}}
+	field := "spot.catches.$.weather"
+	if len(ref.CatchId) > 0 {
+		filter = append(filter, bson.E{Key: "spot.catches", Value: bson.D{{Key: "$elemMatch", Value: bson.D{
+			{Key: "id", Value: ref.CatchId},
+			{Key: "weather", Value: nil},
+		}}}})
+	} else {
+		// catches stored before they had ids are only found by their index
+		field = fmt.Sprintf("spot.catches.%d.weather", ref.Index)
+		filter = append(filter,
+			bson.E{Key: fmt.Sprintf("spot.catches.%d", ref.Index), Value: bson.D{{Key: "$exists", Value: true}}},
+			bson.E{Key: field, Value: nil})
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: field, Value: weather}}}}
+
+	_, err := r.db.Database.Collection(Spot).UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package search
+
+import (
+	"fishfishes_backend/common"
+	"sort"
+	"strings"
+)
+
+// The scores of the kinds of matches of the autocompletion, fuzzy matches lose fuzzyPenalty per edit.
+const (
+	exactScore     float64 = 1.0
+	prefixScore    float64 = 0.9
+	wordScore      float64 = 0.8
+	fuzzyScore     float64 = 0.7
+	fuzzyPenalty   float64 = 0.1
+	frequencyBonus float64 = 0.3
+)
+
+// Suggest returns at most limit species with a name, alias, localized name or scientific name matching the
+// typed input. A name matches if it equals the input, starts with it, has a word starting with it or is within
+// a few typing errors of it; ties and close matches are decided by how often the user caught the species,
+// catches maps species ids to that number. Names are returned in the first of the locales.
+func Suggest(input string, species []common.Species, catches map[string]int, locales []string, limit int) []common.SpeciesSuggestion {
+
+	query := common.NormalizeName(input)
+	if len(query) == 0 {
+		return []common.SpeciesSuggestion{}
+	}
+
+	maxCatches := 0
+	for _, n := range catches {
+		if n > maxCatches {
+			maxCatches = n
+		}
+	}
+
+	suggestions := []common.SpeciesSuggestion{}
+	for _, s := range species {
+		best, matched := 0.0, ""
+		for _, name := range append(s.Names(), s.ScientificName) {
+			if score := matchScore(query, common.NormalizeName(name)); score > best {
+				best, matched = score, name
+			}
+		}
+		if best == 0 {
+			continue
+		}
+		if maxCatches > 0 {
+			best += frequencyBonus * float64(catches[s.Id]) / float64(maxCatches)
+		}
+		suggestions = append(suggestions, common.SpeciesSuggestion{
+			Id:      s.Id,
+			Name:    s.LocalizedName(locales),
+			Matched: matched,
+			Score:   best,
+			Catches: catches[s.Id],
+		})
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+		return suggestions[i].Name < suggestions[j].Name
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
+
+// matchScore rates how well the normalized name matches the normalized query, 0 if it does not match.
+func matchScore(query, name string) float64 {
+	switch {
+	case len(name) == 0:
+		return 0
+	case name == query:
+		return exactScore
+	case strings.HasPrefix(name, query):
+		return prefixScore
+	case strings.Contains(" "+name, " "+query):
+		return wordScore
+	}
+
+	allowed := maxEdits(query)
+	if allowed == 0 {
+		return 0
+	}
+
+	// compare the query with the beginnings of the name and of each of its words, one character more or
+	// less to account for a missing or doubled letter
+	q := []rune(query)
+	n := []rune(name)
+	best := allowed + 1
+	for start := 0; start < len(n); start++ {
+		if start > 0 && n[start-1] != ' ' {
+			continue
+		}
+		for length := len(q) - 1; length <= len(q)+1; length++ {
+			if length < 1 || start+length > len(n) {
+				continue
+			}
+			if d := editDistance(q, n[start:start+length]); d < best {
+				best = d
+			}
+		}
+	}
+	if best > allowed {
+		return 0
+	}
+	return fuzzyScore - fuzzyPenalty*float64(best-1)
+}
+
+// maxEdits returns the number of typing errors tolerated in the query, none for very short input.
+func maxEdits(query string) int {
+	switch n := len([]rune(query)); {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	}
+	return 2
+}
+
+// editDistance returns the optimal string alignment distance of a and b: the number of inserted, deleted,
+// replaced and swapped adjacent characters needed to turn a into b.
+func editDistance(a, b []rune) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = smallest(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = smallest(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
+
+func smallest(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
@@ -0,0 +1,56 @@
+package search
+
+import (
+	"fishfishes_backend/common"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var catalog = []common.Species{
+	{Id: "northern-pike", CommonName: "The Northern Pike", ScientificName: "Esox lucius", Aliases: []string{"Northern Pike", "Pike"}, LocalizedNames: map[string]string{"de": "Hecht"}},
+	{Id: "pikeperch", CommonName: "Pikeperch", ScientificName: "Sander lucioperca", LocalizedNames: map[string]string{"de": "Zander"}},
+	{Id: "perch", CommonName: "Perch", ScientificName: "Perca fluviatilis", LocalizedNames: map[string]string{"de": "Flussbarsch"}},
+	{Id: "tench", CommonName: "Tench", ScientificName: "Tinca tinca", LocalizedNames: map[string]string{"de": "Schleie"}},
+}
+
+func ids(suggestions []common.SpeciesSuggestion) []string {
+	result := []string{}
+	for _, s := range suggestions {
+		result = append(result, s.Id)
+	}
+	return result
+}
+
+func TestSuggest(t *testing.T) {
+	assert.Equal(t, []string{"northern-pike", "pikeperch"}, ids(Suggest("pike", catalog, nil, nil, 10)))
+	assert.Equal(t, []string{"northern-pike"}, ids(Suggest("Nothern Pike", catalog, nil, nil, 10)))
+	assert.Equal(t, []string{"northern-pike"}, ids(Suggest("hech", catalog, nil, nil, 10)))
+	assert.Equal(t, []string{"pikeperch"}, ids(Suggest("lucioperka", catalog, nil, nil, 10)))
+	assert.Equal(t, []string{"tench"}, ids(Suggest("schliee", catalog, nil, nil, 10)))
+	assert.Empty(t, Suggest("xyz", catalog, nil, nil, 10))
+	assert.Empty(t, Suggest(" ", catalog, nil, nil, 10))
+
+	suggestions := Suggest("hecht", catalog, nil, []string{"de"}, 10)
+	assert.Equal(t, "Hecht", suggestions[0].Name)
+	assert.Equal(t, "Hecht", suggestions[0].Matched)
+	assert.Equal(t, exactScore, suggestions[0].Score)
+}
+
+func TestSuggestRanksByCatches(t *testing.T) {
+	// both match as prefix, ties are ordered by name
+	assert.Equal(t, []string{"pikeperch", "northern-pike"}, ids(Suggest("pik", catalog, nil, nil, 10)))
+	catches := map[string]int{"northern-pike": 12, "pikeperch": 1}
+	suggestions := Suggest("pik", catalog, catches, nil, 10)
+	assert.Equal(t, []string{"northern-pike", "pikeperch"}, ids(suggestions))
+	assert.Equal(t, 12, suggestions[0].Catches)
+
+	assert.Len(t, Suggest("p", catalog, catches, nil, 1), 1)
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance([]rune("pike"), []rune("pike")))
+	assert.Equal(t, 1, editDistance([]rune("pkie"), []rune("pike")))
+	assert.Equal(t, 1, editDistance([]rune("nothern"), []rune("northern")))
+	assert.Equal(t, 2, editDistance([]rune("barsh"), []rune("barsch1")))
+	assert.Equal(t, 3, editDistance([]rune(""), []rune("abc")))
+}
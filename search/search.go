@@ -0,0 +1,176 @@
+// Package search highlights the terms of a full-text search in the fields of spots.
+package search
+
+import (
+	"fishfishes_backend/common"
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+)
+
+// MaxSnippetLength is the number of characters of a field shown around the first match.
+const MaxSnippetLength int = 120
+
+// minStemLength is the length a word must have to match a longer search term, e.g. "pike" for "pikes".
+const minStemLength int = 4
+
+// Terms returns the lower case words of a query in the syntax of the mongo text search. Quotes of phrases
+// are dropped and negated words are ignored.
+func Terms(query string) []string {
+	var terms []string
+	for _, field := range strings.Fields(query) {
+		if strings.HasPrefix(field, "-") {
+			continue
+		}
+		terms = append(terms, words(field)...)
+	}
+	return terms
+}
+
+// Matches returns a snippet for every field of the spot which contains one of the terms.
+func Matches(spot common.Fish_spot, terms []string) []common.SearchMatch {
+	var matches []common.SearchMatch
+	add := func(field string, text string) {
+		if snippet, ok := Highlight(text, terms); ok {
+			matches = append(matches, common.SearchMatch{Field: field, Snippet: snippet})
+		}
+	}
+
+	add("marker.title", spot.Marker.Title)
+	for i, catch := range spot.Catches {
+		add(fmt.Sprintf("catches[%d].id", i), catch.Fish)
+		add(fmt.Sprintf("catches[%d].equipment.name", i), catch.Equipment.Name)
+		add(fmt.Sprintf("catches[%d].equipment.bait", i), catch.Equipment.Bait)
+		add(fmt.Sprintf("catches[%d].equipment.leader", i), catch.Equipment.Leader)
+	}
+	return matches
+}
+
+// GroupMatches returns a snippet for every field of the spot of a group matching one of the terms.
+func GroupMatches(spot common.GroupSpot, terms []string) []common.SearchMatch {
+	matches := Matches(common.Fish_spot{Marker: spot.Marker}, terms)
+	if snippet, ok := Highlight(spot.Notes, terms); ok {
+		matches = append(matches, common.SearchMatch{Field: "notes", Snippet: snippet})
+	}
+	return matches
+}
+
+// Highlight wraps the words of the text matching one of the terms in <em> tags and escapes the rest.
+// Long texts are cut to MaxSnippetLength characters around the first match. It returns false, if no word matches.
+func Highlight(text string, terms []string) (string, bool) {
+
+	runes := []rune(text)
+	type span struct{ start, end int }
+	var spans []span
+
+	start := -1
+	for i := 0; i <= len(runes); i++ {
+		if i < len(runes) && isWordRune(runes[i]) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 && matchesAny(strings.ToLower(string(runes[start:i])), terms) {
+			spans = append(spans, span{start, i})
+		}
+		start = -1
+	}
+	if len(spans) == 0 {
+		return "", false
+	}
+
+	from, to := 0, len(runes)
+	if len(runes) > MaxSnippetLength {
+		from = spans[0].start - MaxSnippetLength/4
+		if from < 0 {
+			from = 0
+		}
+		to = from + MaxSnippetLength
+		if to > len(runes) {
+			to = len(runes)
+			from = to - MaxSnippetLength
+		}
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	pos := from
+	for _, s := range spans {
+		if s.start < from || s.end > to {
+			continue
+		}
+		b.WriteString(html.EscapeString(string(runes[pos:s.start])))
+		b.WriteString("<em>")
+		b.WriteString(html.EscapeString(string(runes[s.start:s.end])))
+		b.WriteString("</em>")
+		pos = s.end
+	}
+	b.WriteString(html.EscapeString(string(runes[pos:to])))
+	if to < len(runes) {
+		b.WriteString("…")
+	}
+	return b.String(), true
+}
+
+// matchesAny approximates the stemming of the mongo text search: a word matches a term it starts with,
+// or a term starting with the word, if the word is not too short.
+func matchesAny(word string, terms []string) bool {
+	for _, term := range terms {
+		if strings.HasPrefix(word, term) || (len([]rune(word)) >= minStemLength && strings.HasPrefix(term, word)) {
+			return true
+		}
+	}
+	return false
+}
+
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !isWordRune(r) })
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// stopWords are left out when the names of a species are added to a search.
+var stopWords = map[string]bool{"the": true, "of": true, "der": true, "die": true, "das": true, "de": true, "du": true, "la": true, "le": true, "el": true}
+
+// ExpandSpecies returns the words of all names of the species which have a name containing one of the terms
+// as a word, so that a search for "hecht" also finds catches of "The Northern Pike".
+func ExpandSpecies(terms []string, species []common.Species) []string {
+	wanted := map[string]bool{}
+	for _, term := range terms {
+		wanted[term] = true
+	}
+
+	seen := map[string]bool{}
+	var expanded []string
+	for _, s := range species {
+		if !anyWord(s.Names(), wanted) {
+			continue
+		}
+		for _, name := range s.Names() {
+			for _, word := range words(name) {
+				if !wanted[word] && !seen[word] && !stopWords[word] {
+					seen[word] = true
+					expanded = append(expanded, word)
+				}
+			}
+		}
+	}
+	return expanded
+}
+
+func anyWord(names []string, wanted map[string]bool) bool {
+	for _, name := range names {
+		for _, word := range words(name) {
+			if wanted[word] {
+				return true
+			}
+		}
+	}
+	return false
+}
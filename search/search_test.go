@@ -0,0 +1,67 @@
+package search
+
+import (
+	"fishfishes_backend/common"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestTerms(t *testing.T) {
+	assert.Equal(t, []string{"pike", "red", "worm"}, Terms(`Pike "red worm" -carp`))
+	assert.Empty(t, Terms("  "))
+}
+
+func TestHighlight(t *testing.T) {
+	snippet, ok := Highlight("Pike & Perch at the Lake", []string{"pike", "lakes"})
+	assert.True(t, ok)
+	assert.Equal(t, "<em>Pike</em> &amp; Perch at the <em>Lake</em>", snippet)
+
+	_, ok = Highlight("Common Carp", []string{"pike"})
+	assert.False(t, ok)
+
+	// short words do not match longer terms
+	_, ok = Highlight("at", []string{"atlantic"})
+	assert.False(t, ok)
+}
+
+func TestHighlightCutsLongTexts(t *testing.T) {
+	text := strings.Repeat("water ", 50) + "spoon " + strings.Repeat("water ", 50)
+	snippet, ok := Highlight(text, []string{"spoon"})
+	assert.True(t, ok)
+	assert.True(t, strings.HasPrefix(snippet, "…"))
+	assert.True(t, strings.HasSuffix(snippet, "…"))
+	assert.Contains(t, snippet, "<em>spoon</em>")
+	assert.Equal(t, MaxSnippetLength+2, len([]rune(strings.NewReplacer("<em>", "", "</em>", "").Replace(snippet))))
+}
+
+func TestMatches(t *testing.T) {
+	spot := common.Fish_spot{
+		Marker: common.Marker{Title: "Old harbour"},
+		Catches: []common.Catch{
+			{Fish: "Atlantic Cod", Equipment: common.Equipment{Name: "Pilker", Bait: "Red worm"}},
+			{Fish: "Mackerel", Equipment: common.Equipment{Bait: "Feather", Leader: "Red mono"}},
+		},
+	}
+	assert.Equal(t, []common.SearchMatch{
+		{Field: "catches[0].equipment.bait", Snippet: "<em>Red</em> worm"},
+		{Field: "catches[1].equipment.leader", Snippet: "<em>Red</em> mono"},
+	}, Matches(spot, Terms("red")))
+}
+
+func TestGroupMatches(t *testing.T) {
+	spot := common.GroupSpot{Marker: common.Marker{Title: "Club lake"}, Notes: "Night fishing from the club jetty"}
+	assert.Equal(t, []common.SearchMatch{
+		{Field: "marker.title", Snippet: "<em>Club</em> lake"},
+		{Field: "notes", Snippet: "Night fishing from the <em>club</em> jetty"},
+	}, GroupMatches(spot, Terms("club")))
+}
+
+func TestExpandSpecies(t *testing.T) {
+	species := []common.Species{
+		{CommonName: "The Northern Pike", Aliases: []string{"Pike"}, LocalizedNames: map[string]string{"de": "Hecht", "fr": "Brochet"}},
+		{CommonName: "Tench", LocalizedNames: map[string]string{"de": "Schleie"}},
+	}
+	assert.Equal(t, []string{"northern", "pike", "brochet"}, ExpandSpecies([]string{"hecht"}, species))
+	assert.Empty(t, ExpandSpecies([]string{"hech"}, species))
+}
@@ -0,0 +1,22 @@
+package security
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ValidateAdminKey guards the administration endpoints. Without a configured admin key they are closed.
+func (s Security) ValidateAdminKey() gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		adminKey := c.Request.Header.Get("X-Admin-Key")
+
+		if len(s.AdminAPIKey) == 0 || strings.Compare(adminKey, s.AdminAPIKey) != 0 {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
+			c.Abort()
+			return
+		}
+	}
+}
@@ -1,11 +1,13 @@
 package security
 
 type Security struct {
-	APIKey string
+	APIKey      string
+	AdminAPIKey string
 }
 
-func NewSecurity(apiKey string) Security {
+func NewSecurity(apiKey, adminAPIKey string) Security {
 	return Security{
-		APIKey: apiKey,
+		APIKey:      apiKey,
+		AdminAPIKey: adminAPIKey,
 	}
 }
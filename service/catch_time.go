@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"fishfishes_backend/astronomy"
+	"fishfishes_backend/common"
+	"strings"
+	"time"
+)
+
+// timeAliases maps the free-form time values the app used to send to the parts of the day.
+var timeAliases = map[string]string{
+	"morning":   common.TimeMorning,
+	"dawn":      common.TimeMorning,
+	"day":       common.TimeDay,
+	"noon":      common.TimeDay,
+	"midday":    common.TimeDay,
+	"afternoon": common.TimeAfternoon,
+	"evening":   common.TimeAfternoon,
+	"dusk":      common.TimeAfternoon,
+	"night":     common.TimeNight,
+}
+
+// clockLayouts are the formats of a time of day without a date, found in old catches.
+var clockLayouts = []string{"15:04", "15:04:05", "3:04pm", "3:04 pm", "3pm", "3 pm"}
+
+// resolveCatchTimes derives the part of the day of each catch with a timestamp from the local sunrise and
+// sunset at the spot. The values of catches without a timestamp are only normalized.
+func (s Service) resolveCatchTimes(spot *common.Fish_spot) {
+	for i := range spot.Catches {
+		catch := &spot.Catches[i]
+		if catch.CaughtAt != nil {
+			catch.Time = s.dayPart(spot.Marker.Coordinates, *catch.CaughtAt)
+		} else if normalized, ok := normalizeTime(catch.Time); ok {
+			catch.Time = normalized
+		}
+	}
+}
+
+func (s Service) dayPart(coordinates common.Coordinates, at time.Time) string {
+	return astronomy.DayPart(coordinates, at.In(s.location(coordinates)))
+}
+
+// location returns the time zone of the coordinates, UTC if no resolver is configured.
+func (s Service) location(coordinates common.Coordinates) *time.Location {
+	if s.Zones == nil {
+		return time.UTC
+	}
+	return s.Zones.Location(coordinates)
+}
+
+// normalizeTime maps a free-form part of the day to its canonical value.
+func normalizeTime(value string) (string, bool) {
+	normalized, ok := timeAliases[strings.ToLower(strings.TrimSpace(value))]
+	return normalized, ok
+}
+
+// parseCatchTime tries to read a timestamp from the free-form time of an old catch. A time of day without
+// a date is taken as local time at the spot on the day the spot was saved.
+func parseCatchTime(value string, createdAt time.Time, location *time.Location) (*time.Time, bool) {
+
+	value = strings.TrimSpace(value)
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, true
+	}
+	if t, err := time.ParseInLocation("2006-01-02 15:04", value, location); err == nil {
+		return &t, true
+	}
+
+	if createdAt.IsZero() {
+		return nil, false
+	}
+	for _, layout := range clockLayouts {
+		if clock, err := time.Parse(layout, strings.ToLower(value)); err == nil {
+			day := createdAt.In(location)
+			t := time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, location)
+			return &t, true
+		}
+	}
+
+	return nil, false
+}
+
+// MigrateCatchTimes converts the free-form time of stored catches. Timestamps are moved to CaughtAt and the part
+// of the day is derived from them, known part of the day values are normalized. It returns the number of catches
+// changed and the number of catches whose value could not be parsed and was left as it is.
+func (s Service) MigrateCatchTimes(ctx context.Context) (int, int, error) {
+
+	migrated, unparsed := 0, 0
+
+	_, err := s.Repo.UpdateSpots(ctx, func(spot *common.Fish_spot, createdAt time.Time) bool {
+		changed := false
+		for i := range spot.Catches {
+			catch := &spot.Catches[i]
+			if catch.CaughtAt != nil || len(catch.Time) == 0 {
+				continue
+			}
+
+			if normalized, ok := normalizeTime(catch.Time); ok {
+				if normalized != catch.Time {
+					catch.Time = normalized
+					changed = true
+					migrated++
+				}
+				continue
+			}
+
+			caughtAt, ok := parseCatchTime(catch.Time, createdAt, s.location(spot.Marker.Coordinates))
+			if !ok {
+				unparsed++
+				continue
+			}
+			catch.CaughtAt = caughtAt
+			catch.Time = s.dayPart(spot.Marker.Coordinates, *caughtAt)
+			changed = true
+			migrated++
+		}
+		return changed
+	})
+
+	return migrated, unparsed, err
+}
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"fishfishes_backend/common"
+	"fishfishes_backend/records"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"time"
+)
+
+// AddCatch appends the catch of the request body to a spot of the user. Like SaveSpot it responds with
+// warnings for the rules of the region the catch breaks, without rejecting it, and the personal records it set.
+func (s Service) AddCatch(c *gin.Context) {
+	userId := c.Query("userId")
+	spotId := c.Query("spotId")
+	if len(userId) == 0 || len(spotId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID or spotId"})
+		return
+	}
+
+	catch, species, ok := s.bindCatch(c, userId)
+	if !ok {
+		return
+	}
+	catch.Id = ""
+
+	spots, spot, ok := s.userSpot(c, userId, spotId)
+	if !ok {
+		return
+	}
+
+	// the catch is prepared on its own, so that the stored catches are left as they are
+	added := common.Fish_spot{Id: spot.Id, Marker: spot.Marker, Catches: []common.Catch{catch}}
+	if err := s.prepareCatches(c, &added, species); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	catch = added.Catches[0]
+
+	err := s.Repo.AddCatch(c, userId, spotId, catch)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	spot.Catches = append(spot.Catches, catch)
+	changes, err := s.updateRecords(c, userId, spots, records.SpeciesKey(catch))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = s.recordActivities(c, userId, *spot, false, []common.Catch{catch})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":   "saved",
+		"catchId":  catch.Id,
+		"warnings": s.checkRegulations(*spot, []int{len(spot.Catches) - 1}, spots),
+		"records":  changes,
+	})
+}
+
+// UpdateCatch replaces the catch catchId of a spot of the user with the catch of the request body.
+func (s Service) UpdateCatch(c *gin.Context) {
+	userId := c.Query("userId")
+	spotId := c.Query("spotId")
+	catchId := c.Query("catchId")
+	if len(userId) == 0 || len(spotId) == 0 || len(catchId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID, spotId or catchId"})
+		return
+	}
+
+	catch, species, ok := s.bindCatch(c, userId)
+	if !ok {
+		return
+	}
+	catch.Id = catchId
+
+	spots, spot, ok := s.userSpot(c, userId, spotId)
+	if !ok {
+		return
+	}
+	index := catchIndex(*spot, catchId)
+	if index < 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No catch found"})
+		return
+	}
+
+	updated := common.Fish_spot{Id: spot.Id, Marker: spot.Marker, Catches: []common.Catch{catch}}
+	if err := s.prepareCatches(c, &updated, species); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	catch = updated.Catches[0]
+
+	err := s.Repo.UpdateCatch(c, userId, spotId, catch)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	previous := spot.Catches[index]
+	spot.Catches[index] = catch
+	changes, err := s.updateRecords(c, userId, spots, records.SpeciesKey(previous), records.SpeciesKey(catch))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":   "saved",
+		"warnings": s.checkRegulations(*spot, []int{index}, spots),
+		"records":  changes,
+	})
+}
+
+// DeleteCatch removes the catch catchId from a spot of the user with its comments and reactions.
+func (s Service) DeleteCatch(c *gin.Context) {
+	userId := c.Query("userId")
+	spotId := c.Query("spotId")
+	catchId := c.Query("catchId")
+	if len(userId) == 0 || len(spotId) == 0 || len(catchId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID, spotId or catchId"})
+		return
+	}
+
+	spots, spot, ok := s.userSpot(c, userId, spotId)
+	if !ok {
+		return
+	}
+	index := catchIndex(*spot, catchId)
+	if index < 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No catch found"})
+		return
+	}
+
+	err := s.Repo.DeleteCatch(c, userId, spotId, catchId)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// left behind, the reactions would still be counted for the spot
+	err = s.Repo.DeleteTarget(c, common.Target{OwnerId: userId, SpotId: spotId, CatchId: catchId})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	deleted := spot.Catches[index]
+	spot.Catches = append(spot.Catches[:index], spot.Catches[index+1:]...)
+	changes, err := s.updateRecords(c, userId, spots, records.SpeciesKey(deleted))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "deleted", "records": changes})
+}
+
+// bindCatch parses and validates the catch of the request body and links its equipment to the tackle box of
+// the user. It responds with an error and returns false, if the body is not a valid catch.
+func (s Service) bindCatch(c *gin.Context, userId string) (common.Catch, common.SpeciesIndex, bool) {
+	var catch common.Catch
+	if err := c.ShouldBindJSON(&catch); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "JSON Parse Error", "details": err.Error()})
+		return catch, common.SpeciesIndex{}, false
+	}
+
+	species, err := s.speciesIndex(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return catch, species, false
+	}
+
+	errs := catch.Validate("", species.Catalog())
+	catches := []common.Catch{catch}
+	unknown, err := s.linkTackle(c, userId, catches, func(int) string { return "equipment" })
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return catch, species, false
+	}
+	catch = catches[0]
+
+	if errs = append(errs, unknown...); len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "fields": errs})
+		return catch, species, false
+	}
+
+	return catch, species, true
+}
+
+// userSpot returns all spots of the user and the one with the given id among them. It responds with an
+// error and returns false, if the user has no such spot.
+func (s Service) userSpot(c *gin.Context, userId string, spotId string) ([]common.Fish_spot, *common.Fish_spot, bool) {
+	spots, err := s.Repo.GetAllSpots(c, userId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, nil, false
+	}
+
+	for i := range *spots {
+		if (*spots)[i].Id == spotId {
+			return *spots, &(*spots)[i], true
+		}
+	}
+
+	c.JSON(http.StatusNotFound, gin.H{"error": "No spot found"})
+	return nil, nil, false
+}
+
+// catchIndex returns the index of the catch with the given id in the spot, -1 if there is none.
+func catchIndex(spot common.Fish_spot, catchId string) int {
+	for i, catch := range spot.Catches {
+		if catch.Id == catchId {
+			return i
+		}
+	}
+	return -1
+}
+
+// assignCatchIds gives all catches of the spot without id a new one.
+func assignCatchIds(spot *common.Fish_spot) {
+	for i := range spot.Catches {
+		if len(spot.Catches[i].Id) == 0 {
+			spot.Catches[i].Id = uuid.New().String()
+		}
+	}
+}
+
+// MigrateCatchIds gives all stored catches without id a new one and returns the number of updated catches.
+func (s Service) MigrateCatchIds(ctx context.Context) (int, error) {
+
+	migrated := 0
+	_, err := s.Repo.UpdateSpots(ctx, func(spot *common.Fish_spot, createdAt time.Time) bool {
+		missing := 0
+		for _, catch := range spot.Catches {
+			if len(catch.Id) == 0 {
+				missing++
+			}
+		}
+		assignCatchIds(spot)
+		migrated += missing
+		return missing > 0
+	})
+
+	return migrated, err
+}
@@ -0,0 +1,368 @@
+package service
+
+import (
+	"context"
+	"fishfishes_backend/common"
+	"fishfishes_backend/moderation"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultCommentLimit int = 20
+	maxCommentLimit     int = 100
+)
+
+type commentBody struct {
+	Text string `json:"text"`
+}
+
+type reactionBody struct {
+	Type string `json:"type"`
+}
+
+// GetComments returns a page of the comments on the spot spotId of the user ownerId or, with catchId, on a
+// catch at it, the oldest first, each with its replies. The optional query parameters are limit, the size of
+// the page, and cursor, the nextCursor of the previous page.
+func (s Service) GetComments(c *gin.Context) {
+	userId := c.Query("userId")
+	if len(userId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID"})
+		return
+	}
+
+	target, ok := s.commentTarget(c, userId)
+	if !ok {
+		return
+	}
+
+	limit := defaultCommentLimit
+	if value := c.Query("limit"); len(value) > 0 {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxCommentLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and " + strconv.Itoa(maxCommentLimit)})
+			return
+		}
+		limit = parsed
+	}
+
+	cursor, err := common.DecodeCursor(c.Query("cursor"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	comments, err := s.Repo.GetComments(c, target, cursor, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	result := common.Comments{Items: []common.Comment{}}
+	if len(comments) > limit {
+		comments = comments[:limit]
+		last := comments[len(comments)-1]
+		result.NextCursor = common.Cursor{CreatedAt: last.CreatedAt, Id: last.Id}.Encode()
+	}
+
+	var ids []string
+	for _, comment := range comments {
+		ids = append(ids, comment.Id)
+	}
+	replies, err := s.Repo.GetReplies(c, ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	authors := map[string]bool{}
+	for _, comment := range append(comments, replies...) {
+		authors[comment.AuthorId] = true
+	}
+	var authorIds []string
+	for id := range authors {
+		authorIds = append(authorIds, id)
+	}
+	names, err := s.Repo.GetUserNames(c, authorIds)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	byParent := map[string][]common.Comment{}
+	for _, reply := range replies {
+		if reply.VisibleTo(userId) {
+			reply.AuthorName = names[reply.AuthorId]
+			byParent[reply.ParentId] = append(byParent[reply.ParentId], reply)
+		}
+	}
+	for _, comment := range comments {
+		if comment.VisibleTo(userId) {
+			comment.AuthorName = names[comment.AuthorId]
+			comment.Replies = byParent[comment.Id]
+			result.Items = append(result.Items, comment)
+		}
+	}
+
+	c.IndentedJSON(http.StatusOK, result)
+}
+
+// AddComment comments on the spot spotId of the user ownerId or, with catchId, on a catch at it. With
+// parentId the comment replies to another comment.
+func (s Service) AddComment(c *gin.Context) {
+	userId := c.Query("userId")
+	if len(userId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID"})
+		return
+	}
+
+	target, ok := s.commentTarget(c, userId)
+	if !ok {
+		return
+	}
+
+	var body commentBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "JSON Parse Error", "details": err.Error()})
+		return
+	}
+
+	comment := common.Comment{
+		Target:    target,
+		Id:        uuid.New().String(),
+		ParentId:  c.Query("parentId"),
+		AuthorId:  userId,
+		Text:      strings.TrimSpace(body.Text),
+		CreatedAt: time.Now().UTC(),
+	}
+
+	errs := common.ValidateCommentText(comment.Text)
+	if len(comment.ParentId) > 0 {
+		parent, err := s.Repo.GetComment(c, comment.ParentId)
+		if err != nil && err != common.ErrNotFound {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		errs = append(errs, comment.ValidateParent(parent)...)
+	}
+	if len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "fields": errs})
+		return
+	}
+
+	if !s.moderate(c, &comment) {
+		return
+	}
+
+	err := s.Repo.CreateComment(c, comment)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"status": "created", "commentId": comment.Id, "flagged": comment.Flagged})
+}
+
+// EditComment changes the text of the comment commentId of the user.
+func (s Service) EditComment(c *gin.Context) {
+	userId := c.Query("userId")
+	commentId := c.Query("commentId")
+	if len(userId) == 0 || len(commentId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID or commentId"})
+		return
+	}
+
+	var body commentBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "JSON Parse Error", "details": err.Error()})
+		return
+	}
+
+	comment, err := s.Repo.GetComment(c, commentId)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if comment.AuthorId != userId {
+		respondError(c, common.ErrForbidden)
+		return
+	}
+
+	comment.Text = strings.TrimSpace(body.Text)
+	if errs := common.ValidateCommentText(comment.Text); len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "fields": errs})
+		return
+	}
+
+	comment.Flagged = false
+	if !s.moderate(c, comment) {
+		return
+	}
+
+	err = s.Repo.UpdateComment(c, commentId, comment.Text, comment.Flagged, time.Now().UTC())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "saved", "flagged": comment.Flagged})
+}
+
+// DeleteComment removes the comment commentId with its replies. The author of the comment and the owner of
+// the spot may delete it.
+func (s Service) DeleteComment(c *gin.Context) {
+	userId := c.Query("userId")
+	commentId := c.Query("commentId")
+	if len(userId) == 0 || len(commentId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID or commentId"})
+		return
+	}
+
+	comment, err := s.Repo.GetComment(c, commentId)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if !comment.CanDelete(userId) {
+		respondError(c, common.ErrForbidden)
+		return
+	}
+
+	err = s.Repo.DeleteComment(c, commentId)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "deleted"})
+}
+
+// React sets the reaction of the user to the spot spotId of the user ownerId or, with catchId, to a catch at
+// it, replacing an earlier reaction of the user.
+func (s Service) React(c *gin.Context) {
+	userId := c.Query("userId")
+	if len(userId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID"})
+		return
+	}
+
+	target, ok := s.commentTarget(c, userId)
+	if !ok {
+		return
+	}
+
+	var body reactionBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "JSON Parse Error", "details": err.Error()})
+		return
+	}
+	if !common.IsReaction(body.Type) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "fields": common.ValidationErrors{{
+			Field: "type", Code: common.ErrorInvalid, Message: "type must be like, love, wow or respect",
+		}}})
+		return
+	}
+
+	reaction := common.Reaction{Target: target, Id: common.ReactionId(target, userId), UserId: userId, Type: body.Type, CreatedAt: time.Now().UTC()}
+	err := s.Repo.SaveReaction(c, reaction)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "saved"})
+}
+
+// Unreact removes the reaction of the user to a spot or catch.
+func (s Service) Unreact(c *gin.Context) {
+	userId := c.Query("userId")
+	if len(userId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID"})
+		return
+	}
+
+	target := common.Target{OwnerId: c.Query("ownerId"), SpotId: c.Query("spotId"), CatchId: c.Query("catchId")}
+	err := s.Repo.DeleteReaction(c, target, userId)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "deleted"})
+}
+
+// commentTarget reads the target of the query parameters ownerId, spotId and the optional catchId and checks
+// that the user can see it. It responds with an error and returns false, if the target does not exist, is
+// not shared with the user or the owner blocked the user.
+func (s Service) commentTarget(c *gin.Context, userId string) (common.Target, bool) {
+	target := common.Target{OwnerId: c.Query("ownerId"), SpotId: c.Query("spotId"), CatchId: c.Query("catchId")}
+	if len(target.OwnerId) == 0 || len(target.SpotId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no ownerId or spotId"})
+		return target, false
+	}
+
+	spot, err := s.findSpot(c, target.OwnerId, target.SpotId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return target, false
+	}
+
+	friend := false
+	if target.OwnerId != userId {
+		friendship, err := s.Repo.GetFriendship(c, userId, target.OwnerId)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return target, false
+		}
+		if friendship != nil && friendship.Status == common.FriendshipBlocked {
+			respondError(c, common.ErrForbidden)
+			return target, false
+		}
+		friend = friendship != nil && friendship.Status == common.FriendshipAccepted
+	}
+
+	// spots not shared with the user are reported as missing, so that their existence is not revealed
+	if spot == nil || !spot.VisibleTo(target.OwnerId == userId, friend) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No spot found"})
+		return target, false
+	}
+	if len(target.CatchId) > 0 && catchIndex(*spot, target.CatchId) < 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No catch found"})
+		return target, false
+	}
+
+	return target, true
+}
+
+// moderate passes the comment to the moderator. It flags the comment or, if it is rejected, responds with
+// an error and returns false.
+func (s Service) moderate(c *gin.Context, comment *common.Comment) bool {
+	decision, err := s.Moderator.Moderate(c, *comment)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+
+	switch decision.Verdict {
+	case moderation.Reject:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Comment rejected", "reason": decision.Reason})
+		return false
+	case moderation.Flag:
+		comment.Flagged = true
+	}
+	return true
+}
+
+// attachReactions sets the reaction counts of the spots of the user and their catches.
+func (s Service) attachReactions(ctx context.Context, userId string, spots []common.Fish_spot) error {
+	counts, err := s.Repo.GetReactionCounts(ctx, userId)
+	if err != nil {
+		return err
+	}
+	common.AttachReactions(spots, counts)
+	return nil
+}
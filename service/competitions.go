@@ -0,0 +1,322 @@
+package service
+
+import (
+	"fishfishes_backend/common"
+	"fishfishes_backend/competitions"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// GetCompetitions returns the competitions the user can take part in, the earliest first. The optional query
+// parameters from and to restrict them to those taking place at some time in between.
+func (s Service) GetCompetitions(c *gin.Context) {
+	userId := c.Query("userId")
+	if len(userId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID"})
+		return
+	}
+
+	from, to, err := dateRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	groupIds, err := s.groupIds(c, userId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := s.Repo.GetCompetitions(c, userId, groupIds, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, result)
+}
+
+func (s Service) GetCompetitionByID(c *gin.Context) {
+	userId := c.Query("userId")
+	competitionId := c.Query("competitionId")
+	if len(userId) == 0 || len(competitionId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID or competitionId"})
+		return
+	}
+
+	competition, ok := s.visibleCompetition(c, competitionId, userId)
+	if !ok {
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, competition)
+}
+
+// CreateCompetition creates the competition of the request body with the user as organizer. Admins and the
+// owner of its group can.
+func (s Service) CreateCompetition(c *gin.Context) {
+	userId := c.Query("userId")
+	if len(userId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID"})
+		return
+	}
+
+	competition, ok := bindCompetition(c)
+	if !ok {
+		return
+	}
+	if _, ok := s.groupMembership(c, competition.GroupId, userId, common.Membership.CanEdit); !ok {
+		return
+	}
+	competition.Id = uuid.New().String()
+	competition.OrganizerId = userId
+	competition.CreatedAt = time.Now().UTC()
+
+	err := s.Repo.CreateCompetition(c, competition)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"status": "created", "competitionId": competition.Id})
+}
+
+// UpdateCompetition replaces the rules of a competition of the user. The rules can not change once the
+// competition has started, as catches were already checked against them, and the group never changes.
+func (s Service) UpdateCompetition(c *gin.Context) {
+	userId := c.Query("userId")
+	competitionId := c.Query("competitionId")
+	if len(userId) == 0 || len(competitionId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID or competitionId"})
+		return
+	}
+
+	competition, ok := bindCompetition(c)
+	if !ok {
+		return
+	}
+
+	existing, ok := s.visibleCompetition(c, competitionId, userId)
+	if !ok {
+		return
+	}
+	if existing.OrganizerId != userId {
+		respondError(c, common.ErrForbidden)
+		return
+	}
+	if !time.Now().Before(existing.Start) {
+		c.JSON(http.StatusConflict, gin.H{"error": "The competition has already started"})
+		return
+	}
+
+	competition.Id = existing.Id
+	competition.OrganizerId = existing.OrganizerId
+	competition.GroupId = existing.GroupId
+	competition.CreatedAt = existing.CreatedAt
+	err := s.Repo.UpdateCompetition(c, competition)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "saved"})
+}
+
+// DeleteCompetition removes a competition of the user with all submissions. Once the results are final, the
+// competition stays.
+func (s Service) DeleteCompetition(c *gin.Context) {
+	userId := c.Query("userId")
+	competitionId := c.Query("competitionId")
+	if len(userId) == 0 || len(competitionId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID or competitionId"})
+		return
+	}
+
+	competition, ok := s.visibleCompetition(c, competitionId, userId)
+	if !ok {
+		return
+	}
+	if competition.OrganizerId != userId {
+		respondError(c, common.ErrForbidden)
+		return
+	}
+	if competitions.Final(*competition, time.Now()) {
+		c.JSON(http.StatusConflict, gin.H{"error": "The results of the competition are final"})
+		return
+	}
+
+	err := s.Repo.DeleteCompetition(c, userId, competitionId)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "deleted"})
+}
+
+// SubmitCatch enters the catch catchId of the user into the competition competitionId, which has to be one
+// of the group of the user or public. The catch is checked against the rules of the competition and scored
+// with its values at the time of the submission.
+func (s Service) SubmitCatch(c *gin.Context) {
+	userId := c.Query("userId")
+	competitionId := c.Query("competitionId")
+	catchId := c.Query("catchId")
+	if len(userId) == 0 || len(competitionId) == 0 || len(catchId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID, competitionId or catchId"})
+		return
+	}
+
+	competition, ok := s.visibleCompetition(c, competitionId, userId)
+	if !ok {
+		return
+	}
+
+	spots, err := s.Repo.GetAllSpots(c, userId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	for _, spot := range *spots {
+		for _, catch := range spot.Catches {
+			if catch.Id != catchId {
+				continue
+			}
+
+			submission, errs := competitions.Submit(*competition, userId, spot, catch, time.Now().UTC())
+			if len(errs) > 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "fields": errs})
+				return
+			}
+
+			err = s.Repo.CreateSubmission(c, submission)
+			if err != nil {
+				respondError(c, err)
+				return
+			}
+
+			c.JSON(http.StatusCreated, gin.H{"status": "submitted", "submissionId": submission.Id})
+			return
+		}
+	}
+
+	c.JSON(http.StatusNotFound, gin.H{"error": "No catch found"})
+}
+
+// WithdrawSubmission removes the catch catchId of the user from the competition competitionId, as long as
+// the results are not final.
+func (s Service) WithdrawSubmission(c *gin.Context) {
+	userId := c.Query("userId")
+	competitionId := c.Query("competitionId")
+	catchId := c.Query("catchId")
+	if len(userId) == 0 || len(competitionId) == 0 || len(catchId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID, competitionId or catchId"})
+		return
+	}
+
+	competition, err := s.Repo.GetCompetition(c, competitionId)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if competitions.Final(*competition, time.Now()) {
+		c.JSON(http.StatusConflict, gin.H{"error": "The results of the competition are final"})
+		return
+	}
+
+	err = s.Repo.DeleteSubmission(c, userId, common.SubmissionId(competitionId, catchId))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "withdrawn"})
+}
+
+// GetLeaderboard returns the standings of the participants of the competition competitionId. While the
+// competition is running they change with each submission; the final results are marked as such.
+func (s Service) GetLeaderboard(c *gin.Context) {
+	userId := c.Query("userId")
+	competitionId := c.Query("competitionId")
+	if len(userId) == 0 || len(competitionId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID or competitionId"})
+		return
+	}
+
+	competition, ok := s.visibleCompetition(c, competitionId, userId)
+	if !ok {
+		return
+	}
+
+	submissions, err := s.Repo.GetSubmissions(c, competitionId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	leaderboard := competitions.Rank(*competition, submissions, time.Now())
+
+	var ids []string
+	for _, standing := range leaderboard.Standings {
+		ids = append(ids, standing.UserId)
+	}
+	names, err := s.Repo.GetUserNames(c, ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	for i := range leaderboard.Standings {
+		leaderboard.Standings[i].UserName = names[leaderboard.Standings[i].UserId]
+	}
+
+	c.IndentedJSON(http.StatusOK, leaderboard)
+}
+
+// visibleCompetition returns the competition, if the user can see it. It responds with an error and returns
+// false otherwise, reporting competitions of other groups as missing.
+func (s Service) visibleCompetition(c *gin.Context, competitionId string, userId string) (*common.Competition, bool) {
+	competition, err := s.Repo.GetCompetition(c, competitionId)
+	if err != nil {
+		respondError(c, err)
+		return nil, false
+	}
+
+	membership, err := s.Repo.GetMembership(c, competition.GroupId, userId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if !competitions.Visible(*competition, userId, membership) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No competition found"})
+		return nil, false
+	}
+
+	return competition, true
+}
+
+// bindCompetition parses and validates the competition of the request body. It responds with 400 and returns
+// false, if the body is not a valid competition.
+func bindCompetition(c *gin.Context) (common.Competition, bool) {
+	var competition common.Competition
+	if err := c.ShouldBindJSON(&competition); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "JSON Parse Error", "details": err.Error()})
+		return competition, false
+	}
+	competition.Name = strings.TrimSpace(competition.Name)
+
+	if errs := competition.Validate(); len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "fields": errs})
+		return competition, false
+	}
+
+	// an empty species list allows all species and is stored empty rather than null
+	if competition.Species == nil {
+		competition.Species = []string{}
+	}
+
+	return competition, true
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"fishfishes_backend/cpue"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+// GetCPUE returns the catch per hour and the success rate of the trips of the user, overall and by spot,
+// species, month, part of the day and bait, with their confidence intervals. The optional query parameters
+// from and to restrict the start of the trips.
+func (s Service) GetCPUE(c *gin.Context) {
+	id := c.Query("userId")
+	if len(id) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID"})
+		return
+	}
+
+	from, to, err := dateRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	efforts, err := s.Repo.GetTripEfforts(c, id, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, cpue.Compute(efforts, s.location, time.Now().UTC()))
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"fishfishes_backend/export"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// ExportSpots streams the spots of the user as GeoJSON, GPX or KML. The format is chosen by the format
+// query parameter or, if it is missing, by the Accept header.
+func (s Service) ExportSpots(c *gin.Context) {
+	id := c.Query("userId")
+	if len(id) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID"})
+		return
+	}
+
+	format := export.SpotFormatByContentType(c.GetHeader("Accept"))
+	if name := c.Query("format"); len(name) > 0 {
+		format = export.SpotFormat(name)
+	}
+	if format == nil {
+		c.JSON(http.StatusNotAcceptable, gin.H{"error": "format must be geojson, gpx or kml"})
+		return
+	}
+
+	spots, err := s.Repo.GetAllSpots(c, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	system, err := s.unitSystem(c, id)
+	if err == nil {
+		err = toUnitSystem(*spots, system)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", format.ContentType)
+	c.Header("Content-Disposition", "attachment; filename=spots."+format.Extension)
+	c.Status(http.StatusOK)
+
+	err = export.WriteSpots(c.Writer, format.Name, *spots)
+	if err != nil {
+		// the status is already sent, so the client can only notice the broken stream
+		_ = c.Error(err)
+	}
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"fishfishes_backend/common"
+	"fishfishes_backend/export"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// ExportCatches streams the catch log of the user as CSV or XLSX, one row per catch. The optional query
+// parameters from and to restrict the date range, fish may be repeated to restrict the species.
+func (s Service) ExportCatches(c *gin.Context) {
+	id := c.Query("userId")
+	if len(id) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID"})
+		return
+	}
+
+	format := export.CatchFormatByContentType(c.GetHeader("Accept"))
+	if name := c.Query("format"); len(name) > 0 {
+		format = export.CatchFormat(name)
+	}
+	if format == nil {
+		c.JSON(http.StatusNotAcceptable, gin.H{"error": "format must be csv or xlsx"})
+		return
+	}
+
+	filter := export.CatchFilter{Species: c.QueryArray("fish")}
+	var err error
+	filter.From, err = parseDate(c.Query("from"), false)
+	if err == nil {
+		filter.To, err = parseDate(c.Query("to"), true)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	spots, err := s.Repo.GetAllSpots(c, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	system, err := s.unitSystem(c, id)
+	if err == nil {
+		err = toUnitSystem(*spots, system)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	rows := export.FlattenCatches(*spots, filter)
+
+	c.Header("Content-Type", format.ContentType)
+	c.Header("Content-Disposition", "attachment; filename=catches."+format.Extension)
+	c.Status(http.StatusOK)
+
+	err = export.WriteCatches(c.Writer, format.Name, rows, common.SizeUnitOf(system), common.DepthUnitOf(system))
+	if err != nil {
+		// the status is already sent, so the client can only notice the broken stream
+		_ = c.Error(err)
+	}
+}
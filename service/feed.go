@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"fishfishes_backend/common"
+	"fishfishes_backend/feed"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultFeedLimit int = 20
+	maxFeedLimit     int = 100
+)
+
+// GetFeed returns a page of the activities of the friends of the user on their shared spots, the latest
+// first. The optional query parameters are limit, the size of the page, and cursor, the nextCursor of the
+// previous page. Sizes and depths are in the unit system of the user.
+func (s Service) GetFeed(c *gin.Context) {
+	id := c.Query("userId")
+	if len(id) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID"})
+		return
+	}
+
+	limit := defaultFeedLimit
+	if value := c.Query("limit"); len(value) > 0 {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxFeedLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and " + strconv.Itoa(maxFeedLimit)})
+			return
+		}
+		limit = parsed
+	}
+
+	cursor, err := feed.DecodeCursor(c.Query("cursor"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	friends, err := s.friendIds(c, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if len(friends) == 0 {
+		c.IndentedJSON(http.StatusOK, common.Feed{Items: []common.FeedItem{}})
+		return
+	}
+
+	entries, err := s.Repo.GetFeed(c, friends, cursor, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	entries, next := feed.Page(entries, limit)
+
+	names, err := s.Repo.GetUserNames(c, friends)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	system, err := s.unitSystem(c, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	result := common.Feed{Items: []common.FeedItem{}, NextCursor: next}
+	for _, entry := range entries {
+		if entry.Catch != nil {
+			converted, err := entry.Catch.ToUnitSystem(system)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			entry.Catch = &converted
+		}
+		result.Items = append(result.Items, feed.Item(entry, names[entry.UserId]))
+	}
+
+	c.IndentedJSON(http.StatusOK, result)
+}
+
+type spotVisibility struct {
+	Visibility   string `json:"visibility"`
+	HideLocation bool   `json:"hideLocation"`
+}
+
+// SetSpotVisibility changes who can see the spot spotId of the user and whether others see its location.
+func (s Service) SetSpotVisibility(c *gin.Context) {
+	userId := c.Query("userId")
+	spotId := c.Query("spotId")
+	if len(userId) == 0 || len(spotId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID or spotId"})
+		return
+	}
+
+	var body spotVisibility
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "JSON Parse Error", "details": err.Error()})
+		return
+	}
+	if len(body.Visibility) == 0 || !common.IsVisibility(body.Visibility) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "fields": common.ValidationErrors{{
+			Field: "visibility", Code: common.ErrorInvalid, Message: "visibility must be private, friends or public",
+		}}})
+		return
+	}
+
+	err := s.Repo.SetSpotVisibility(c, userId, spotId, body.Visibility, body.HideLocation)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "saved"})
+}
+
+// friendIds returns the ids of the friends of the user.
+func (s Service) friendIds(ctx context.Context, userId string) ([]string, error) {
+	friendships, err := s.Repo.GetFriendships(ctx, userId, common.FriendshipAccepted)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []string
+	for _, friendship := range friendships {
+		result = append(result, friendship.Other(userId))
+	}
+	return result, nil
+}
+
+// recordActivities stores the activities of saving the spot with the given new catches for the feed.
+func (s Service) recordActivities(ctx context.Context, userId string, spot common.Fish_spot, newSpot bool, catches []common.Catch) error {
+	activities := feed.Activities(userId, spot, newSpot, catches, time.Now().UTC(), func() string {
+		return uuid.New().String()
+	})
+	return s.Repo.AddActivities(ctx, activities)
+}
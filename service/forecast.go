@@ -0,0 +1,69 @@
+package service
+
+import (
+	"fishfishes_backend/astronomy"
+	"fishfishes_backend/common"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const maxForecastDays int = 14
+
+func (s Service) GetForecast(c *gin.Context) {
+	userId := c.Query("userId")
+	spotId := c.Query("spotId")
+	if len(userId) == 0 || len(spotId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID or spotId"})
+		return
+	}
+
+	days := 1
+	if value := c.Query("days"); len(value) > 0 {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxForecastDays {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "days must be a number between 1 and " + strconv.Itoa(maxForecastDays)})
+			return
+		}
+		days = parsed
+	}
+
+	spot, err := s.findSpot(c, userId, spotId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if spot == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No spot found"})
+		return
+	}
+
+	// the days are the local days at the spot, unless the caller asks for another time zone
+	location := s.location(spot.Marker.Coordinates)
+	if tz := c.Query("tz"); len(tz) > 0 {
+		loaded, err := time.LoadLocation(tz)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unknown tz '" + tz + "'"})
+			return
+		}
+		location = loaded
+	}
+
+	date := time.Now().In(location)
+	if value := c.Query("date"); len(value) > 0 {
+		parsed, err := time.ParseInLocation(dateLayout, value, location)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "date must be formatted as " + dateLayout})
+			return
+		}
+		date = parsed
+	}
+
+	forecasts := make([]common.Forecast, 0, days)
+	for i := 0; i < days; i++ {
+		forecasts = append(forecasts, astronomy.NewForecast(spot.Marker.Coordinates, date.AddDate(0, 0, i)))
+	}
+
+	c.IndentedJSON(http.StatusOK, forecasts)
+}
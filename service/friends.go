@@ -0,0 +1,149 @@
+package service
+
+import (
+	"fishfishes_backend/common"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+// actionStatus is the status of the response to each friend action.
+var actionStatus = map[string]string{
+	common.FriendRequest:  "requested",
+	common.FriendAccept:   "accepted",
+	common.FriendDecline:  "declined",
+	common.FriendCancel:   "cancelled",
+	common.FriendUnfollow: "unfollowed",
+	common.FriendBlock:    "blocked",
+	common.FriendUnblock:  "unblocked",
+}
+
+func (s Service) SendFriendRequest(c *gin.Context) {
+	s.friendAction(c, common.FriendRequest)
+}
+
+func (s Service) AcceptFriendRequest(c *gin.Context) {
+	s.friendAction(c, common.FriendAccept)
+}
+
+func (s Service) DeclineFriendRequest(c *gin.Context) {
+	s.friendAction(c, common.FriendDecline)
+}
+
+func (s Service) CancelFriendRequest(c *gin.Context) {
+	s.friendAction(c, common.FriendCancel)
+}
+
+func (s Service) Unfollow(c *gin.Context) {
+	s.friendAction(c, common.FriendUnfollow)
+}
+
+func (s Service) BlockUser(c *gin.Context) {
+	s.friendAction(c, common.FriendBlock)
+}
+
+func (s Service) UnblockUser(c *gin.Context) {
+	s.friendAction(c, common.FriendUnblock)
+}
+
+// friendAction takes the action of the user on the relationship with the user friendId.
+func (s Service) friendAction(c *gin.Context, action string) {
+	userId := c.Query("userId")
+	friendId := c.Query("friendId")
+	if len(userId) == 0 || len(friendId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID or friendId"})
+		return
+	}
+	if userId == friendId {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "friendId must be another user"})
+		return
+	}
+
+	users, err := s.Repo.GetUserNames(c, []string{friendId})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if _, ok := users[friendId]; !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No user found"})
+		return
+	}
+
+	current, err := s.Repo.GetFriendship(c, userId, friendId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	next, err := common.ApplyFriendAction(current, action, userId, friendId, time.Now().UTC())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if next != nil {
+		err = s.Repo.SaveFriendship(c, *next)
+	} else if current != nil {
+		err = s.Repo.DeleteFriendship(c, current.Id)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": actionStatus[action]})
+}
+
+// GetFriends returns the friends of the user.
+func (s Service) GetFriends(c *gin.Context) {
+	s.getFriends(c, common.FriendshipAccepted, false)
+}
+
+// GetFriendRequests returns the pending friend requests the user received and sent.
+func (s Service) GetFriendRequests(c *gin.Context) {
+	s.getFriends(c, common.FriendshipPending, false)
+}
+
+// GetBlockedUsers returns the users the user blocked. Users who blocked the user are not revealed.
+func (s Service) GetBlockedUsers(c *gin.Context) {
+	s.getFriends(c, common.FriendshipBlocked, true)
+}
+
+func (s Service) getFriends(c *gin.Context, status string, outgoingOnly bool) {
+	userId := c.Query("userId")
+	if len(userId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID"})
+		return
+	}
+
+	friendships, err := s.Repo.GetFriendships(c, userId, status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var others []string
+	for _, friendship := range friendships {
+		others = append(others, friendship.Other(userId))
+	}
+	names, err := s.Repo.GetUserNames(c, others)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	result := []common.Friend{}
+	for _, friendship := range friendships {
+		direction := common.DirectionIncoming
+		if friendship.RequesterId == userId {
+			direction = common.DirectionOutgoing
+		}
+		if outgoingOnly && direction != common.DirectionOutgoing {
+			continue
+		}
+		other := friendship.Other(userId)
+		result = append(result, common.Friend{UserId: other, Name: names[other], Status: friendship.Status, Direction: direction, Since: friendship.UpdatedAt})
+	}
+
+	c.IndentedJSON(http.StatusOK, result)
+}
@@ -0,0 +1,562 @@
+package service
+
+import (
+	"context"
+	"fishfishes_backend/common"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type groupRole struct {
+	Role string `json:"role"`
+}
+
+// CreateGroup creates the group of the request body with the user as its owner.
+func (s Service) CreateGroup(c *gin.Context) {
+	userId := c.Query("userId")
+	if len(userId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID"})
+		return
+	}
+
+	group, ok := bindGroup(c)
+	if !ok {
+		return
+	}
+	group.Id = uuid.New().String()
+	group.CreatedAt = time.Now().UTC()
+
+	owner := common.Membership{Id: common.MembershipId(group.Id, userId), GroupId: group.Id, UserId: userId, Role: common.GroupOwner, Status: common.MembershipActive, CreatedAt: group.CreatedAt}
+	err := s.Repo.CreateGroup(c, group, owner)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"status": "created", "groupId": group.Id})
+}
+
+// GetGroups returns the groups the user is a member of or invited to with the role of the user.
+func (s Service) GetGroups(c *gin.Context) {
+	userId := c.Query("userId")
+	if len(userId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID"})
+		return
+	}
+
+	memberships, err := s.Repo.GetMemberships(c, userId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	byGroup := map[string]common.Membership{}
+	var ids []string
+	for _, membership := range memberships {
+		byGroup[membership.GroupId] = membership
+		ids = append(ids, membership.GroupId)
+	}
+	groups, err := s.Repo.GetGroups(c, ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	result := []common.UserGroup{}
+	for _, group := range groups {
+		membership := byGroup[group.Id]
+		result = append(result, common.UserGroup{Group: group, Role: membership.Role, Status: membership.Status})
+	}
+
+	c.IndentedJSON(http.StatusOK, result)
+}
+
+// UpdateGroup changes the name and description of the group groupId. Admins and the owner can.
+func (s Service) UpdateGroup(c *gin.Context) {
+	userId := c.Query("userId")
+	groupId := c.Query("groupId")
+	if len(userId) == 0 || len(groupId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID or groupId"})
+		return
+	}
+
+	group, ok := bindGroup(c)
+	if !ok {
+		return
+	}
+	group.Id = groupId
+
+	if _, ok := s.groupMembership(c, groupId, userId, common.Membership.CanEdit); !ok {
+		return
+	}
+
+	err := s.Repo.UpdateGroup(c, group)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "saved"})
+}
+
+// DeleteGroup removes the group groupId with its spots. Only the owner can.
+func (s Service) DeleteGroup(c *gin.Context) {
+	userId := c.Query("userId")
+	groupId := c.Query("groupId")
+	if len(userId) == 0 || len(groupId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID or groupId"})
+		return
+	}
+
+	isOwner := func(m common.Membership) bool {
+		return m.Active() && m.Role == common.GroupOwner
+	}
+	if _, ok := s.groupMembership(c, groupId, userId, isOwner); !ok {
+		return
+	}
+
+	err := s.Repo.DeleteGroup(c, groupId)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "deleted"})
+}
+
+// GetGroupMembers returns the members and invited users of the group groupId.
+func (s Service) GetGroupMembers(c *gin.Context) {
+	userId := c.Query("userId")
+	groupId := c.Query("groupId")
+	if len(userId) == 0 || len(groupId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID or groupId"})
+		return
+	}
+
+	if _, ok := s.groupMembership(c, groupId, userId, common.Membership.CanView); !ok {
+		return
+	}
+
+	members, err := s.Repo.GetGroupMembers(c, groupId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var ids []string
+	for _, member := range members {
+		ids = append(ids, member.UserId)
+	}
+	names, err := s.Repo.GetUserNames(c, ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	for i := range members {
+		members[i].UserName = names[members[i].UserId]
+	}
+
+	c.IndentedJSON(http.StatusOK, members)
+}
+
+// InviteToGroup invites the user inviteeId to the group groupId as a member. Admins and the owner can.
+func (s Service) InviteToGroup(c *gin.Context) {
+	userId := c.Query("userId")
+	groupId := c.Query("groupId")
+	inviteeId := c.Query("inviteeId")
+	if len(userId) == 0 || len(groupId) == 0 || len(inviteeId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID, groupId or inviteeId"})
+		return
+	}
+
+	if _, ok := s.groupMembership(c, groupId, userId, common.Membership.CanEdit); !ok {
+		return
+	}
+
+	users, err := s.Repo.GetUserNames(c, []string{inviteeId})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if _, ok := users[inviteeId]; !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No user found"})
+		return
+	}
+
+	friendship, err := s.Repo.GetFriendship(c, userId, inviteeId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if friendship != nil && friendship.Status == common.FriendshipBlocked {
+		respondError(c, common.ErrForbidden)
+		return
+	}
+
+	existing, err := s.Repo.GetMembership(c, groupId, inviteeId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if existing != nil {
+		respondError(c, common.ErrConflict)
+		return
+	}
+
+	invitation := common.Membership{Id: common.MembershipId(groupId, inviteeId), GroupId: groupId, UserId: inviteeId, Role: common.GroupMember, Status: common.MembershipInvited, InvitedBy: userId, CreatedAt: time.Now().UTC()}
+	err = s.Repo.SaveMembership(c, invitation)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "invited"})
+}
+
+// AcceptGroupInvitation makes the user a member of the group groupId the user was invited to.
+func (s Service) AcceptGroupInvitation(c *gin.Context) {
+	s.answerInvitation(c, true)
+}
+
+func (s Service) DeclineGroupInvitation(c *gin.Context) {
+	s.answerInvitation(c, false)
+}
+
+func (s Service) answerInvitation(c *gin.Context, accept bool) {
+	userId := c.Query("userId")
+	groupId := c.Query("groupId")
+	if len(userId) == 0 || len(groupId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID or groupId"})
+		return
+	}
+
+	invitation, err := s.Repo.GetMembership(c, groupId, userId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if invitation == nil || invitation.Status != common.MembershipInvited {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No invitation found"})
+		return
+	}
+
+	if !accept {
+		err = s.Repo.DeleteMembership(c, groupId, userId)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "declined"})
+		return
+	}
+
+	invitation.Status = common.MembershipActive
+	invitation.CreatedAt = time.Now().UTC()
+	err = s.Repo.SaveMembership(c, *invitation)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "accepted"})
+}
+
+// LeaveGroup ends the membership of the user in the group groupId. The owner has to hand the group over or
+// delete it instead.
+func (s Service) LeaveGroup(c *gin.Context) {
+	userId := c.Query("userId")
+	groupId := c.Query("groupId")
+	if len(userId) == 0 || len(groupId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID or groupId"})
+		return
+	}
+
+	membership, ok := s.groupMembership(c, groupId, userId, common.Membership.Active)
+	if !ok {
+		return
+	}
+	if membership.Role == common.GroupOwner {
+		c.JSON(http.StatusConflict, gin.H{"error": "The owner can not leave the group"})
+		return
+	}
+
+	err := s.Repo.DeleteMembership(c, groupId, userId)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "left"})
+}
+
+// RemoveGroupMember removes the member or invitation memberId from the group groupId. Admins remove members,
+// the owner also admins.
+func (s Service) RemoveGroupMember(c *gin.Context) {
+	userId := c.Query("userId")
+	groupId := c.Query("groupId")
+	memberId := c.Query("memberId")
+	if len(userId) == 0 || len(groupId) == 0 || len(memberId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID, groupId or memberId"})
+		return
+	}
+
+	membership, ok := s.groupMembership(c, groupId, userId, common.Membership.CanEdit)
+	if !ok {
+		return
+	}
+	member, ok := s.otherMembership(c, groupId, memberId)
+	if !ok {
+		return
+	}
+	if !membership.CanRemove(*member) {
+		respondError(c, common.ErrForbidden)
+		return
+	}
+
+	err := s.Repo.DeleteMembership(c, groupId, memberId)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "removed"})
+}
+
+// SetGroupRole gives the member memberId of the group groupId the role of the request body. Only the owner
+// can; giving the owner role hands the group over and makes the previous owner an admin.
+func (s Service) SetGroupRole(c *gin.Context) {
+	userId := c.Query("userId")
+	groupId := c.Query("groupId")
+	memberId := c.Query("memberId")
+	if len(userId) == 0 || len(groupId) == 0 || len(memberId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID, groupId or memberId"})
+		return
+	}
+
+	var body groupRole
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "JSON Parse Error", "details": err.Error()})
+		return
+	}
+	if !common.IsGroupRole(body.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "fields": common.ValidationErrors{{
+			Field: "role", Code: common.ErrorInvalid, Message: "role must be owner, admin or member",
+		}}})
+		return
+	}
+
+	membership, ok := s.groupMembership(c, groupId, userId, common.Membership.Active)
+	if !ok {
+		return
+	}
+	member, ok := s.otherMembership(c, groupId, memberId)
+	if !ok {
+		return
+	}
+	if !membership.CanSetRole(*member, body.Role) {
+		respondError(c, common.ErrForbidden)
+		return
+	}
+
+	var err error
+	if body.Role == common.GroupOwner {
+		err = s.Repo.HandOverGroup(c, groupId, userId, memberId)
+	} else {
+		member.Role = body.Role
+		err = s.Repo.SaveMembership(c, *member)
+	}
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "saved"})
+}
+
+// GetGroupSpots returns the spots of the group groupId.
+func (s Service) GetGroupSpots(c *gin.Context) {
+	userId := c.Query("userId")
+	groupId := c.Query("groupId")
+	if len(userId) == 0 || len(groupId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID or groupId"})
+		return
+	}
+
+	if _, ok := s.groupMembership(c, groupId, userId, common.Membership.CanView); !ok {
+		return
+	}
+
+	spots, err := s.Repo.GetGroupSpots(c, []string{groupId})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, spots)
+}
+
+// SaveGroupSpot stores the spot of the request body in the group groupId, a new one if it has no id.
+// Admins and the owner can.
+func (s Service) SaveGroupSpot(c *gin.Context) {
+	userId := c.Query("userId")
+	groupId := c.Query("groupId")
+	if len(userId) == 0 || len(groupId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID or groupId"})
+		return
+	}
+
+	var spot common.GroupSpot
+	if err := c.ShouldBindJSON(&spot); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "JSON Parse Error", "details": err.Error()})
+		return
+	}
+	if errs := spot.Validate(); len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "fields": errs})
+		return
+	}
+
+	if _, ok := s.groupMembership(c, groupId, userId, common.Membership.CanEdit); !ok {
+		return
+	}
+
+	if len(spot.Id) == 0 {
+		spot.Id = uuid.New().String()
+	}
+	spot.GroupId = groupId
+	spot.CreatedBy = userId // kept if the spot exists
+	spot.UpdatedBy = userId
+	spot.UpdatedAt = time.Now().UTC()
+	spot.Marker.Id = spot.Id
+
+	err := s.Repo.SaveGroupSpot(c, spot)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "saved", "spotId": spot.Id})
+}
+
+// DeleteGroupSpot removes the spot spotId of the group groupId. Admins and the owner can.
+func (s Service) DeleteGroupSpot(c *gin.Context) {
+	userId := c.Query("userId")
+	groupId := c.Query("groupId")
+	spotId := c.Query("spotId")
+	if len(userId) == 0 || len(groupId) == 0 || len(spotId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID, groupId or spotId"})
+		return
+	}
+
+	if _, ok := s.groupMembership(c, groupId, userId, common.Membership.CanEdit); !ok {
+		return
+	}
+
+	err := s.Repo.DeleteGroupSpot(c, groupId, spotId)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "deleted"})
+}
+
+// groupMembership returns the membership of the user in the group and checks that it allows the action.
+// It responds with an error and returns false, if the user is no member, which is reported as a missing
+// group, or may not take the action.
+func (s Service) groupMembership(c *gin.Context, groupId string, userId string, allowed func(m common.Membership) bool) (*common.Membership, bool) {
+	membership, err := s.Repo.GetMembership(c, groupId, userId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if membership == nil || !membership.Active() {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No group found"})
+		return nil, false
+	}
+	if !allowed(*membership) {
+		respondError(c, common.ErrForbidden)
+		return nil, false
+	}
+	return membership, true
+}
+
+// otherMembership returns the membership or invitation of another user in the group. It responds with 404
+// and returns false, if there is none.
+func (s Service) otherMembership(c *gin.Context, groupId string, memberId string) (*common.Membership, bool) {
+	member, err := s.Repo.GetMembership(c, groupId, memberId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if member == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No member found"})
+		return nil, false
+	}
+	return member, true
+}
+
+// groupIds returns the ids of the groups the user is a member of rather than invited to.
+func (s Service) groupIds(ctx context.Context, userId string) ([]string, error) {
+	memberships, err := s.Repo.GetMemberships(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, membership := range memberships {
+		if membership.CanView() {
+			ids = append(ids, membership.GroupId)
+		}
+	}
+	return ids, nil
+}
+
+// groupMarkers returns the markers of the spots of the groups the user is a member of, with the group as
+// their layer.
+func (s Service) groupMarkers(ctx context.Context, userId string) ([]common.Marker, error) {
+	ids, err := s.groupIds(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := s.Repo.GetGroups(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	names := map[string]string{}
+	for _, group := range groups {
+		names[group.Id] = group.Name
+	}
+
+	spots, err := s.Repo.GetGroupSpots(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var markers []common.Marker
+	for _, spot := range spots {
+		markers = append(markers, common.Marker{Id: spot.Id, Title: spot.Marker.Title, Coordinates: spot.Marker.Coordinates, Layer: spot.GroupId, LayerName: names[spot.GroupId]})
+	}
+	return markers, nil
+}
+
+// bindGroup parses and validates the group of the request body. It responds with 400 and returns false, if
+// the body is not a valid group.
+func bindGroup(c *gin.Context) (common.Group, bool) {
+	var group common.Group
+	if err := c.ShouldBindJSON(&group); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "JSON Parse Error", "details": err.Error()})
+		return group, false
+	}
+	group.Name = strings.TrimSpace(group.Name)
+
+	if errs := group.Validate(); len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "fields": errs})
+		return group, false
+	}
+
+	return group, true
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"fishfishes_backend/common"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// groupRepo keeps the memberships and spots of groups in memory. Calls of other methods of Repo panic.
+type groupRepo struct {
+	Repo
+	memberships map[string]common.Membership
+	spots       map[string]common.GroupSpot
+}
+
+func newGroupRepo(memberships ...common.Membership) *groupRepo {
+	r := &groupRepo{memberships: map[string]common.Membership{}, spots: map[string]common.GroupSpot{}}
+	for _, membership := range memberships {
+		membership.Id = common.MembershipId(membership.GroupId, membership.UserId)
+		r.memberships[membership.Id] = membership
+	}
+	return r
+}
+
+func (r *groupRepo) GetMembership(_ context.Context, groupId string, userId string) (*common.Membership, error) {
+	membership, ok := r.memberships[common.MembershipId(groupId, userId)]
+	if !ok {
+		return nil, nil
+	}
+	return &membership, nil
+}
+
+func (r *groupRepo) SaveMembership(_ context.Context, membership common.Membership) error {
+	r.memberships[membership.Id] = membership
+	return nil
+}
+
+func (r *groupRepo) DeleteMembership(_ context.Context, groupId string, userId string) error {
+	id := common.MembershipId(groupId, userId)
+	if _, ok := r.memberships[id]; !ok {
+		return common.ErrNotFound
+	}
+	delete(r.memberships, id)
+	return nil
+}
+
+func (r *groupRepo) HandOverGroup(_ context.Context, groupId string, ownerId string, memberId string) error {
+	owner, member := r.memberships[common.MembershipId(groupId, ownerId)], r.memberships[common.MembershipId(groupId, memberId)]
+	if owner.Role != common.GroupOwner || !owner.Active() || !member.Active() {
+		return common.ErrConflict
+	}
+	owner.Role, member.Role = common.GroupAdmin, common.GroupOwner
+	r.memberships[owner.Id], r.memberships[member.Id] = owner, member
+	return nil
+}
+
+func (r *groupRepo) UpdateGroup(context.Context, common.Group) error {
+	return nil
+}
+
+func (r *groupRepo) SaveGroupSpot(_ context.Context, spot common.GroupSpot) error {
+	r.spots[spot.Id] = spot
+	return nil
+}
+
+func (r *groupRepo) DeleteGroupSpot(_ context.Context, _ string, spotId string) error {
+	delete(r.spots, spotId)
+	return nil
+}
+
+// clubMembers are the members of the group "club": the owner olga, the admin adam, the member mia and the
+// invited ida.
+func clubMembers() *groupRepo {
+	return newGroupRepo(
+		common.Membership{GroupId: "club", UserId: "olga", Role: common.GroupOwner, Status: common.MembershipActive},
+		common.Membership{GroupId: "club", UserId: "adam", Role: common.GroupAdmin, Status: common.MembershipActive},
+		common.Membership{GroupId: "club", UserId: "mia", Role: common.GroupMember, Status: common.MembershipActive},
+		common.Membership{GroupId: "club", UserId: "ida", Role: common.GroupMember, Status: common.MembershipInvited},
+	)
+}
+
+func serveGroup(repo *groupRepo, handler func(Service, *gin.Context), target string, body string) int {
+	gin.SetMode(gin.TestMode)
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	handler(Service{Repo: repo}, c)
+	return recorder.Code
+}
+
+func TestGroupEditPermissions(t *testing.T) {
+
+	t.Parallel()
+
+	type test struct {
+		handler func(Service, *gin.Context)
+		target  string
+		body    string
+	}
+
+	cases := map[string]test{
+		"update group":      {handler: Service.UpdateGroup, target: "/updateGroup?groupId=club", body: `{"name":"Anglers"}`},
+		"save group spot":   {handler: Service.SaveGroupSpot, target: "/saveGroupSpot?groupId=club", body: `{"marker":{"title":"Jetty","coordinates":{"latitude":53.5,"longitude":10}}}`},
+		"delete group spot": {handler: Service.DeleteGroupSpot, target: "/deleteGroupSpot?groupId=club&spotId=jetty"},
+	}
+	expected := map[string]int{"olga": http.StatusOK, "adam": http.StatusOK, "mia": http.StatusForbidden, "ida": http.StatusNotFound, "nina": http.StatusNotFound}
+
+	for name, tc := range cases {
+
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			for userId, code := range expected {
+				assert.Equal(t, code, serveGroup(clubMembers(), tc.handler, tc.target+"&userId="+userId, tc.body), userId)
+			}
+		})
+	}
+}
+
+func TestRemoveGroupMember(t *testing.T) {
+
+	t.Parallel()
+
+	type test struct {
+		userId   string
+		memberId string
+		code     int
+	}
+
+	cases := map[string]test{
+		"admin removes member":     {userId: "adam", memberId: "mia", code: http.StatusOK},
+		"admin removes invitation": {userId: "adam", memberId: "ida", code: http.StatusOK},
+		"owner removes admin":      {userId: "olga", memberId: "adam", code: http.StatusOK},
+		"admin removes owner":      {userId: "adam", memberId: "olga", code: http.StatusForbidden},
+		"member removes member":    {userId: "mia", memberId: "ida", code: http.StatusForbidden},
+		"owner removes non-member": {userId: "olga", memberId: "nina", code: http.StatusNotFound},
+		"invited removes a member": {userId: "ida", memberId: "mia", code: http.StatusNotFound},
+		"admin removes itself":     {userId: "adam", memberId: "adam", code: http.StatusForbidden},
+	}
+
+	for name, tc := range cases {
+
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := clubMembers()
+			code := serveGroup(repo, Service.RemoveGroupMember, "/removeGroupMember?groupId=club&userId="+tc.userId+"&memberId="+tc.memberId, "")
+			assert.Equal(t, tc.code, code)
+
+			_, kept := repo.memberships[common.MembershipId("club", tc.memberId)]
+			assert.Equal(t, tc.code != http.StatusOK && tc.memberId != "nina", kept)
+		})
+	}
+}
+
+func TestSetGroupRole(t *testing.T) {
+
+	t.Parallel()
+
+	repo := clubMembers()
+	assert.Equal(t, http.StatusForbidden, serveGroup(repo, Service.SetGroupRole, "/setGroupRole?groupId=club&userId=adam&memberId=mia", `{"role":"admin"}`))
+	assert.Equal(t, http.StatusForbidden, serveGroup(repo, Service.SetGroupRole, "/setGroupRole?groupId=club&userId=olga&memberId=ida", `{"role":"admin"}`))
+	assert.Equal(t, http.StatusBadRequest, serveGroup(repo, Service.SetGroupRole, "/setGroupRole?groupId=club&userId=olga&memberId=mia", `{"role":"captain"}`))
+
+	assert.Equal(t, http.StatusOK, serveGroup(repo, Service.SetGroupRole, "/setGroupRole?groupId=club&userId=olga&memberId=mia", `{"role":"admin"}`))
+	assert.Equal(t, common.GroupAdmin, repo.memberships["club/mia"].Role)
+	assert.Equal(t, common.GroupOwner, repo.memberships["club/olga"].Role)
+
+	// handing the group over makes the previous owner an admin, who can not give roles anymore
+	assert.Equal(t, http.StatusOK, serveGroup(repo, Service.SetGroupRole, "/setGroupRole?groupId=club&userId=olga&memberId=adam", `{"role":"owner"}`))
+	assert.Equal(t, common.GroupOwner, repo.memberships["club/adam"].Role)
+	assert.Equal(t, common.GroupAdmin, repo.memberships["club/olga"].Role)
+	assert.Equal(t, http.StatusForbidden, serveGroup(repo, Service.SetGroupRole, "/setGroupRole?groupId=club&userId=olga&memberId=mia", `{"role":"member"}`))
+}
+
+func TestAnswerGroupInvitation(t *testing.T) {
+
+	t.Parallel()
+
+	repo := clubMembers()
+	assert.Equal(t, http.StatusOK, serveGroup(repo, Service.AcceptGroupInvitation, "/acceptGroupInvitation?groupId=club&userId=ida", ""))
+	assert.Equal(t, common.MembershipActive, repo.memberships["club/ida"].Status)
+	assert.Equal(t, common.GroupMember, repo.memberships["club/ida"].Role)
+	assert.Equal(t, http.StatusNotFound, serveGroup(repo, Service.AcceptGroupInvitation, "/acceptGroupInvitation?groupId=club&userId=ida", ""))
+
+	repo = clubMembers()
+	assert.Equal(t, http.StatusOK, serveGroup(repo, Service.DeclineGroupInvitation, "/declineGroupInvitation?groupId=club&userId=ida", ""))
+	assert.NotContains(t, repo.memberships, "club/ida")
+
+	// members and users without an invitation have nothing to answer
+	assert.Equal(t, http.StatusNotFound, serveGroup(repo, Service.DeclineGroupInvitation, "/declineGroupInvitation?groupId=club&userId=mia", ""))
+	assert.Contains(t, repo.memberships, "club/mia")
+	assert.Equal(t, http.StatusNotFound, serveGroup(repo, Service.AcceptGroupInvitation, "/acceptGroupInvitation?groupId=club&userId=nina", ""))
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"errors"
+	"fishfishes_backend/common"
+	"fishfishes_backend/importer"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const maxImportSize int64 = 10 << 20
+
+// ImportSpots reads spots from a GeoJSON, GPX, KML or CSV file, either sent as body or as multipart field
+// "file". By default it only reports what would be imported; with dryRun=false the new spots are saved.
+// Spots which duplicate an existing spot or an earlier row of the file are skipped.
+func (s Service) ImportSpots(c *gin.Context) {
+	id := c.Query("userId")
+	if len(id) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID"})
+		return
+	}
+
+	dryRun := true
+	if value := c.Query("dryRun"); len(value) > 0 {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "dryRun must be true or false"})
+			return
+		}
+		dryRun = parsed
+	}
+
+	mapping := importer.CSVMapping{
+		Latitude:  c.Query("latColumn"),
+		Longitude: c.Query("lonColumn"),
+		Title:     c.Query("titleColumn"),
+	}
+	if delimiter := c.Query("delimiter"); len(delimiter) > 0 {
+		r, size := utf8.DecodeRuneInString(delimiter)
+		if size != len(delimiter) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "delimiter must be a single character"})
+			return
+		}
+		mapping.Delimiter = r
+	}
+
+	body, ok := importFile(c)
+	if !ok {
+		return
+	}
+	defer body.Close()
+
+	rows, err := importer.Read(body, c.Query("format"), mapping)
+	if err != nil {
+		respondBodyError(c, err)
+		return
+	}
+
+	existing, err := s.Repo.GetAllSpots(c, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, spots := importReport(rows, *existing)
+	report.DryRun = dryRun
+
+	if !dryRun && len(spots) > 0 {
+		species, err := s.speciesIndex(c)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		// imported spots are stored like saved ones, with ids, metric units and linked species
+		for i := range spots {
+			if err := s.prepareCatches(c, &spots[i], species); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+		}
+
+		err = s.Repo.SaveSpots(c, id, spots)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		report.Imported = len(spots)
+
+		if keys := speciesKeys(spots); len(keys) > 0 {
+			_, err = s.updateRecords(c, id, append(*existing, spots...), keys...)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+		}
+	}
+
+	c.IndentedJSON(http.StatusOK, report)
+}
+
+// importFile returns the file sent as multipart field "file" or as body. The size limit is set before the
+// multipart form is parsed, so that neither way reads more than maxImportSize bytes. It responds with an
+// error and returns false, if the file can not be read.
+func importFile(c *gin.Context) (io.ReadCloser, bool) {
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+	if !strings.HasPrefix(c.ContentType(), "multipart/") {
+		return c.Request.Body, true
+	}
+
+	err := c.Request.ParseMultipartForm(maxImportSize)
+	if err != nil {
+		respondBodyError(c, err)
+		return nil, false
+	}
+	file, _, err := c.Request.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Found no file"})
+		return nil, false
+	}
+
+	return file, true
+}
+
+// respondBodyError responds with 413 if the body exceeded its size limit and with 400 otherwise.
+func respondBodyError(c *gin.Context, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("the file must not be larger than %d bytes", tooLarge.Limit)})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
+
+// importReport checks each row and returns the report together with the spots to save.
+func importReport(rows []importer.Row, existing []common.Fish_spot) (common.ImportReport, []common.Fish_spot) {
+
+	report := common.ImportReport{Total: len(rows), Rows: make([]common.ImportRow, 0, len(rows))}
+	var spots []common.Fish_spot
+
+	for _, row := range rows {
+		result := common.ImportRow{Row: row.Number, Marker: row.Marker}
+
+		if len(row.Error) > 0 {
+			result.Errors = common.ValidationErrors{{Field: "row", Code: common.ErrorInvalid, Message: row.Error}}
+		} else {
+			result.Errors = row.Marker.Validate("marker")
+		}
+
+		if len(result.Errors) > 0 {
+			result.Status = common.ImportStatusError
+			report.Failed++
+		} else if i := importer.FindDuplicate(row.Marker, existing); i >= 0 {
+			result.Status = common.ImportStatusDuplicate
+			result.DuplicateOf = existing[i].Id
+			report.Duplicates++
+		} else if i := importer.FindDuplicate(row.Marker, spots); i >= 0 {
+			result.Status = common.ImportStatusDuplicate
+			result.DuplicateOf = spots[i].Id
+			report.Duplicates++
+		} else {
+			result.Status = common.ImportStatusNew
+			spotId := uuid.New().String()
+			row.Marker.Id = spotId
+			result.Marker.Id = spotId
+			spots = append(spots, common.Fish_spot{Id: spotId, Marker: row.Marker, Catches: []common.Catch{}})
+		}
+
+		report.Rows = append(report.Rows, result)
+	}
+
+	return report, spots
+}
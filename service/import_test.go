@@ -0,0 +1,59 @@
+package service
+
+import (
+	"bytes"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestImportSpotsTooLarge(t *testing.T) {
+
+	t.Parallel()
+
+	gin.SetMode(gin.TestMode)
+	oversized := strings.Repeat("x", int(maxImportSize)+1)
+
+	multipartBody := func() (*bytes.Buffer, string) {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		part, _ := writer.CreateFormFile("file", "spots.csv")
+		_, _ = part.Write([]byte(oversized))
+		_ = writer.Close()
+		return &body, writer.FormDataContentType()
+	}
+
+	type test struct {
+		body        func() (*bytes.Buffer, string)
+		contentType string
+	}
+
+	cases := map[string]test{
+		"multipart": {body: multipartBody},
+		"raw body": {body: func() (*bytes.Buffer, string) {
+			return bytes.NewBufferString("lat,lon,title\n" + oversized), "text/csv"
+		}},
+	}
+
+	for name, tc := range cases {
+
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			body, contentType := tc.body()
+			recorder := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(recorder)
+			c.Request = httptest.NewRequest(http.MethodPost, "/importSpots?userId=anna&format=csv", body)
+			c.Request.Header.Set("Content-Type", contentType)
+
+			Service{}.ImportSpots(c)
+			assert.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package service
+
+import "context"
+
+// Migrate runs the data migration of the given name, unless it was done before, and marks it as done. It
+// returns false if the migration was skipped. A failed migration is not marked and runs again next time.
+func (s Service) Migrate(ctx context.Context, name string, migrate func(ctx context.Context) error) (bool, error) {
+
+	done, err := s.Repo.IsMigrated(ctx, name)
+	if err != nil || done {
+		return false, err
+	}
+
+	err = migrate(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return true, s.Repo.SetMigrated(ctx, name)
+}
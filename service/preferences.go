@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"fishfishes_backend/common"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func (s Service) GetPreferences(c *gin.Context) {
+	id := c.Query("userId")
+	if len(id) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID"})
+		return
+	}
+
+	preferences, err := s.Repo.GetPreferences(c, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, preferences)
+}
+
+func (s Service) SavePreferences(c *gin.Context) {
+	id := c.Query("userId")
+	if len(id) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID"})
+		return
+	}
+
+	var preferences common.UserPreferences
+	if err := c.ShouldBindJSON(&preferences); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "JSON Parse Error", "details": err.Error()})
+		return
+	}
+
+	if !common.IsUnitSystem(preferences.UnitSystem) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "fields": common.ValidationErrors{{
+			Field:   "unitSystem",
+			Code:    common.ErrorUnknownUnit,
+			Message: "unitSystem must be metric or imperial",
+		}}})
+		return
+	}
+
+	err := s.Repo.SavePreferences(c, id, preferences)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "saved"})
+}
+
+// unitSystem returns the preferred unit system of the user.
+func (s Service) unitSystem(ctx context.Context, userId string) (string, error) {
+	preferences, err := s.Repo.GetPreferences(ctx, userId)
+	if err != nil {
+		return "", err
+	}
+	return preferences.UnitSystem, nil
+}
+
+// toUnitSystem converts the catches of the spots to the given unit system.
+func toUnitSystem(spots []common.Fish_spot, system string) error {
+	for i := range spots {
+		err := convertCatches(&spots[i], system)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func convertCatches(spot *common.Fish_spot, system string) error {
+	for i := range spot.Catches {
+		catch, err := spot.Catches[i].ToUnitSystem(system)
+		if err != nil {
+			return err
+		}
+		spot.Catches[i] = catch
+	}
+	return nil
+}
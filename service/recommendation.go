@@ -0,0 +1,151 @@
+package service
+
+import (
+	"fishfishes_backend/common"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+const (
+	maxRecommendations int     = 5
+	timeWeight         float64 = 1.0 // additional weight of fish caught at the requested time of day
+	depthWeight        float64 = 1.0 // additional weight of fish caught in the requested depth range
+)
+
+func (s Service) GetRecommendations(c *gin.Context) {
+	id := c.Query("userId")
+	fish := c.Query("fish")
+	if len(id) == 0 || len(fish) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID or fish"})
+		return
+	}
+
+	query := common.RecommendationQuery{
+		Fish:   fish,
+		SpotId: c.Query("spotId"),
+		Time:   c.Query("time"),
+	}
+
+	if deep := c.Query("deep"); len(deep) > 0 {
+		value, err := strconv.ParseFloat(deep, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "deep must be a number"})
+			return
+		}
+		value, err = common.ConvertDepth(value, c.Query("deepUnit"), common.UnitMeter)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		query.Deep = &value
+	}
+
+	if len(query.SpotId) > 0 {
+		spot, err := s.findSpot(c, id, query.SpotId)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if spot == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No spot found"})
+			return
+		}
+		query.Coordinates = &spot.Marker.Coordinates
+	}
+
+	own, err := s.Repo.GetEquipmentStats(c, id, query, false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	recommendations := rankRecommendations(own, query, common.RecommendationSourceOwn)
+
+	if len(recommendations) < maxRecommendations {
+		public, err := s.Repo.GetEquipmentStats(c, id, query, true)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		recommendations = appendMissing(recommendations, rankRecommendations(public, query, common.RecommendationSourcePublic))
+	}
+
+	if len(recommendations) > maxRecommendations {
+		recommendations = recommendations[:maxRecommendations]
+	}
+
+	c.IndentedJSON(http.StatusOK, recommendations)
+}
+
+// rankRecommendations scores each equipment by the number of fish caught with it. Fish caught at the requested
+// time of day or depth count additionally, so setups which worked under the same conditions are ranked first.
+func rankRecommendations(stats []common.EquipmentStats, query common.RecommendationQuery, source string) []common.Recommendation {
+
+	var recommendations []common.Recommendation
+	for _, stat := range stats {
+		if stat.Fish <= 0 {
+			continue
+		}
+		recommendations = append(recommendations, common.Recommendation{
+			Equipment:   stat.Equipment,
+			Source:      source,
+			Score:       float64(stat.Fish) + timeWeight*float64(stat.FishAtTime) + depthWeight*float64(stat.FishAtDepth),
+			Catches:     stat.Catches,
+			Fish:        stat.Fish,
+			FishAtTime:  stat.FishAtTime,
+			FishAtDepth: stat.FishAtDepth,
+			Explanation: explain(stat, query, source),
+		})
+	}
+
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		if recommendations[i].Score != recommendations[j].Score {
+			return recommendations[i].Score > recommendations[j].Score
+		}
+		return recommendations[i].Catches > recommendations[j].Catches
+	})
+
+	return recommendations
+}
+
+// appendMissing appends the fallback recommendations whose equipment is not recommended yet.
+func appendMissing(recommendations []common.Recommendation, fallback []common.Recommendation) []common.Recommendation {
+	for _, candidate := range fallback {
+		known := false
+		for _, recommendation := range recommendations {
+			if sameEquipment(recommendation.Equipment, candidate.Equipment) {
+				known = true
+				break
+			}
+		}
+		if !known {
+			recommendations = append(recommendations, candidate)
+		}
+	}
+	return recommendations
+}
+
+// sameEquipment compares the text of two equipments.
+func sameEquipment(a, b common.Equipment) bool {
+	return a.Name == b.Name && a.Bait == b.Bait && a.Leader == b.Leader
+}
+
+func explain(stat common.EquipmentStats, query common.RecommendationQuery, source string) string {
+
+	owner := "your"
+	if source == common.RecommendationSourcePublic {
+		owner = "other anglers'"
+	}
+
+	explanation := fmt.Sprintf("%d %s in %d of %s catches", stat.Fish, query.Fish, stat.Catches, owner)
+	if len(query.Time) > 0 {
+		explanation += fmt.Sprintf(", %d of them at %s", stat.FishAtTime, query.Time)
+	}
+	if query.Deep != nil {
+		explanation += fmt.Sprintf(", %d of them at a similar depth", stat.FishAtDepth)
+	}
+	return explanation
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"fishfishes_backend/common"
+	"fishfishes_backend/records"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+// GetRecords returns the personal records of the user per species with their history, optionally only of
+// the species given by its id or name. Sizes and weights are in the unit system of the user.
+func (s Service) GetRecords(c *gin.Context) {
+	id := c.Query("userId")
+	if len(id) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID"})
+		return
+	}
+
+	stored, err := s.Repo.GetRecords(c, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	species := c.Query("species")
+	result := []common.PersonalRecords{}
+	for _, r := range stored {
+		if len(species) == 0 || r.Species == species || r.Fish == species {
+			result = append(result, r)
+		}
+	}
+
+	system, err := s.unitSystem(c, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	convertRecords(result, system)
+
+	c.IndentedJSON(http.StatusOK, result)
+}
+
+// MigrateRecords computes and stores the records of all users from the catches logged before the records
+// were introduced. It returns the number of users with catches.
+func (s Service) MigrateRecords(ctx context.Context) (int, error) {
+	users, err := s.Repo.GetSpotUserIds(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, userId := range users {
+		spots, err := s.Repo.GetAllSpots(ctx, userId)
+		if err != nil {
+			return 0, err
+		}
+
+		_, err = s.updateRecords(ctx, userId, *spots, speciesKeys(*spots)...)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(users), nil
+}
+
+// speciesKeys returns the record keys of the species of all catches of the spots.
+func speciesKeys(spots []common.Fish_spot) []string {
+	var keys []string
+	for _, spot := range spots {
+		for _, catch := range spot.Catches {
+			keys = append(keys, records.SpeciesKey(catch))
+		}
+	}
+	return keys
+}
+
+// updateRecords recomputes the records of the given species from all spots of the user, as they are after
+// a change of catches, and returns the changed records.
+func (s Service) updateRecords(ctx context.Context, userId string, spots []common.Fish_spot, species ...string) ([]common.RecordChange, error) {
+
+	stored, err := s.Repo.GetRecords(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, changes := records.Update(stored, spots, species, s.location, time.Now().UTC())
+	if len(updated) > 0 {
+		err = s.Repo.SaveRecords(ctx, userId, updated)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if changes == nil {
+		changes = []common.RecordChange{}
+	}
+	return changes, nil
+}
+
+// convertRecords converts the sizes and weights of the records, which are in cm and kg, to the given unit system.
+func convertRecords(list []common.PersonalRecords, system string) {
+	convert := func(r *common.Record) {
+		switch r.Kind {
+		case common.RecordLargest:
+			r.Value, _ = common.ConvertSize(r.Value, common.UnitCentimeter, common.SizeUnitOf(system))
+			r.Unit = common.SizeUnitOf(system)
+		case common.RecordHeaviest:
+			r.Value, _ = common.ConvertWeight(r.Value, common.UnitKilogram, common.WeightUnitOf(system))
+			r.Unit = common.WeightUnitOf(system)
+		}
+	}
+
+	for i := range list {
+		for j := range list[i].Records {
+			convert(&list[i].Records[j])
+		}
+		for j := range list[i].History {
+			change := &list[i].History[j]
+			if change.Previous != nil {
+				previous := common.Record{Kind: change.Kind, Value: *change.Previous}
+				convert(&previous)
+				change.Previous = &previous.Value
+			}
+			convert(&change.Record)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"fishfishes_backend/common"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// GetRules returns the rules of the regions at a spot of the user, given by spotId, or at the coordinates
+// latitude and longitude. The optional speciesId restricts the rules to a species. Sizes are in the unit
+// system of the user.
+func (s Service) GetRules(c *gin.Context) {
+	userId := c.Query("userId")
+	if len(userId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID"})
+		return
+	}
+
+	var coordinates common.Coordinates
+	if spotId := c.Query("spotId"); len(spotId) > 0 {
+		spot, err := s.findSpot(c, userId, spotId)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if spot == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No spot found"})
+			return
+		}
+		coordinates = spot.Marker.Coordinates
+	} else {
+		latitude, latErr := strconv.ParseFloat(c.Query("latitude"), 64)
+		longitude, lonErr := strconv.ParseFloat(c.Query("longitude"), 64)
+		coordinates = common.Coordinates{Latitude: latitude, Longitude: longitude}
+		if latErr != nil || lonErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "spotId or latitude and longitude must be given"})
+			return
+		}
+		if errs := coordinates.Validate(""); len(errs) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "fields": errs})
+			return
+		}
+	}
+
+	rules := []common.RegionRules{}
+	if s.Regulations != nil {
+		rules = s.Regulations.RulesAt(coordinates, c.Query("speciesId"))
+	}
+
+	system, err := s.unitSystem(c, userId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	convertRules(rules, common.SizeUnitOf(system))
+
+	c.IndentedJSON(http.StatusOK, rules)
+}
+
+// checkRegulations evaluates the catches of the spot at the given indexes against the rules of its region.
+// Other spots of the user count towards the bag limits.
+func (s Service) checkRegulations(spot common.Fish_spot, indexes []int, others []common.Fish_spot) []common.RegulationWarning {
+	warnings := []common.RegulationWarning{}
+	if s.Regulations == nil {
+		return warnings
+	}
+	return append(warnings, s.Regulations.Check(spot, indexes, others, time.Now().UTC(), s.location(spot.Marker.Coordinates))...)
+}
+
+// allCatches returns the indexes of all catches of the spot.
+func allCatches(spot common.Fish_spot) []int {
+	indexes := make([]int, len(spot.Catches))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	return indexes
+}
+
+// convertRules converts the sizes of the rules, which are in cm, to the given unit.
+func convertRules(regions []common.RegionRules, sizeUnit string) {
+	convert := func(value *float64) *float64 {
+		if value == nil {
+			return nil
+		}
+		converted, _ := common.ConvertSize(*value, common.UnitCentimeter, sizeUnit)
+		return &converted
+	}
+
+	for i := range regions {
+		regions[i].SizeUnit = sizeUnit
+		for j := range regions[i].Rules {
+			rule := &regions[i].Rules[j]
+			rule.MinSize = convert(rule.MinSize)
+			rule.MaxSize = convert(rule.MaxSize)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"fishfishes_backend/common"
+	"fishfishes_backend/common/geo"
+	"fishfishes_backend/feed"
+	"fishfishes_backend/search"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultSearchLimit int = 20
+	maxSearchLimit     int = 100
+)
+
+// SearchSpots searches the spots the user can see for the words of the query parameter q and returns them
+// ranked by relevance, with a highlighted snippet for every matching field.
+func (s Service) SearchSpots(c *gin.Context) {
+	id := c.Query("userId")
+	if len(id) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID"})
+		return
+	}
+
+	query := strings.TrimSpace(c.Query("q"))
+	if len(search.Terms(query)) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q must contain at least one word"})
+		return
+	}
+
+	limit := defaultSearchLimit
+	if value := c.Query("limit"); len(value) > 0 {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxSearchLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a number between 1 and " + strconv.Itoa(maxSearchLimit)})
+			return
+		}
+		limit = parsed
+	}
+
+	// catches name their species in any language, so the names of the searched species are added
+	species, err := s.Repo.GetSpecies(c, "")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	terms := search.Terms(query)
+	expanded := search.ExpandSpecies(terms, species)
+	terms = append(terms, expanded...)
+	if len(expanded) > 0 {
+		query += " " + strings.Join(expanded, " ")
+	}
+
+	scope, err := s.searchScope(c, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	hits, err := s.Repo.SearchSpots(c, scope, query, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	groupHits, err := s.Repo.SearchGroupSpots(c, scope.GroupIds, query, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	results := []common.SearchResult{}
+	for _, hit := range hits {
+		result := common.SearchResult{
+			SpotId:      hit.Spot.Id,
+			UserId:      hit.UserId,
+			Title:       hit.Spot.Marker.Title,
+			Coordinates: hit.Spot.Marker.Coordinates,
+			Score:       hit.Score,
+			Matches:     search.Matches(hit.Spot, terms),
+		}
+		if hit.UserId != id && hit.Spot.HideLocation {
+			result.Coordinates = geo.Obfuscate(result.Coordinates, feed.ObfuscationDegrees)
+			result.Hidden = true
+		}
+		results = append(results, result)
+	}
+	for _, hit := range groupHits {
+		results = append(results, common.SearchResult{
+			SpotId:      hit.Spot.Id,
+			GroupId:     hit.Spot.GroupId,
+			Title:       hit.Spot.Marker.Title,
+			Coordinates: hit.Spot.Marker.Coordinates,
+			Score:       hit.Score,
+			Matches:     search.GroupMatches(hit.Spot, terms),
+		})
+	}
+
+	// both searches are ranked alike, so that the best of both are returned
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	c.IndentedJSON(http.StatusOK, results)
+}
+
+// searchScope returns the spots the user can see: the own ones, those shared by friends, public ones and
+// those of the groups of the user.
+func (s Service) searchScope(ctx context.Context, userId string) (common.SearchScope, error) {
+
+	friends, err := s.friendIds(ctx, userId)
+	if err != nil {
+		return common.SearchScope{}, err
+	}
+	blocked, err := s.Repo.GetFriendships(ctx, userId, common.FriendshipBlocked)
+	if err != nil {
+		return common.SearchScope{}, err
+	}
+
+	groupIds, err := s.groupIds(ctx, userId)
+	if err != nil {
+		return common.SearchScope{}, err
+	}
+
+	scope := common.SearchScope{UserId: userId, FriendIds: friends, GroupIds: groupIds}
+	for _, friendship := range blocked {
+		scope.BlockedIds = append(scope.BlockedIds, friendship.Other(userId))
+	}
+	return scope, nil
+}
@@ -2,10 +2,17 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
+	"fishfishes_backend/astronomy"
 	common "fishfishes_backend/common"
+	"fishfishes_backend/moderation"
+	"fishfishes_backend/regulations"
+	"fishfishes_backend/timezone"
+	"fishfishes_backend/weather"
 	"github.com/gin-gonic/gin"
 )
 
@@ -13,20 +20,100 @@ type Repo interface {
 	CheckLogin(ctx context.Context, user common.User) (bool, string)
 	CreateAccount(ctx context.Context, user common.User) error
 	SaveSpot(ctx context.Context, userId string, spot common.Fish_spot) error
+	SaveSpots(ctx context.Context, userId string, spots []common.Fish_spot) error
+	AddCatch(ctx context.Context, userId string, spotId string, catch common.Catch) error
+	UpdateCatch(ctx context.Context, userId string, spotId string, catch common.Catch) error
+	DeleteCatch(ctx context.Context, userId string, spotId string, catchId string) error
+	GetRecords(ctx context.Context, userId string) ([]common.PersonalRecords, error)
+	SaveRecords(ctx context.Context, userId string, records []common.PersonalRecords) error
 	GetAllSpots(ctx context.Context, id string) (*[]common.Fish_spot, error)
-	GetFishListSalt() []string
-	GetFishListFresh() []string
+	GetSpecies(ctx context.Context, waterType string) ([]common.Species, error)
+	GetSpeciesByID(ctx context.Context, id string) (*common.Species, error)
+	CreateSpecies(ctx context.Context, species common.Species) error
+	UpdateSpecies(ctx context.Context, species common.Species) error
+	DeleteSpecies(ctx context.Context, id string) error
+	GetCatchStatistics(ctx context.Context, userId string, filter common.StatisticsFilter) (*common.CatchStatistics, error)
+	GetEquipmentStats(ctx context.Context, userId string, query common.RecommendationQuery, public bool) ([]common.EquipmentStats, error)
+	UpdateSpots(ctx context.Context, update func(spot *common.Fish_spot, createdAt time.Time) bool) (int, error)
+	GetSpotUserIds(ctx context.Context) ([]string, error)
+	IsMigrated(ctx context.Context, name string) (bool, error)
+	SetMigrated(ctx context.Context, name string) error
+	GetPreferences(ctx context.Context, userId string) (*common.UserPreferences, error)
+	SavePreferences(ctx context.Context, userId string, preferences common.UserPreferences) error
+	SearchSpots(ctx context.Context, scope common.SearchScope, query string, limit int) ([]common.SearchHit, error)
+	SearchGroupSpots(ctx context.Context, groupIds []string, query string, limit int) ([]common.GroupSearchHit, error)
+	GetCatchCounts(ctx context.Context, userId string) (map[string]int, error)
+	GetTackle(ctx context.Context, userId string, itemType string) ([]common.TackleItem, error)
+	CreateTackle(ctx context.Context, userId string, item common.TackleItem) error
+	UpdateTackle(ctx context.Context, userId string, item common.TackleItem) error
+	DeleteTackle(ctx context.Context, userId string, itemId string) error
+	GetTrips(ctx context.Context, userId string, from *time.Time, to *time.Time) ([]common.Trip, error)
+	GetTrip(ctx context.Context, userId string, tripId string) (*common.Trip, error)
+	CreateTrip(ctx context.Context, userId string, trip common.Trip) error
+	UpdateTrip(ctx context.Context, userId string, trip common.Trip) error
+	DeleteTrip(ctx context.Context, userId string, tripId string) error
+	GetTripEfforts(ctx context.Context, userId string, from *time.Time, to *time.Time) ([]common.TripEffort, error)
+	GetUserNames(ctx context.Context, userIds []string) (map[string]string, error)
+	GetFriendship(ctx context.Context, userId string, otherId string) (*common.Friendship, error)
+	SaveFriendship(ctx context.Context, friendship common.Friendship) error
+	DeleteFriendship(ctx context.Context, id string) error
+	GetFriendships(ctx context.Context, userId string, status string) ([]common.Friendship, error)
+	AddActivities(ctx context.Context, activities []common.Activity) error
+	GetFeed(ctx context.Context, userIds []string, cursor *common.FeedCursor, limit int) ([]common.FeedEntry, error)
+	SetSpotVisibility(ctx context.Context, userId string, spotId string, visibility string, hideLocation bool) error
+	GetComments(ctx context.Context, target common.Target, cursor *common.Cursor, limit int) ([]common.Comment, error)
+	GetReplies(ctx context.Context, commentIds []string) ([]common.Comment, error)
+	GetComment(ctx context.Context, commentId string) (*common.Comment, error)
+	CreateComment(ctx context.Context, comment common.Comment) error
+	UpdateComment(ctx context.Context, commentId string, text string, flagged bool, editedAt time.Time) error
+	DeleteComment(ctx context.Context, commentId string) error
+	SaveReaction(ctx context.Context, reaction common.Reaction) error
+	DeleteReaction(ctx context.Context, target common.Target, userId string) error
+	GetReactionCounts(ctx context.Context, ownerId string) ([]common.ReactionCount, error)
+	DeleteTarget(ctx context.Context, target common.Target) error
+	CreateGroup(ctx context.Context, group common.Group, owner common.Membership) error
+	GetGroups(ctx context.Context, groupIds []string) ([]common.Group, error)
+	UpdateGroup(ctx context.Context, group common.Group) error
+	DeleteGroup(ctx context.Context, groupId string) error
+	GetMembership(ctx context.Context, groupId string, userId string) (*common.Membership, error)
+	SaveMembership(ctx context.Context, membership common.Membership) error
+	DeleteMembership(ctx context.Context, groupId string, userId string) error
+	HandOverGroup(ctx context.Context, groupId string, ownerId string, memberId string) error
+	GetMemberships(ctx context.Context, userId string) ([]common.Membership, error)
+	GetGroupMembers(ctx context.Context, groupId string) ([]common.Membership, error)
+	GetGroupSpots(ctx context.Context, groupIds []string) ([]common.GroupSpot, error)
+	SaveGroupSpot(ctx context.Context, spot common.GroupSpot) error
+	DeleteGroupSpot(ctx context.Context, groupId string, spotId string) error
+	GetCompetitions(ctx context.Context, userId string, groupIds []string, from *time.Time, to *time.Time) ([]common.Competition, error)
+	GetCompetition(ctx context.Context, competitionId string) (*common.Competition, error)
+	CreateCompetition(ctx context.Context, competition common.Competition) error
+	UpdateCompetition(ctx context.Context, competition common.Competition) error
+	DeleteCompetition(ctx context.Context, organizerId string, competitionId string) error
+	GetSubmissions(ctx context.Context, competitionId string) ([]common.Submission, error)
+	CreateSubmission(ctx context.Context, submission common.Submission) error
+	DeleteSubmission(ctx context.Context, userId string, submissionId string) error
 }
 
 const VERSION string = "0.0.1"
 
 type Service struct {
-	Repo Repo
+	Repo        Repo
+	Weather     weather.Provider
+	Zones       *timezone.Resolver
+	Regulations *regulations.Book
+	Moderator   moderation.Moderator
 }
 
-func NewService(repo Repo) Service {
+// NewService creates the service. The weather provider and the regulations are optional, without them catches
+// are stored without conditions and without checking the rules. Comments are published unmoderated until
+// another Moderator is set.
+func NewService(repo Repo, weatherProvider weather.Provider, zones *timezone.Resolver, book *regulations.Book) Service {
 	return Service{
-		Repo: repo,
+		Repo:        repo,
+		Weather:     weatherProvider,
+		Zones:       zones,
+		Regulations: book,
+		Moderator:   moderation.AllowAll{},
 	}
 }
 
@@ -46,6 +133,19 @@ func (s Service) GetAllSpots(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+
+	system, err := s.unitSystem(c, id)
+	if err == nil {
+		err = toUnitSystem(*spots, system)
+	}
+	if err == nil {
+		err = s.attachReactions(c, id, *spots)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
 	c.IndentedJSON(http.StatusOK, spots)
 }
 
@@ -67,6 +167,16 @@ func (s Service) GetAllSpotCoordinates(c *gin.Context) {
 		markers = append(markers, marker)
 	}
 
+	// the spots of the groups come with the group as layer, so that the app can toggle them
+	if c.Query("groups") == "true" {
+		groupMarkers, err := s.groupMarkers(c, id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		markers = append(markers, groupMarkers...)
+	}
+
 	c.IndentedJSON(http.StatusOK, markers)
 }
 
@@ -85,7 +195,19 @@ func (s Service) GetSpotByID(c *gin.Context) {
 
 	for _, spot := range *spots {
 		if strings.Compare(spot.Id, spotId) == 0 {
-			c.IndentedJSON(http.StatusOK, spot)
+			system, err := s.unitSystem(c, userId)
+			if err == nil {
+				err = convertCatches(&spot, system)
+			}
+			found := []common.Fish_spot{spot}
+			if err == nil {
+				err = s.attachReactions(c, userId, found)
+			}
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			c.IndentedJSON(http.StatusOK, found[0])
 			return
 		}
 	}
@@ -101,26 +223,171 @@ func (s Service) SaveSpot(c *gin.Context) {
 	}
 
 	var spot common.Fish_spot
-	if err := c.BindJSON(&spot); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "JSON Parse Error"})
+	if err := c.ShouldBindJSON(&spot); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "JSON Parse Error", "details": err.Error()})
 		return
 	}
 
-	err := s.Repo.SaveSpot(c, id, spot)
+	species, err := s.speciesIndex(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 
+	errs := spot.Validate(species.Catalog())
+	unknown, err := s.linkTackle(c, id, spot.Catches, func(i int) string { return fmt.Sprintf("catches[%d].equipment", i) })
 	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errs = append(errs, unknown...); len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "fields": errs})
+		return
+	}
+
+	if err := s.prepareCatches(c, &spot, species); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	existing, err := s.Repo.GetAllSpots(c, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = s.Repo.SaveSpot(c, id, spot)
+
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	changes, err := s.updateRecords(c, id, append(*existing, spot), speciesKeys([]common.Fish_spot{spot})...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	newSpot, newCatches := newInSpot(*existing, spot)
+	err = s.recordActivities(c, id, spot, newSpot, newCatches)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
 	// Return response with updated user data
-	c.JSON(http.StatusOK, gin.H{"status": "saved"})
+	c.JSON(http.StatusOK, gin.H{"status": "saved", "warnings": s.checkRegulations(spot, allCatches(spot), *existing), "records": changes})
+}
+
+// newInSpot tells whether the saved spot is new among the existing spots of the user and returns its
+// catches not logged before.
+func newInSpot(existing []common.Fish_spot, spot common.Fish_spot) (bool, []common.Catch) {
+	newSpot := true
+	logged := map[string]bool{}
+	for _, other := range existing {
+		newSpot = newSpot && other.Id != spot.Id
+		for _, catch := range other.Catches {
+			logged[catch.Id] = true
+		}
+	}
+
+	var catches []common.Catch
+	for _, catch := range spot.Catches {
+		if !logged[catch.Id] {
+			catches = append(catches, catch)
+		}
+	}
+	return newSpot, catches
+}
+
+// prepareCatches gives new catches an id, links the catches of the spot to the species catalog, converts them to metric units and
+// completes their times, weather and moon phases, as they are stored.
+func (s Service) prepareCatches(ctx context.Context, spot *common.Fish_spot, species common.SpeciesIndex) error {
+
+	assignCatchIds(spot)
+	normalizeSpecies(spot, species)
+
+	// catches are stored in metric units, whatever unit they were entered in
+	if err := convertCatches(spot, common.UnitSystemMetric); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	s.resolveCatchTimes(spot)
+	s.attachWeather(ctx, spot, now)
+	attachMoonPhase(spot, now)
+	return nil
+}
+
+// findSpot returns the spot of the user with the given id or nil, if the user has no such spot.
+func (s Service) findSpot(ctx context.Context, userId string, spotId string) (*common.Fish_spot, error) {
+	spots, err := s.Repo.GetAllSpots(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, spot := range *spots {
+		if strings.Compare(spot.Id, spotId) == 0 {
+			return &spot, nil
+		}
+	}
+
+	return nil, nil
 }
 
-func (s Service) GetFishListSalt(c *gin.Context) {
-	c.IndentedJSON(http.StatusOK, s.Repo.GetFishListSalt())
+// speciesIndex returns the index of the names of the species catalog.
+func (s Service) speciesIndex(ctx context.Context) (common.SpeciesIndex, error) {
+	species, err := s.Repo.GetSpecies(ctx, "")
+	if err != nil {
+		return common.SpeciesIndex{}, err
+	}
+	return common.NewSpeciesIndex(species), nil
 }
 
-func (s Service) GetFishListFresh(c *gin.Context) {
-	c.IndentedJSON(http.StatusOK, s.Repo.GetFishListFresh())
+// normalizeSpecies links the catches to the species catalog. Catches of species missing in the catalog keep
+// their name as free text.
+func normalizeSpecies(spot *common.Fish_spot, species common.SpeciesIndex) {
+	for i := range spot.Catches {
+		species.Link(&spot.Catches[i])
+	}
+}
+
+// attachWeather looks up the conditions at the spot for all catches without conditions, at the time of the
+// catch or the given time, if the catch has no timestamp. Failed lookups are left empty and filled later by
+// the weather backfill.
+func (s Service) attachWeather(ctx context.Context, spot *common.Fish_spot, at time.Time) {
+	if s.Weather == nil {
+		return
+	}
+
+	for i := range spot.Catches {
+		if spot.Catches[i].Weather != nil {
+			continue
+		}
+		conditions, err := s.Weather.Conditions(ctx, spot.Marker.Coordinates, catchTime(spot.Catches[i], at))
+		if err != nil {
+			continue
+		}
+		spot.Catches[i].Weather = conditions
+	}
+}
+
+// attachMoonPhase stores the moon phase on all catches without one, at the time of the catch or the given time.
+func attachMoonPhase(spot *common.Fish_spot, at time.Time) {
+	for i := range spot.Catches {
+		if spot.Catches[i].MoonPhase == nil {
+			phase := astronomy.Phase(catchTime(spot.Catches[i], at))
+			spot.Catches[i].MoonPhase = &phase
+		}
+	}
+}
+
+// catchTime returns the timestamp of the catch or the fallback, if it has none.
+func catchTime(catch common.Catch, fallback time.Time) time.Time {
+	if catch.CaughtAt != nil {
+		return *catch.CaughtAt
+	}
+	return fallback
 }
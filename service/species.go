@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"fishfishes_backend/common"
+	"fishfishes_backend/search"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// GetFishListSalt returns the names of the saltwater species of the catalog in the locale of the request.
+func (s Service) GetFishListSalt(c *gin.Context) {
+	s.getFishList(c, common.WaterTypeSalt)
+}
+
+// GetFishListFresh returns the names of the freshwater species of the catalog in the locale of the request.
+func (s Service) GetFishListFresh(c *gin.Context) {
+	s.getFishList(c, common.WaterTypeFresh)
+}
+
+func (s Service) getFishList(c *gin.Context, waterType string) {
+	species, err := s.Repo.GetSpecies(c, waterType)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	locales := acceptedLocales(c)
+	names := []string{}
+	for _, fish := range species {
+		names = append(names, fish.LocalizedName(locales))
+	}
+	sort.Strings(names)
+
+	c.IndentedJSON(http.StatusOK, names)
+}
+
+// GetSpecies returns the species catalog with the names in the locale of the request. The optional query
+// parameters restrict it to the water type waterType and to the species with a name in any language or a
+// scientific name containing q.
+func (s Service) GetSpecies(c *gin.Context) {
+	waterType := c.Query("waterType")
+	if len(waterType) > 0 && !common.IsWaterType(waterType) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "waterType must be fresh, salt or brackish"})
+		return
+	}
+
+	species, err := s.Repo.GetSpecies(c, waterType)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	query := c.Query("q")
+	locales := acceptedLocales(c)
+	result := []common.Species{}
+	for _, fish := range species {
+		if len(query) == 0 || fish.Matches(query) {
+			fish.Name = fish.LocalizedName(locales)
+			result = append(result, fish)
+		}
+	}
+
+	c.IndentedJSON(http.StatusOK, result)
+}
+
+func (s Service) GetSpeciesByID(c *gin.Context) {
+	id := c.Query("speciesId")
+	if len(id) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no speciesId"})
+		return
+	}
+
+	species, err := s.Repo.GetSpeciesByID(c, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	species.Name = species.LocalizedName(acceptedLocales(c))
+
+	c.IndentedJSON(http.StatusOK, species)
+}
+
+func (s Service) CreateSpecies(c *gin.Context) {
+	species, ok := bindSpecies(c)
+	if !ok {
+		return
+	}
+
+	err := s.Repo.CreateSpecies(c, species)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"status": "created"})
+}
+
+func (s Service) UpdateSpecies(c *gin.Context) {
+	species, ok := bindSpecies(c)
+	if !ok {
+		return
+	}
+
+	err := s.Repo.UpdateSpecies(c, species)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "saved"})
+}
+
+func (s Service) DeleteSpecies(c *gin.Context) {
+	id := c.Query("speciesId")
+	if len(id) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no speciesId"})
+		return
+	}
+
+	err := s.Repo.DeleteSpecies(c, id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "deleted"})
+}
+
+// bindSpecies parses and validates the species of the request body. It responds with 400 and returns false,
+// if the body is not a valid species.
+func bindSpecies(c *gin.Context) (common.Species, bool) {
+	var species common.Species
+	if err := c.ShouldBindJSON(&species); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "JSON Parse Error", "details": err.Error()})
+		return species, false
+	}
+
+	if errs := species.Validate(); len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "fields": errs})
+		return species, false
+	}
+
+	if species.Aliases == nil {
+		species.Aliases = []string{}
+	}
+	return species, true
+}
+
+// respondError maps the not found and conflict errors of the repository to their status codes, any other
+// error to 500.
+func respondError(c *gin.Context, err error) {
+	switch err {
+	case common.ErrNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case common.ErrConflict:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case common.ErrForbidden:
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// acceptedLocales returns the locales of the Accept-Language header of the request, the most preferred first.
+func acceptedLocales(c *gin.Context) []string {
+	return common.AcceptedLocales(c.GetHeader("Accept-Language"))
+}
+
+const (
+	defaultSuggestions int = 10
+	maxSuggestions     int = 50
+)
+
+// AutocompleteSpecies proposes species for the partially typed name q, tolerating typing errors, ranked by
+// the quality of the match and the number of catches of the species by the user.
+func (s Service) AutocompleteSpecies(c *gin.Context) {
+	id := c.Query("userId")
+	if len(id) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID"})
+		return
+	}
+
+	limit := defaultSuggestions
+	if value := c.Query("limit"); len(value) > 0 {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxSuggestions {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a number between 1 and " + strconv.Itoa(maxSuggestions)})
+			return
+		}
+		limit = parsed
+	}
+
+	species, err := s.Repo.GetSpecies(c, "")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	counts, err := s.Repo.GetCatchCounts(c, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// catches name their species in various ways, the counts are summed up per species
+	index := common.NewSpeciesIndex(species)
+	catches := map[string]int{}
+	for fish, count := range counts {
+		if s, ok := index.Resolve(fish); ok {
+			catches[s.Id] += count
+		}
+	}
+
+	c.IndentedJSON(http.StatusOK, search.Suggest(c.Query("q"), species, catches, acceptedLocales(c), limit))
+}
+
+// MigrateSpecies links all stored catches without species id to the catalog by their names and reports the
+// names which match no species.
+func (s Service) MigrateSpecies(ctx context.Context) (*common.SpeciesMigration, error) {
+
+	species, err := s.speciesIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	migration := &common.SpeciesMigration{Unmatched: []common.UnmatchedSpecies{}}
+	unmatched := map[string]int{}
+
+	_, err = s.Repo.UpdateSpots(ctx, func(spot *common.Fish_spot, createdAt time.Time) bool {
+		changed := false
+		for i := range spot.Catches {
+			catch := &spot.Catches[i]
+			if len(catch.SpeciesId) > 0 {
+				continue
+			}
+			if species.Link(catch) {
+				migration.Linked++
+				changed = true
+			} else {
+				unmatched[catch.Fish]++
+			}
+		}
+		return changed
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for fish, catches := range unmatched {
+		migration.Unmatched = append(migration.Unmatched, common.UnmatchedSpecies{Fish: fish, Catches: catches})
+	}
+	sort.Slice(migration.Unmatched, func(i, j int) bool {
+		if migration.Unmatched[i].Catches != migration.Unmatched[j].Catches {
+			return migration.Unmatched[i].Catches > migration.Unmatched[j].Catches
+		}
+		return migration.Unmatched[i].Fish < migration.Unmatched[j].Fish
+	})
+
+	return migration, nil
+}
+
+// RunSpeciesMigration runs the species migration, e.g. after aliases for unmatched names were added to the catalog.
+func (s Service) RunSpeciesMigration(c *gin.Context) {
+	migration, err := s.MigrateSpecies(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, migration)
+}
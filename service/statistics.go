@@ -0,0 +1,132 @@
+package service
+
+import (
+	"fishfishes_backend/common"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+const dateLayout string = "2006-01-02"
+
+func (s Service) GetCatchStatistics(c *gin.Context) {
+	id := c.Query("userId")
+	if len(id) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID"})
+		return
+	}
+
+	filter, err := statisticsFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	statistics, err := s.Repo.GetCatchStatistics(c, id, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	system, err := s.unitSystem(c, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	convertStatistics(statistics, system)
+
+	c.IndentedJSON(http.StatusOK, statistics)
+}
+
+// statisticsFilter reads the optional query parameters spotId, from, to and waterType.
+func statisticsFilter(c *gin.Context) (common.StatisticsFilter, error) {
+
+	filter := common.StatisticsFilter{
+		SpotId:    c.Query("spotId"),
+		WaterType: c.Query("waterType"),
+	}
+
+	if len(filter.WaterType) > 0 && !common.IsWaterType(filter.WaterType) {
+		return filter, fmt.Errorf("unknown waterType '%s'", filter.WaterType)
+	}
+
+	from, err := parseDate(c.Query("from"), false)
+	if err != nil {
+		return filter, err
+	}
+	filter.From = from
+
+	to, err := parseDate(c.Query("to"), true)
+	if err != nil {
+		return filter, err
+	}
+	filter.To = to
+
+	return filter, nil
+}
+
+// dateRange reads the optional query parameters from and to.
+func dateRange(c *gin.Context) (*time.Time, *time.Time, error) {
+	from, err := parseDate(c.Query("from"), false)
+	if err != nil {
+		return nil, nil, err
+	}
+	to, err := parseDate(c.Query("to"), true)
+	if err != nil {
+		return nil, nil, err
+	}
+	return from, to, nil
+}
+
+// parseDate parses an RFC3339 timestamp or a plain date. A plain date used as upper bound covers the whole day.
+func parseDate(value string, endOfDay bool) (*time.Time, error) {
+	if len(value) == 0 {
+		return nil, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+
+	t, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date '%s', expected %s or RFC3339", value, dateLayout)
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return &t, nil
+}
+
+// convertStatistics converts the sizes and depths of the statistics, which are aggregated in metric units,
+// to the given unit system. The depth ranges keep their metric labels.
+func convertStatistics(statistics *common.CatchStatistics, system string) {
+
+	statistics.SizeUnit = common.SizeUnitOf(system)
+	statistics.DeepUnit = common.DepthUnitOf(system)
+
+	size := func(value float64) float64 {
+		converted, _ := common.ConvertSize(value, common.UnitCentimeter, statistics.SizeUnit)
+		return converted
+	}
+	depth := func(value float64) float64 {
+		converted, _ := common.ConvertDepth(value, common.UnitMeter, statistics.DeepUnit)
+		return converted
+	}
+
+	statistics.Totals.AvgSize = size(statistics.Totals.AvgSize)
+	statistics.Totals.AvgDeep = depth(statistics.Totals.AvgDeep)
+
+	for _, groups := range [][]common.StatisticsGroup{statistics.BySpecies, statistics.ByTime, statistics.ByBait, statistics.ByDepth, statistics.ByMoonPhase} {
+		for i := range groups {
+			groups[i].AvgSize = size(groups[i].AvgSize)
+			groups[i].MaxSize = size(groups[i].MaxSize)
+			groups[i].AvgDeep = depth(groups[i].AvgDeep)
+		}
+	}
+
+	for i := range statistics.PersonalBests {
+		statistics.PersonalBests[i].Size = size(statistics.PersonalBests[i].Size)
+	}
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"fishfishes_backend/common"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+)
+
+// GetTackle returns the tackle box of the user, restricted to the items of the type of the optional query
+// parameter type.
+func (s Service) GetTackle(c *gin.Context) {
+	userId := c.Query("userId")
+	if len(userId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID"})
+		return
+	}
+
+	itemType := c.Query("type")
+	if len(itemType) > 0 && !common.IsTackleType(itemType) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "type must be rod, reel, line, leader, bait or lure"})
+		return
+	}
+
+	tackle, err := s.Repo.GetTackle(c, userId, itemType)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, tackle)
+}
+
+func (s Service) CreateTackle(c *gin.Context) {
+	userId := c.Query("userId")
+	if len(userId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID"})
+		return
+	}
+
+	item, ok := bindTackle(c)
+	if !ok {
+		return
+	}
+	item.Id = uuid.New().String()
+
+	err := s.Repo.CreateTackle(c, userId, item)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"status": "created", "itemId": item.Id})
+}
+
+// UpdateTackle replaces the item itemId of the tackle box of the user. Catches linked to the item keep the
+// text they were saved with until they are saved again.
+func (s Service) UpdateTackle(c *gin.Context) {
+	userId := c.Query("userId")
+	itemId := c.Query("itemId")
+	if len(userId) == 0 || len(itemId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID or itemId"})
+		return
+	}
+
+	item, ok := bindTackle(c)
+	if !ok {
+		return
+	}
+	item.Id = itemId
+
+	err := s.Repo.UpdateTackle(c, userId, item)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "saved"})
+}
+
+func (s Service) DeleteTackle(c *gin.Context) {
+	userId := c.Query("userId")
+	itemId := c.Query("itemId")
+	if len(userId) == 0 || len(itemId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID or itemId"})
+		return
+	}
+
+	err := s.Repo.DeleteTackle(c, userId, itemId)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "deleted"})
+}
+
+// bindTackle parses and validates the tackle item of the request body. It responds with 400 and returns
+// false, if the body is not a valid item.
+func bindTackle(c *gin.Context) (common.TackleItem, bool) {
+	var item common.TackleItem
+	if err := c.ShouldBindJSON(&item); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "JSON Parse Error", "details": err.Error()})
+		return item, false
+	}
+
+	if errs := item.Validate(); len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "fields": errs})
+		return item, false
+	}
+
+	return item, true
+}
+
+// linkTackle fills the equipment of the catches from the items of the tackle box of the user they reference.
+// field returns the path of the equipment of the i-th catch for the errors on the references to items the
+// user does not have.
+func (s Service) linkTackle(ctx context.Context, userId string, catches []common.Catch, field func(i int) string) (common.ValidationErrors, error) {
+
+	referenced := false
+	for _, catch := range catches {
+		referenced = referenced || len(catch.Equipment.ItemIds) > 0
+	}
+	if !referenced {
+		return nil, nil
+	}
+
+	items, err := s.Repo.GetTackle(ctx, userId, "")
+	if err != nil {
+		return nil, err
+	}
+	tackle := map[string]common.TackleItem{}
+	for _, item := range items {
+		tackle[item.Id] = item
+	}
+
+	var errs common.ValidationErrors
+	for i := range catches {
+		errs = append(errs, catches[i].Equipment.Link(tackle, field(i))...)
+	}
+	return errs, nil
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"fishfishes_backend/common"
+	"fishfishes_backend/trips"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"net/http"
+	"time"
+)
+
+// GetTrips returns the trips of the user, the latest first. The optional query parameters from and to
+// restrict the start of the trips.
+func (s Service) GetTrips(c *gin.Context) {
+	userId := c.Query("userId")
+	if len(userId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID"})
+		return
+	}
+
+	from, to, err := dateRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := s.Repo.GetTrips(c, userId, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, result)
+}
+
+func (s Service) GetTripByID(c *gin.Context) {
+	userId := c.Query("userId")
+	tripId := c.Query("tripId")
+	if len(userId) == 0 || len(tripId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID or tripId"})
+		return
+	}
+
+	trip, err := s.Repo.GetTrip(c, userId, tripId)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, trip)
+}
+
+// GetTripSummary returns the totals of the catches of a trip of the user, overall and by species and spot.
+func (s Service) GetTripSummary(c *gin.Context) {
+	userId := c.Query("userId")
+	tripId := c.Query("tripId")
+	if len(userId) == 0 || len(tripId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID or tripId"})
+		return
+	}
+
+	trip, err := s.Repo.GetTrip(c, userId, tripId)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	spots, err := s.Repo.GetAllSpots(c, userId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, trips.Summarize(*trip, *spots, time.Now()))
+}
+
+func (s Service) CreateTrip(c *gin.Context) {
+	userId := c.Query("userId")
+	if len(userId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID"})
+		return
+	}
+
+	trip, ok := s.bindTrip(c, userId)
+	if !ok {
+		return
+	}
+	trip.Id = uuid.New().String()
+
+	err := s.Repo.CreateTrip(c, userId, trip)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"status": "created", "tripId": trip.Id})
+}
+
+func (s Service) UpdateTrip(c *gin.Context) {
+	userId := c.Query("userId")
+	tripId := c.Query("tripId")
+	if len(userId) == 0 || len(tripId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID or tripId"})
+		return
+	}
+
+	trip, ok := s.bindTrip(c, userId)
+	if !ok {
+		return
+	}
+	trip.Id = tripId
+
+	err := s.Repo.UpdateTrip(c, userId, trip)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "saved"})
+}
+
+// DeleteTrip removes a trip of the user. Its catches stay at their spots.
+func (s Service) DeleteTrip(c *gin.Context) {
+	userId := c.Query("userId")
+	tripId := c.Query("tripId")
+	if len(userId) == 0 || len(tripId) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Found no userID or tripId"})
+		return
+	}
+
+	err := s.Repo.DeleteTrip(c, userId, tripId)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "deleted"})
+}
+
+// bindTrip parses and validates the trip of the request body and resolves its spots, catches and companions.
+// It responds with an error and returns false, if the body is not a valid trip of the user.
+func (s Service) bindTrip(c *gin.Context, userId string) (common.Trip, bool) {
+	var trip common.Trip
+	if err := c.ShouldBindJSON(&trip); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "JSON Parse Error", "details": err.Error()})
+		return trip, false
+	}
+
+	errs := trip.Validate()
+
+	spots, err := s.Repo.GetAllSpots(c, userId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return trip, false
+	}
+	errs = append(errs, trips.Resolve(&trip, *spots)...)
+
+	// only friends can come along; the error is the same whether an id exists or not
+	friends, err := s.friendIds(c, userId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return trip, false
+	}
+	isFriend := map[string]bool{}
+	for _, friend := range friends {
+		isFriend[friend] = true
+	}
+	for i, companion := range trip.Companions {
+		if !isFriend[companion] {
+			errs = append(errs, common.FieldError{Field: fmt.Sprintf("companions[%d]", i), Code: common.ErrorNotFriend, Message: "companions must be friends of the user"})
+		}
+	}
+
+	if len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Validation failed", "fields": errs})
+		return trip, false
+	}
+
+	// lists are stored empty rather than null, so that blank trips read like the others
+	if trip.SpotIds == nil {
+		trip.SpotIds = []string{}
+	}
+	if trip.Companions == nil {
+		trip.Companions = []string{}
+	}
+	if trip.CatchIds == nil {
+		trip.CatchIds = []string{}
+	}
+
+	return trip, true
+}
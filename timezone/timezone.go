@@ -0,0 +1,93 @@
+// Package timezone resolves the time zone of coordinates offline from boundary data.
+//
+// The embedded boundaries.geojson holds coarse outlines of the time zones most of our users fish in: Central,
+// Western and Eastern Europe, Kaliningrad and the zones of the contiguous United States. Coordinates outside of
+// all outlines, for example on the open sea or in any other country, resolve to the nautical time zone of their
+// longitude. That fallback is an offset only: it has no daylight saving time and no half hour zones, so local
+// times there can be off by an hour or more, which shifts the derived parts of the day close to sunrise and
+// sunset.
+//
+// A detailed file in the format of the timezone-boundary-builder releases (a FeatureCollection with a "tzid"
+// property per feature) can be loaded instead with LoadFile.
+package timezone
+
+import (
+	"bytes"
+	_ "embed"
+	"fishfishes_backend/common"
+	"fishfishes_backend/common/geo"
+	"fmt"
+	"github.com/pkg/errors"
+	"io"
+	"math"
+	"os"
+	"time"
+	_ "time/tzdata" // the zones must resolve on systems without zoneinfo, too
+)
+
+//go:embed boundaries.geojson
+var boundaries []byte
+
+type zone struct {
+	location *time.Location
+	area     geo.MultiPolygon
+}
+
+// Resolver maps coordinates to time zones. The first zone containing the coordinates wins.
+type Resolver struct {
+	zones []zone
+}
+
+// NewResolver creates a resolver from the embedded boundary data.
+func NewResolver() (*Resolver, error) {
+	return read(bytes.NewReader(boundaries))
+}
+
+// LoadFile creates a resolver from the boundary file at the given path.
+func LoadFile(path string) (*Resolver, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open the time zone boundaries")
+	}
+	defer file.Close()
+
+	return read(file)
+}
+
+func read(r io.Reader) (*Resolver, error) {
+	features, err := geo.ReadAreas(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read the time zone boundaries")
+	}
+
+	resolver := &Resolver{}
+	for _, feature := range features {
+		tzid := feature.Property("tzid")
+		location, err := time.LoadLocation(tzid)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unknown time zone '%s' in the boundaries", tzid)
+		}
+		resolver.zones = append(resolver.zones, zone{location: location, area: feature.Geometry})
+	}
+
+	return resolver, nil
+}
+
+// Location returns the time zone of the coordinates.
+func (r *Resolver) Location(coordinates common.Coordinates) *time.Location {
+	for _, z := range r.zones {
+		if z.area.Contains(coordinates) {
+			return z.location
+		}
+	}
+	return nautical(coordinates.Longitude)
+}
+
+// nautical returns the fixed zone of 15 degrees longitude the given longitude falls into.
+func nautical(longitude float64) *time.Location {
+	offset := int(math.Round(longitude / 15))
+	if offset == 0 {
+		return time.UTC
+	}
+	return time.FixedZone(fmt.Sprintf("UTC%+d", offset), offset*3600)
+}
@@ -0,0 +1,105 @@
+package timezone
+
+import (
+	"fishfishes_backend/common"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestLocation(t *testing.T) {
+
+	t.Parallel()
+
+	resolver, err := NewResolver()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	type test struct {
+		coordinates common.Coordinates
+		expected    string
+	}
+
+	cases := map[string]test{
+		"berlin":         {coordinates: common.Coordinates{Latitude: 52.52, Longitude: 13.40}, expected: "Europe/Berlin"},
+		"madrid":         {coordinates: common.Coordinates{Latitude: 40.42, Longitude: -3.70}, expected: "Europe/Berlin"},
+		"palermo":        {coordinates: common.Coordinates{Latitude: 38.12, Longitude: 13.36}, expected: "Europe/Berlin"},
+		"stockholm":      {coordinates: common.Coordinates{Latitude: 59.33, Longitude: 18.07}, expected: "Europe/Berlin"},
+		"tromsø":         {coordinates: common.Coordinates{Latitude: 69.65, Longitude: 18.96}, expected: "Europe/Berlin"},
+		"lisbon":         {coordinates: common.Coordinates{Latitude: 38.72, Longitude: -9.14}, expected: "Europe/Lisbon"},
+		"london":         {coordinates: common.Coordinates{Latitude: 51.51, Longitude: -0.13}, expected: "Europe/London"},
+		"dublin":         {coordinates: common.Coordinates{Latitude: 53.35, Longitude: -6.26}, expected: "Europe/London"},
+		"helsinki":       {coordinates: common.Coordinates{Latitude: 60.17, Longitude: 24.94}, expected: "Europe/Helsinki"},
+		"riga":           {coordinates: common.Coordinates{Latitude: 56.95, Longitude: 24.11}, expected: "Europe/Helsinki"},
+		"athens":         {coordinates: common.Coordinates{Latitude: 37.98, Longitude: 23.73}, expected: "Europe/Helsinki"},
+		"bucharest":      {coordinates: common.Coordinates{Latitude: 44.43, Longitude: 26.10}, expected: "Europe/Helsinki"},
+		"kaliningrad":    {coordinates: common.Coordinates{Latitude: 54.71, Longitude: 20.51}, expected: "Europe/Kaliningrad"},
+		"new york":       {coordinates: common.Coordinates{Latitude: 40.71, Longitude: -74.01}, expected: "America/New_York"},
+		"tampa":          {coordinates: common.Coordinates{Latitude: 27.95, Longitude: -82.46}, expected: "America/New_York"},
+		"chicago":        {coordinates: common.Coordinates{Latitude: 41.88, Longitude: -87.63}, expected: "America/Chicago"},
+		"denver":         {coordinates: common.Coordinates{Latitude: 39.74, Longitude: -104.99}, expected: "America/Denver"},
+		"phoenix":        {coordinates: common.Coordinates{Latitude: 33.45, Longitude: -112.07}, expected: "America/Phoenix"},
+		"los angeles":    {coordinates: common.Coordinates{Latitude: 34.05, Longitude: -118.24}, expected: "America/Los_Angeles"},
+		"tunis":          {coordinates: common.Coordinates{Latitude: 36.81, Longitude: 10.18}, expected: "UTC+1"},
+		"tokyo":          {coordinates: common.Coordinates{Latitude: 35.68, Longitude: 139.69}, expected: "UTC+9"},
+		"mid atlantic":   {coordinates: common.Coordinates{Latitude: 35.0, Longitude: -30.0}, expected: "UTC-2"},
+		"gulf of guinea": {coordinates: common.Coordinates{Latitude: 0.0, Longitude: 3.0}, expected: "UTC"},
+	}
+
+	for name, tc := range cases {
+
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result := resolver.Location(tc.coordinates)
+			assert.Equal(t, tc.expected, result.String())
+		})
+	}
+}
+
+func TestNauticalOffset(t *testing.T) {
+	_, offset := time.Now().In(nautical(-74.0)).Zone()
+	assert.Equal(t, -5*3600, offset)
+}
+
+func TestNauticalFallback(t *testing.T) {
+
+	t.Parallel()
+
+	resolver, err := NewResolver()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// outside of the outlines there is neither daylight saving time nor a half hour offset
+	sydney := resolver.Location(common.Coordinates{Latitude: -33.87, Longitude: 151.21})
+	_, offset := time.Date(2023, 1, 15, 12, 0, 0, 0, time.UTC).In(sydney).Zone()
+	assert.Equal(t, 10*3600, offset)
+
+	delhi := resolver.Location(common.Coordinates{Latitude: 28.61, Longitude: 77.21})
+	_, offset = time.Date(2023, 1, 15, 12, 0, 0, 0, time.UTC).In(delhi).Zone()
+	assert.Equal(t, 5*3600, offset)
+}
+
+func TestLoadFile(t *testing.T) {
+
+	t.Parallel()
+
+	resolver, err := LoadFile("testdata/india.geojson")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	delhi := resolver.Location(common.Coordinates{Latitude: 28.61, Longitude: 77.21})
+	assert.Equal(t, "Asia/Kolkata", delhi.String())
+	_, offset := time.Date(2023, 1, 15, 12, 0, 0, 0, time.UTC).In(delhi).Zone()
+	assert.Equal(t, 5*3600+1800, offset)
+
+	assert.Equal(t, "UTC+1", resolver.Location(common.Coordinates{Latitude: 52.52, Longitude: 13.40}).String())
+
+	_, err = LoadFile("testdata/missing.geojson")
+	assert.Error(t, err)
+}
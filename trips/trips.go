@@ -0,0 +1,116 @@
+// Package trips relates the trips of anglers to their spots and catches and sums them up.
+package trips
+
+import (
+	"fishfishes_backend/common"
+	"fishfishes_backend/common/utils"
+	"fishfishes_backend/records"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Resolve checks that the spots and catches the trip refers to are among the spots of the user and adds the
+// spots of its catches to the visited spots. It returns an error for each unknown reference.
+func Resolve(trip *common.Trip, spots []common.Fish_spot) common.ValidationErrors {
+
+	spotOf := map[string]string{}
+	known := map[string]bool{}
+	for _, spot := range spots {
+		known[spot.Id] = true
+		for _, catch := range spot.Catches {
+			spotOf[catch.Id] = spot.Id
+		}
+	}
+
+	var errs common.ValidationErrors
+	visited := map[string]bool{}
+	for i, id := range trip.SpotIds {
+		if !known[id] {
+			errs = append(errs, common.FieldError{Field: fmt.Sprintf("spotIds[%d]", i), Code: common.ErrorUnknownSpot, Message: fmt.Sprintf("spot '%s' does not exist", id)})
+		}
+		visited[id] = true
+	}
+	for i, id := range trip.CatchIds {
+		spotId, ok := spotOf[id]
+		if !ok {
+			errs = append(errs, common.FieldError{Field: fmt.Sprintf("catchIds[%d]", i), Code: common.ErrorUnknownCatch, Message: fmt.Sprintf("catch '%s' does not exist", id)})
+			continue
+		}
+		if !visited[spotId] {
+			trip.SpotIds = append(trip.SpotIds, spotId)
+			visited[spotId] = true
+		}
+	}
+	return errs
+}
+
+// Summarize sums up the catches of the trip among the spots of the user. Catches and spots deleted since
+// they were added to the trip are left out.
+func Summarize(trip common.Trip, spots []common.Fish_spot, now time.Time) common.TripSummary {
+
+	summary := common.TripSummary{TripId: trip.Id, Hours: utils.ScaleHalfUp(trip.Hours(now), 2)}
+
+	inTrip := map[string]bool{}
+	for _, id := range trip.CatchIds {
+		inTrip[id] = true
+	}
+	visited := map[string]bool{}
+	for _, id := range trip.SpotIds {
+		visited[id] = true
+	}
+
+	bySpecies := map[string]*common.TripGroup{}
+	bySpot := map[string]*common.TripGroup{}
+	for _, spot := range spots {
+		if visited[spot.Id] {
+			bySpot[spot.Id] = &common.TripGroup{Key: spot.Id, Name: spot.Marker.Title}
+		}
+		for _, catch := range spot.Catches {
+			if !inTrip[catch.Id] {
+				continue
+			}
+			summary.Catches++
+			summary.Fish += catch.Number
+
+			add(bySpecies, records.SpeciesKey(catch), catch.Fish, catch.Number)
+			add(bySpot, spot.Id, spot.Marker.Title, catch.Number)
+		}
+	}
+
+	summary.Blank = summary.Catches == 0
+	if summary.Hours > 0 {
+		summary.FishPerHour = utils.ScaleHalfUp(float64(summary.Fish)/trip.Hours(now), 2)
+	}
+	summary.BySpecies = sorted(bySpecies)
+	summary.BySpot = sorted(bySpot)
+	return summary
+}
+
+func add(groups map[string]*common.TripGroup, key string, name string, fish int) {
+	group, ok := groups[key]
+	if !ok {
+		group = &common.TripGroup{Key: key, Name: name}
+		groups[key] = group
+	}
+	group.Catches++
+	group.Fish += fish
+}
+
+// sorted returns the groups with the most fish first, groups with as many fish by name.
+func sorted(groups map[string]*common.TripGroup) []common.TripGroup {
+	result := []common.TripGroup{}
+	for _, group := range groups {
+		result = append(result, *group)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Fish != result[j].Fish {
+			return result[i].Fish > result[j].Fish
+		}
+		if result[i].Name != result[j].Name {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
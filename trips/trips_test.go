@@ -0,0 +1,78 @@
+package trips
+
+import (
+	"fishfishes_backend/common"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+var spots = []common.Fish_spot{
+	{Id: "lake", Marker: common.Marker{Title: "Lake"}, Catches: []common.Catch{
+		{Id: "c1", Fish: "Pike", SpeciesId: "northern-pike", Number: 1},
+		{Id: "c2", Fish: "Perch", SpeciesId: "european-perch", Number: 4},
+		{Id: "c3", Fish: "Perch", SpeciesId: "european-perch", Number: 2},
+	}},
+	{Id: "river", Marker: common.Marker{Title: "River"}, Catches: []common.Catch{
+		{Id: "c4", Fish: "Grayling", Number: 1},
+	}},
+	{Id: "pond", Marker: common.Marker{Title: "Pond"}},
+}
+
+func TestResolve(t *testing.T) {
+
+	t.Parallel()
+
+	trip := common.Trip{SpotIds: []string{"pond", "sea"}, CatchIds: []string{"c1", "c4", "c9", "c2"}}
+	errs := Resolve(&trip, spots)
+
+	var fields []string
+	for _, e := range errs {
+		fields = append(fields, e.Field+":"+e.Code)
+	}
+	assert.Equal(t, []string{"spotIds[1]:unknown_spot", "catchIds[2]:unknown_catch"}, fields)
+	assert.Equal(t, []string{"pond", "sea", "lake", "river"}, trip.SpotIds)
+}
+
+func TestSummarize(t *testing.T) {
+
+	t.Parallel()
+
+	start := time.Date(2023, 6, 3, 5, 0, 0, 0, time.UTC)
+	end := start.Add(4 * time.Hour)
+	trip := common.Trip{Id: "t", Start: start, End: &end, SpotIds: []string{"lake", "river", "pond"}, CatchIds: []string{"c1", "c2", "c4", "deleted"}}
+
+	summary := Summarize(trip, spots, time.Now())
+	assert.Equal(t, common.TripSummary{
+		TripId:      "t",
+		Hours:       4,
+		Catches:     3,
+		Fish:        6,
+		FishPerHour: 1.5,
+		BySpecies: []common.TripGroup{
+			{Key: "european-perch", Name: "Perch", Catches: 1, Fish: 4},
+			{Key: "Grayling", Name: "Grayling", Catches: 1, Fish: 1},
+			{Key: "northern-pike", Name: "Pike", Catches: 1, Fish: 1},
+		},
+		BySpot: []common.TripGroup{
+			{Key: "lake", Name: "Lake", Catches: 2, Fish: 5},
+			{Key: "river", Name: "River", Catches: 1, Fish: 1},
+			{Key: "pond", Name: "Pond"},
+		},
+	}, summary)
+}
+
+func TestSummarizeBlank(t *testing.T) {
+
+	t.Parallel()
+
+	start := time.Date(2023, 6, 3, 5, 0, 0, 0, time.UTC)
+	trip := common.Trip{Id: "t", Start: start, SpotIds: []string{"pond"}}
+
+	summary := Summarize(trip, spots, start.Add(90*time.Minute))
+	assert.True(t, summary.Blank)
+	assert.Equal(t, 1.5, summary.Hours)
+	assert.Equal(t, 0.0, summary.FishPerHour)
+	assert.Empty(t, summary.BySpecies)
+	assert.Equal(t, []common.TripGroup{{Key: "pond", Name: "Pond"}}, summary.BySpot)
+}
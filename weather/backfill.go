@@ -0,0 +1,90 @@
+package weather
+
+import (
+	"context"
+	"fishfishes_backend/common"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	DefaultBackfillInterval  time.Duration = 10 * time.Minute
+	DefaultBackfillBatchSize int           = 100
+)
+
+// Store is the storage of catches the backfill worker reads from and writes to.
+type Store interface {
+	GetCatchesWithoutWeather(ctx context.Context, limit int) ([]common.CatchReference, error)
+	SetCatchWeather(ctx context.Context, ref common.CatchReference, weather common.Weather) error
+}
+
+// BackfillWorker periodically looks up the conditions of stored catches which do not have any yet.
+type BackfillWorker struct {
+	Store     Store
+	Provider  Provider
+	Logger    *zap.SugaredLogger
+	Interval  time.Duration
+	BatchSize int
+}
+
+func NewBackfillWorker(store Store, provider Provider, logger *zap.SugaredLogger) BackfillWorker {
+	return BackfillWorker{
+		Store:     store,
+		Provider:  provider,
+		Logger:    logger,
+		Interval:  DefaultBackfillInterval,
+		BatchSize: DefaultBackfillBatchSize,
+	}
+}
+
+// Run backfills until the context is cancelled. It is meant to be started as a goroutine.
+func (w BackfillWorker) Run(ctx context.Context) {
+
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			count, err := w.Backfill(ctx)
+			if err != nil {
+				w.Logger.Errorf("weather backfill failed: %s", err.Error())
+				break
+			}
+			if count < w.BatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Backfill processes one batch of catches and returns the number of catches it has processed.
+// Catches without available conditions are marked as unavailable, so they are not looked up again.
+func (w BackfillWorker) Backfill(ctx context.Context) (int, error) {
+
+	refs, err := w.Store.GetCatchesWithoutWeather(ctx, w.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, ref := range refs {
+		weather, err := w.Provider.Conditions(ctx, ref.Coordinates, ref.At)
+		if err == ErrNotAvailable {
+			weather = &common.Weather{ObservedAt: ref.At, Unavailable: true}
+		} else if err != nil {
+			return 0, err
+		}
+
+		err = w.Store.SetCatchWeather(ctx, ref, *weather)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(refs), nil
+}
@@ -0,0 +1,85 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fishfishes_backend/common"
+	"fishfishes_backend/common/geo"
+	"github.com/pkg/errors"
+	"math"
+	"os"
+	"time"
+)
+
+const (
+	DefaultMaxDistance float64       = 50000         // meters between the catch and an observation
+	DefaultMaxAge      time.Duration = 3 * time.Hour // time between the catch and an observation
+)
+
+// Observation is a single measurement as stored in the weather file.
+type Observation struct {
+	Coordinates common.Coordinates `json:"coordinates"`
+	Weather     common.Weather     `json:"weather"`
+}
+
+// FileProvider answers lookups from a local JSON file containing a list of observations.
+// The nearest observation within MaxDistance and MaxAge is returned.
+type FileProvider struct {
+	Observations []Observation
+	MaxDistance  float64
+	MaxAge       time.Duration
+}
+
+// NewFileProvider reads the observations from the JSON file at the given path.
+func NewFileProvider(path string) (*FileProvider, error) {
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read weather file '%s'", path)
+	}
+
+	var observations []Observation
+	err = json.Unmarshal(content, &observations)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not parse weather file '%s'", path)
+	}
+
+	return &FileProvider{
+		Observations: observations,
+		MaxDistance:  DefaultMaxDistance,
+		MaxAge:       DefaultMaxAge,
+	}, nil
+}
+
+func (p *FileProvider) Conditions(_ context.Context, coordinates common.Coordinates, at time.Time) (*common.Weather, error) {
+
+	var nearest *common.Weather
+	best := math.MaxFloat64
+
+	for i := range p.Observations {
+		observation := &p.Observations[i]
+
+		distance := geo.Distance(coordinates, observation.Coordinates)
+		age := at.Sub(observation.Weather.ObservedAt)
+		if age < 0 {
+			age = -age
+		}
+		if distance > p.MaxDistance || age > p.MaxAge {
+			continue
+		}
+
+		// both deviations relative to their maximum, so distance and age count equally
+		score := distance/p.MaxDistance + float64(age)/float64(p.MaxAge)
+		if score < best {
+			best = score
+			nearest = &observation.Weather
+		}
+	}
+
+	if nearest == nil {
+		return nil, ErrNotAvailable
+	}
+
+	weather := *nearest
+	return &weather, nil
+}
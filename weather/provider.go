@@ -0,0 +1,34 @@
+// Package weather provides the lookup of weather conditions for catches and a worker to backfill stored catches.
+package weather
+
+import (
+	"context"
+	"fishfishes_backend/common"
+	"github.com/pkg/errors"
+	"time"
+)
+
+// ErrNotAvailable is returned by a Provider if it has no conditions for the requested place and time.
+var ErrNotAvailable = errors.New("weather conditions not available")
+
+// Provider looks up the weather conditions at the given coordinates and time.
+type Provider interface {
+	Conditions(ctx context.Context, coordinates common.Coordinates, at time.Time) (*common.Weather, error)
+}
+
+// StubProvider returns the same conditions for every place and time. It is meant for testing.
+type StubProvider struct {
+	Weather common.Weather
+}
+
+func NewStubProvider(weather common.Weather) StubProvider {
+	return StubProvider{
+		Weather: weather,
+	}
+}
+
+func (p StubProvider) Conditions(_ context.Context, _ common.Coordinates, at time.Time) (*common.Weather, error) {
+	weather := p.Weather
+	weather.ObservedAt = at
+	return &weather, nil
+}
@@ -0,0 +1,131 @@
+package weather
+
+import (
+	"context"
+	"fishfishes_backend/common"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+var noon = time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func TestFileProviderConditions(t *testing.T) {
+
+	t.Parallel()
+
+	provider := &FileProvider{
+		Observations: []Observation{
+			{
+				Coordinates: common.Coordinates{Latitude: 52.0, Longitude: 13.0},
+				Weather:     common.Weather{ObservedAt: noon, Pressure: 1010},
+			},
+			{
+				Coordinates: common.Coordinates{Latitude: 52.1, Longitude: 13.0},
+				Weather:     common.Weather{ObservedAt: noon.Add(-2 * time.Hour), Pressure: 1020},
+			},
+		},
+		MaxDistance: DefaultMaxDistance,
+		MaxAge:      DefaultMaxAge,
+	}
+
+	type test struct {
+		coordinates common.Coordinates
+		at          time.Time
+		expected    float64
+		err         error
+	}
+
+	cases := map[string]test{
+		"nearest in time and place": {
+			coordinates: common.Coordinates{Latitude: 52.0, Longitude: 13.0},
+			at:          noon,
+			expected:    1010,
+		},
+		"nearer in time wins over slightly nearer in place": {
+			coordinates: common.Coordinates{Latitude: 52.09, Longitude: 13.0},
+			at:          noon.Add(-2 * time.Hour),
+			expected:    1020,
+		},
+		"too far away": {
+			coordinates: common.Coordinates{Latitude: 48.0, Longitude: 11.0},
+			at:          noon,
+			err:         ErrNotAvailable,
+		},
+		"too old": {
+			coordinates: common.Coordinates{Latitude: 52.0, Longitude: 13.0},
+			at:          noon.Add(24 * time.Hour),
+			err:         ErrNotAvailable,
+		},
+	}
+
+	for name, tc := range cases {
+
+		tc := tc
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := provider.Conditions(context.Background(), tc.coordinates, tc.at)
+			if tc.err != nil {
+				assert.Equal(t, tc.err, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, result.Pressure)
+		})
+	}
+}
+
+type memoryStore struct {
+	refs    []common.CatchReference
+	weather map[int]common.Weather
+}
+
+func (s *memoryStore) GetCatchesWithoutWeather(_ context.Context, limit int) ([]common.CatchReference, error) {
+	var refs []common.CatchReference
+	for _, ref := range s.refs {
+		if _, ok := s.weather[ref.Index]; !ok && len(refs) < limit {
+			refs = append(refs, ref)
+		}
+	}
+	return refs, nil
+}
+
+func (s *memoryStore) SetCatchWeather(_ context.Context, ref common.CatchReference, weather common.Weather) error {
+	s.weather[ref.Index] = weather
+	return nil
+}
+
+func TestBackfill(t *testing.T) {
+
+	store := &memoryStore{
+		refs: []common.CatchReference{
+			{SpotEntityId: "a", Index: 0, Coordinates: common.Coordinates{Latitude: 52.0, Longitude: 13.0}, At: noon},
+			{SpotEntityId: "a", Index: 1, Coordinates: common.Coordinates{Latitude: 0, Longitude: 0}, At: noon},
+		},
+		weather: map[int]common.Weather{},
+	}
+	provider := &FileProvider{
+		Observations: []Observation{
+			{
+				Coordinates: common.Coordinates{Latitude: 52.0, Longitude: 13.0},
+				Weather:     common.Weather{ObservedAt: noon, Temperature: 18.5},
+			},
+		},
+		MaxDistance: DefaultMaxDistance,
+		MaxAge:      DefaultMaxAge,
+	}
+
+	worker := NewBackfillWorker(store, provider, nil)
+
+	count, err := worker.Backfill(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, 18.5, store.weather[0].Temperature)
+	assert.True(t, store.weather[1].Unavailable)
+
+	count, err = worker.Backfill(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+}